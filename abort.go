@@ -0,0 +1,225 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotUserAbort is returned when the TCAP is not TC-U-ABORT.
+var ErrNotUserAbort = errors.New("tcap: not a TC-U-ABORT")
+
+// ErrInvalidAbortSource is returned when the abort-source in ABRT is missing or malformed.
+var ErrInvalidAbortSource = errors.New("tcap: invalid abort source")
+
+// AbortSource is the abort-source in ABRT, i.e., AbortDialogueServiceUser or
+// AbortDialogueServiceProvider.
+type AbortSource int
+
+// String returns the name of AbortSource as in Q.773.
+func (s AbortSource) String() string {
+	switch int(s) {
+	case AbortDialogueServiceUser:
+		return "dialogue-service-user"
+	case AbortDialogueServiceProvider:
+		return "dialogue-service-provider"
+	}
+	return fmt.Sprintf("%d", int(s))
+}
+
+// valid reports whether the AbortSource is one of the values defined in Q.773.
+func (s AbortSource) valid() bool {
+	return s == AbortSource(AbortDialogueServiceUser) || s == AbortSource(AbortDialogueServiceProvider)
+}
+
+// NewABRTWithSource returns a new ABRT(Dialogue Abort) of the typed abort source.
+func NewABRTWithSource(src AbortSource, userinfo ...*IE) *DialoguePDU {
+	return NewABRT(uint8(src), userinfo...)
+}
+
+// SetAbortSource sets the abort-source of the ABRT, and updates the lengths.
+func (d *DialoguePDU) SetAbortSource(src AbortSource) {
+	d.AbortSource = &IE{
+		Tag:   NewContextSpecificPrimitiveTag(0),
+		Value: []byte{uint8(src)},
+	}
+	d.SetLength()
+}
+
+// Source returns the abort-source of the ABRT.
+func (d *DialoguePDU) Source() (AbortSource, error) {
+	if d.Type.Code() != ABRT || d.AbortSource == nil {
+		return 0, ErrInvalidAbortSource
+	}
+	// abort-source is [0] IMPLICIT INTEGER.
+	v, err := (&IE{Tag: NewUniversalPrimitiveTag(2), Value: d.AbortSource.Value}).Int64()
+	if err != nil || !AbortSource(v).valid() {
+		return 0, ErrInvalidAbortSource
+	}
+	return AbortSource(v), nil
+}
+
+// UserAbort is the decoded TC-U-ABORT.
+type UserAbort struct {
+	DestTransactionID uint32
+
+	// AbortSource is AbortDialogueServiceUser or AbortDialogueServiceProvider in ABRT.
+	// It is AbortDialogueServiceUser if the Abort has no ABRT, e.g., the dialogue rejected with AARE.
+	AbortSource int
+
+	// Externals are the ones in the user information of the DialoguePDU, if any.
+	Externals []*External
+
+	// DialoguePDU is the ABRT or AARE in Dialogue Portion, or nil if absent.
+	DialoguePDU *DialoguePDU
+}
+
+// NewUserAbortWithSource creates a new TCAP of type Transaction=Abort with ABRT in Dialogue Portion,
+// of the abort source (i.e., AbortDialogueServiceUser or AbortDialogueServiceProvider) and
+// the Externals in the user information, e.g., the one carrying MAP-UserAbortInfo.
+func NewUserAbortWithSource(dtid uint32, src int, externals ...*External) (*TCAP, error) {
+	abrt := NewABRTWithSource(AbortSource(src))
+	if err := abrt.SetExternals(externals...); err != nil {
+		return nil, err
+	}
+	return NewAbortMessage(WithDTID(dtid), WithDialoguePDU(abrt)), nil
+}
+
+// UserAbort returns the decoded TC-U-ABORT, or ErrNotUserAbort if the TCAP is not an Abort or
+// has the P-Abort Cause.
+func (t *TCAP) UserAbort() (*UserAbort, error) {
+	tx := t.Transaction
+	if tx == nil || tx.Type.Code() != Abort {
+		return nil, ErrNotUserAbort
+	}
+	if _, ok := tx.PAbort(); ok {
+		return nil, ErrNotUserAbort
+	}
+
+	u := &UserAbort{AbortSource: AbortDialogueServiceUser}
+	if tx.DestTransactionID != nil {
+		u.DestTransactionID = t.DTID()
+	}
+	if t.Dialogue == nil || t.Dialogue.DialoguePDU == nil {
+		return u, nil
+	}
+
+	pdu := t.Dialogue.DialoguePDU
+	u.DialoguePDU = pdu
+	if pdu.Type.Code() == ABRT {
+		src, err := pdu.Source()
+		if err != nil {
+			return nil, err
+		}
+		u.AbortSource = int(src)
+	}
+
+	externals, err := pdu.Externals()
+	if err != nil {
+		return nil, err
+	}
+	u.Externals = externals
+	return u, nil
+}
+
+// DialogueDiagnosticKind is the kind of DialogueDiagnostic.
+type DialogueDiagnosticKind int
+
+// DialogueDiagnosticKind definitions.
+const (
+	// DialogueUserAbort is the ABRT of dialogue-service-user, i.e., TC-U-ABORT by the peer TC-user.
+	DialogueUserAbort DialogueDiagnosticKind = iota
+
+	// DialogueProviderAbort is the ABRT of dialogue-service-provider, i.e., the dialogue aborted by
+	// the peer TC, e.g., no common dialogue portion.
+	DialogueProviderAbort
+
+	// DialogueRejected is the AARE rejecting the dialogue for the reason other than
+	// application-context-name-not-supported.
+	DialogueRejected
+
+	// DialogueVersionFallback is the AARE rejecting the dialogue with
+	// application-context-name-not-supported, which triggers the fallback to the lower version
+	// (see FallbackBegin).
+	DialogueVersionFallback
+)
+
+// String returns the name of DialogueDiagnosticKind.
+func (k DialogueDiagnosticKind) String() string {
+	switch k {
+	case DialogueUserAbort:
+		return "UserAbort"
+	case DialogueProviderAbort:
+		return "ProviderAbort"
+	case DialogueRejected:
+		return "Rejected"
+	case DialogueVersionFallback:
+		return "VersionFallback"
+	}
+	return fmt.Sprintf("%d", int(k))
+}
+
+// DialogueDiagnostic is the decoded ABRT or rejecting AARE that terminates a dialogue.
+type DialogueDiagnostic struct {
+	Kind DialogueDiagnosticKind
+
+	// AbortSource is set for ABRT.
+	AbortSource AbortSource
+
+	// Diagnostic is the result-source-diagnostic set for AARE.
+	Diagnostic SourceDiagnostic
+
+	// ApplicationContext is the one in AARE, e.g., the alternative to fall back to, or nil for ABRT.
+	ApplicationContext ApplicationContext
+
+	// DialoguePDU is the ABRT or AARE decoded.
+	DialoguePDU *DialoguePDU
+}
+
+// DialogueDiagnostic returns the DialogueDiagnostic of the TCAP, which is an Abort carrying ABRT or
+// AARE in Dialogue Portion, or an End rejecting the dialogue with AARE.
+// It returns false if the TCAP has no such Dialogue Portion, e.g., TC-P-ABORT or the AARE accepting.
+func (t *TCAP) DialogueDiagnostic() (*DialogueDiagnostic, bool) {
+	tx := t.Transaction
+	if tx == nil || t.Dialogue == nil || t.Dialogue.DialoguePDU == nil {
+		return nil, false
+	}
+	pdu := t.Dialogue.DialoguePDU
+	mtype := tx.Type.Code()
+
+	switch pdu.Type.Code() {
+	case ABRT:
+		src, err := pdu.Source()
+		if err != nil || mtype != Abort {
+			return nil, false
+		}
+		d := &DialogueDiagnostic{Kind: DialogueUserAbort, AbortSource: src, DialoguePDU: pdu}
+		if src == AbortSource(AbortDialogueServiceProvider) {
+			d.Kind = DialogueProviderAbort
+		}
+		return d, true
+	case AARE:
+		if mtype != Abort && mtype != End {
+			return nil, false
+		}
+		if res, err := pdu.AssociateResult(); err != nil || res != AssociateResult(RejectPerm) {
+			return nil, false
+		}
+		diag, err := pdu.SourceDiagnostic()
+		if err != nil {
+			return nil, false
+		}
+		d := &DialogueDiagnostic{Kind: DialogueRejected, Diagnostic: diag, DialoguePDU: pdu}
+		if diag == (SourceDiagnostic{DialogueServiceUser, ApplicationContextNameNotSupported}) {
+			d.Kind = DialogueVersionFallback
+		}
+		if ac, err := pdu.ApplicationContext(); err == nil {
+			d.ApplicationContext = ac
+		}
+		return d, true
+	}
+	return nil, false
+}
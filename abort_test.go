@@ -0,0 +1,143 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestUserAbortWithSource(t *testing.T) {
+	b, err := tcap.NewMAPUserAbortInfo(tcap.ResourceUnavailable, 1).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ext, err := tcap.ParseExternal(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := tcap.NewUserAbortWithSource(0x11111111, tcap.AbortDialogueServiceProvider, ext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err = m.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := tcap.Parse(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := parsed.UserAbort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "DTID", u.DestTransactionID, uint32(0x11111111))
+	verify.Values(t, "AbortSource", u.AbortSource, tcap.AbortDialogueServiceProvider)
+	verify.Values(t, "Externals", u.Externals, []*tcap.External{ext})
+
+	abortInfo, err := u.DialoguePDU.MAPUserAbortInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "MAP-UserAbortInfo", abortInfo, &tcap.MAPUserAbortInfo{Choice: tcap.ResourceUnavailable, Reason: 1})
+
+	b, err = tcap.NewAbortMessage(tcap.WithDTID(1), tcap.WithAbortCause(tcap.ResourceLimitation)).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed, err = tcap.Parse(b); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parsed.UserAbort(); !errors.Is(err, tcap.ErrNotUserAbort) {
+		t.Errorf("P-Abort: got %v, want %v", err, tcap.ErrNotUserAbort)
+	}
+}
+
+func TestAbortSource(t *testing.T) {
+	abrt := tcap.NewABRTWithSource(tcap.AbortSource(tcap.AbortDialogueServiceProvider))
+	b, err := abrt.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := tcap.ParseDialoguePDU(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := parsed.Source()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "source", src.String(), "dialogue-service-provider")
+
+	parsed.SetAbortSource(tcap.AbortSource(tcap.AbortDialogueServiceUser))
+	if src, _ := parsed.Source(); src != tcap.AbortSource(tcap.AbortDialogueServiceUser) {
+		t.Errorf("source after SetAbortSource: got %s", src)
+	}
+	if _, err := tcap.NewABRT(7).Source(); !errors.Is(err, tcap.ErrInvalidAbortSource) {
+		t.Errorf("undefined source: got %v, want %v", err, tcap.ErrInvalidAbortSource)
+	}
+
+	m := tcap.NewEndMessage(tcap.WithDTID(1), tcap.WithDialoguePDU(abrt))
+	var verr *tcap.ValidationError
+	if err := m.Validate(); !errors.As(err, &verr) {
+		t.Fatalf("ABRT in End: got %v, want ValidationError", err)
+	}
+	verify.Values(t, "violations", verr.Violations, []tcap.Violation{{Field: "DialoguePDU", Reason: "ABRT only allowed in Abort"}})
+
+	if err := tcap.NewAbortMessage(tcap.WithDTID(1), tcap.WithDialoguePDU(abrt)).Validate(); err != nil {
+		t.Errorf("ABRT in Abort: got %v", err)
+	}
+}
+
+func TestDialogueDiagnostic(t *testing.T) {
+	rejected := tcap.NewACNNotSupported(1, tcap.ShortMsgMTRelayContext, 2, false)
+	rejected.Dialogue.DialoguePDU.SetResult(tcap.AssociateResult(tcap.RejectPerm), tcap.SourceDiagnostic{Source: tcap.DialogueServiceUser, Reason: tcap.NoReasonGiven})
+	rejected.SetLength()
+
+	tests := []struct {
+		name string
+		m    *tcap.TCAP
+		kind tcap.DialogueDiagnosticKind
+	}{
+		{"user abort", tcap.NewAbortMessage(tcap.WithDTID(1), tcap.WithDialoguePDU(tcap.NewABRT(uint8(tcap.AbortDialogueServiceUser)))), tcap.DialogueUserAbort},
+		{"provider abort", tcap.NewAbortMessage(tcap.WithDTID(1), tcap.WithDialoguePDU(tcap.NewABRT(uint8(tcap.AbortDialogueServiceProvider)))), tcap.DialogueProviderAbort},
+		{"fallback in Abort", tcap.NewACNNotSupported(1, tcap.ShortMsgMTRelayContext, 2, false), tcap.DialogueVersionFallback},
+		{"fallback in End", tcap.NewACNNotSupported(1, tcap.ShortMsgMTRelayContext, 2, true), tcap.DialogueVersionFallback},
+		{"rejected", rejected, tcap.DialogueRejected},
+	}
+	for _, tt := range tests {
+		b, err := tt.m.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed, err := tcap.Parse(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d, ok := parsed.DialogueDiagnostic()
+		if !ok {
+			t.Errorf("%s: no diagnostic", tt.name)
+			continue
+		}
+		verify.Values(t, tt.name, d.Kind, tt.kind)
+		if tt.kind == tcap.DialogueVersionFallback {
+			verify.Values(t, tt.name+" context", d.ApplicationContext, tcap.ShortMsgMTRelayV2)
+		}
+	}
+
+	if _, ok := tcap.NewAbortMessage(tcap.WithDTID(1), tcap.WithAbortCause(tcap.ResourceLimitation)).DialogueDiagnostic(); ok {
+		t.Error("P-Abort: got diagnostic")
+	}
+	accepted := tcap.NewEndReturnResultWithDialogue(1, tcap.DialogueAsID, tcap.ShortMsgMTRelayContext, 3, 0, 44, true, nil)
+	if _, ok := accepted.DialogueDiagnostic(); ok {
+		t.Error("accepted: got diagnostic")
+	}
+}
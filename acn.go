@@ -0,0 +1,246 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrUnknownApplicationContext is returned when the name of application context is unknown.
+var ErrUnknownApplicationContext = errors.New("tcap: unknown application context")
+
+// ApplicationContext is the application-context-name in AARQ, AARE and AUDT.
+type ApplicationContext OID
+
+// MAPContext returns the MAP application context of the context and version, e.g.,
+// MAPContext(ShortMsgMTRelayContext, 3) for shortMsgMT-RelayContext-v3 (0.4.0.0.1.0.25.3).
+func MAPContext(ctx, ver uint8) ApplicationContext {
+	return ApplicationContext{0, 4, 0, 0, 1, 0, uint64(ctx), uint64(ver)}
+}
+
+// MAP application contexts commonly used.
+var (
+	NetworkLocUpV1                           = MAPContext(NetworkLocUpContext, 1)
+	NetworkLocUpV2                           = MAPContext(NetworkLocUpContext, 2)
+	NetworkLocUpV3                           = MAPContext(NetworkLocUpContext, 3)
+	LocationInfoRetrievalV1                  = MAPContext(LocationInfoRetrievalContext, 1)
+	LocationInfoRetrievalV2                  = MAPContext(LocationInfoRetrievalContext, 2)
+	LocationInfoRetrievalV3                  = MAPContext(LocationInfoRetrievalContext, 3)
+	ShortMsgGatewayV1                        = MAPContext(ShortMsgGatewayContext, 1)
+	ShortMsgGatewayV2                        = MAPContext(ShortMsgGatewayContext, 2)
+	ShortMsgGatewayV3                        = MAPContext(ShortMsgGatewayContext, 3)
+	ShortMsgMORelayV1                        = MAPContext(ShortMsgRelayContext, 1) // shortMsgRelayContext-v1, used for MO and MT.
+	ShortMsgMORelayV2                        = MAPContext(ShortMsgRelayContext, 2)
+	ShortMsgMORelayV3                        = MAPContext(ShortMsgRelayContext, 3)
+	ShortMsgMTRelayV2                        = MAPContext(ShortMsgMTRelayContext, 2)
+	ShortMsgMTRelayV3                        = MAPContext(ShortMsgMTRelayContext, 3)
+	ShortMsgAlertV1                          = MAPContext(ShortMsgAlertContext, 1)
+	ShortMsgAlertV2                          = MAPContext(ShortMsgAlertContext, 2)
+	NetworkUnstructuredSsV1                  = MAPContext(NetworkUnstructuredSsContext, 1)
+	NetworkUnstructuredSsV2                  = MAPContext(NetworkUnstructuredSsContext, 2)
+	SubscriberInfoEnquiryV3                  = MAPContext(SubscriberInfoEnquiryContext, 3)
+	AnyTimeInfoEnquiryV3                     = MAPContext(AnyTimeInfoEnquiryContext, 3)
+	InfoRetrievalV1                          = MAPContext(InfoRetrievalContext, 1)
+	InfoRetrievalV2                          = MAPContext(InfoRetrievalContext, 2)
+	InfoRetrievalV3                          = MAPContext(InfoRetrievalContext, 3)
+	SubscriberDataMngtV3                     = MAPContext(SubscriberDataMngtContext, 3)
+	GprsLocationUpdateV3                     = MAPContext(GprsLocationUpdateContext, 3)
+	RoamingNumberEnquiryV3                   = MAPContext(RoamingNumberEnquiryContext, 3)
+	LocationCancellationV3                   = MAPContext(LocationCancellationContext, 3)
+	MwdMngtV3                                = MAPContext(MwdMngtContext, 3)
+	AuthenticationFailureReportV3            = MAPContext(AuthenticationFailureReportContext, 3)
+	LocationSvcGatewayV3                     = MAPContext(LocationSvcGatewayContext, 3)
+	SubscriberDataModificationNotificationV3 = MAPContext(SubscriberDataModificationNotificationContext, 3)
+)
+
+// CAP application contexts of phase 1 to 4 (3GPP TS 29.078).
+var (
+	CAPv1GsmSSFToGsmSCF       = ApplicationContext{0, 4, 0, 0, 1, 0, 50, 0}
+	CAPv2GsmSSFToGsmSCF       = ApplicationContext{0, 4, 0, 0, 1, 0, 50, 1}
+	CAPv2AssistGsmSSFToGsmSCF = ApplicationContext{0, 4, 0, 0, 1, 0, 51, 1}
+	CAPv2GsmSRFToGsmSCF       = ApplicationContext{0, 4, 0, 0, 1, 0, 52, 1}
+	CAPv3GsmSSFToGsmSCF       = ApplicationContext{0, 4, 0, 0, 1, 21, 3, 4}
+	CAPv3GprsSSFToGsmSCF      = ApplicationContext{0, 4, 0, 0, 1, 21, 3, 50}
+	CAPv3SMS                  = ApplicationContext{0, 4, 0, 0, 1, 21, 3, 61}
+	CAPv4GsmSSFToGsmSCF       = ApplicationContext{0, 4, 0, 0, 1, 22, 3, 4}
+	CAPv4SMS                  = ApplicationContext{0, 4, 0, 0, 1, 22, 3, 61}
+)
+
+// capContextNames is the names of CAP application contexts.
+var capContextNames = map[string]ApplicationContext{
+	"cap-v1-gsmSSF-to-gsmSCF":        CAPv1GsmSSFToGsmSCF,
+	"cap-v2-gsmSSF-to-gsmSCF":        CAPv2GsmSSFToGsmSCF,
+	"cap-v2-assist-gsmSSF-to-gsmSCF": CAPv2AssistGsmSSFToGsmSCF,
+	"cap-v2-gsmSRF-to-gsmSCF":        CAPv2GsmSRFToGsmSCF,
+	"capssf-scfGenericAC-v3":         CAPv3GsmSSFToGsmSCF,
+	"capGprsSsfToScfAC-v3":           CAPv3GprsSSFToGsmSCF,
+	"cap3-sms":                       CAPv3SMS,
+	"capssf-scfGenericAC-v4":         CAPv4GsmSSFToGsmSCF,
+	"cap4-sms":                       CAPv4SMS,
+}
+
+// mapContextNames is the names of the contexts in MAP application contexts.
+var mapContextNames = map[uint8]string{
+	NetworkLocUpContext:                           "networkLocUpContext",
+	LocationCancellationContext:                   "locationCancellationContext",
+	RoamingNumberEnquiryContext:                   "roamingNumberEnquiryContext",
+	IstAlertingContext:                            "istAlertingContext",
+	LocationInfoRetrievalContext:                  "locationInfoRetrievalContext",
+	CallControlTransferContext:                    "callControlTransferContext",
+	ReportingContext:                              "reportingContext",
+	CallCompletionContext:                         "callCompletionContext",
+	ServiceTerminationContext:                     "serviceTerminationContext",
+	ResetContext:                                  "resetContext",
+	HandoverControlContext:                        "handoverControlContext",
+	SIWFSAllocationContext:                        "sIWFSAllocationContext",
+	EquipmentMngtContext:                          "equipmentMngtContext",
+	InfoRetrievalContext:                          "infoRetrievalContext",
+	InterVlrInfoRetrievalContext:                  "interVlrInfoRetrievalContext",
+	SubscriberDataMngtContext:                     "SubscriberDataMngtContext",
+	TracingContext:                                "tracingContext",
+	NetworkFunctionalSsContext:                    "networkFunctionalSsContext",
+	NetworkUnstructuredSsContext:                  "networkUnstructuredSsContext",
+	ShortMsgGatewayContext:                        "shortMsgGatewayContext",
+	ShortMsgRelayContext:                          "shortMsgRelayContext",
+	SubscriberDataModificationNotificationContext: "subscriberDataModificationNotificationContext",
+	ShortMsgAlertContext:                          "shortMsgAlertContext",
+	MwdMngtContext:                                "mwdMngtContext",
+	ShortMsgMTRelayContext:                        "shortMsgMTRelayContext",
+	ImsiRetrievalContext:                          "imsiRetrievalContext",
+	MsPurgingContext:                              "msPurgingContext",
+	SubscriberInfoEnquiryContext:                  "subscriberInfoEnquiryContext",
+	AnyTimeInfoEnquiryContext:                     "anyTimeInfoEnquiryContext",
+	GroupCallControlContext:                       "groupCallControlContext",
+	GprsLocationUpdateContext:                     "gprsLocationUpdateContext",
+	GprsLocationInfoRetrievalContext:              "gprsLocationInfoRetrievalContext",
+	FailureReportContext:                          "failureReportContext",
+	GprsNotifyContext:                             "gprsNotifyContext",
+	SsInvocationNotificationContext:               "ssInvocationNotificationContext",
+	LocationSvcGatewayContext:                     "locationSvcGatewayContext",
+	LocationSvcEnquiryContext:                     "locationSvcEnquiryContext",
+	AuthenticationFailureReportContext:            "authenticationFailureReportContext",
+	MmEventReportingContext:                       "mmEventReportingContext",
+	AnyTimeInfoHandlingContext:                    "anyTimeInfoHandlingContext",
+}
+
+// mapContextName returns the name of the context in MAP application context, or empty if unknown.
+func mapContextName(ctx uint8) string {
+	return mapContextNames[ctx]
+}
+
+// LookupApplicationContext returns the application context of the name (e.g., "shortMsgMTRelayContext-v3"
+// or "cap-v2-gsmSSF-to-gsmSCF") as returned by String, or the one in dotted notation.
+func LookupApplicationContext(name string) (ApplicationContext, error) {
+	if ac, ok := capContextNames[name]; ok {
+		return ac, nil
+	}
+	if n := strings.LastIndex(name, "-v"); n > 0 {
+		ver, err := strconv.ParseUint(name[n+2:], 10, 8)
+		if err == nil {
+			for ctx, s := range mapContextNames {
+				if strings.EqualFold(s, name[:n]) {
+					return MAPContext(ctx, uint8(ver)), nil
+				}
+			}
+		}
+	}
+	if o, err := ParseOID(name); err == nil {
+		return ApplicationContext(o), nil
+	}
+	return nil, ErrUnknownApplicationContext
+}
+
+// IsMAP reports whether the application context is the one of MAP, i.e., under 0.4.0.0.1.0
+// and not the one of CAP.
+func (ac ApplicationContext) IsMAP() bool {
+	if len(ac) != 8 || !OID(ac[:6]).Equal(OID{0, 4, 0, 0, 1, 0}) || ac[6] > 0xff || ac[7] > 0xff {
+		return false
+	}
+	for _, c := range capContextNames {
+		if ac.Equal(c) {
+			return false
+		}
+	}
+	return true
+}
+
+// Context returns the context and version of the MAP application context, e.g.,
+// ShortMsgMTRelayContext and 3 for shortMsgMT-RelayContext-v3.
+func (ac ApplicationContext) Context() (ctx, ver uint8, ok bool) {
+	if !ac.IsMAP() {
+		return 0, 0, false
+	}
+	return uint8(ac[6]), uint8(ac[7]), true
+}
+
+// WithVersion returns the MAP application context of the same context with the version,
+// e.g., to fall back to the lower version. It returns ac as is if it is not MAP.
+func (ac ApplicationContext) WithVersion(ver uint8) ApplicationContext {
+	ctx, _, ok := ac.Context()
+	if !ok {
+		return ac
+	}
+	return MAPContext(ctx, ver)
+}
+
+// Equal reports whether the application contexts are the same.
+func (ac ApplicationContext) Equal(other ApplicationContext) bool {
+	return OID(ac).Equal(OID(other))
+}
+
+// String returns the name of the application context, e.g., "shortMsgMTRelayContext-v3",
+// or the dotted notation if unknown.
+func (ac ApplicationContext) String() string {
+	for name, c := range capContextNames {
+		if ac.Equal(c) {
+			return name
+		}
+	}
+	if ctx, ver, ok := ac.Context(); ok {
+		if name := mapContextName(ctx); name != "" {
+			return fmt.Sprintf("%s-v%d", name, ver)
+		}
+	}
+	return OID(ac).String()
+}
+
+// NewApplicationContextNameOID creates a new ApplicationContextName as an IE of the application context.
+func NewApplicationContextNameOID(ac ApplicationContext) (*IE, error) {
+	oid, err := NewObjectIdentifier(NewUniversalPrimitiveTag(6), OID(ac))
+	if err != nil {
+		return nil, err
+	}
+	return NewIE(NewContextSpecificConstructorTag(1), encodeTLV(oid)), nil
+}
+
+// ApplicationContext returns the application-context-name of the DialoguePDU.
+func (d *DialoguePDU) ApplicationContext() (ApplicationContext, error) {
+	if d.ApplicationContextName == nil {
+		return nil, ErrInvalidOID
+	}
+	i, err := ParseIE(d.ApplicationContextName.Value)
+	if err != nil || i.Tag != NewUniversalPrimitiveTag(6) {
+		return nil, ErrInvalidOID
+	}
+	o, err := i.OID()
+	if err != nil {
+		return nil, err
+	}
+	return ApplicationContext(o), nil
+}
+
+// SetApplicationContext sets the application-context-name of the DialoguePDU, and updates the lengths.
+func (d *DialoguePDU) SetApplicationContext(ac ApplicationContext) error {
+	i, err := NewApplicationContextNameOID(ac)
+	if err != nil {
+		return err
+	}
+	d.ApplicationContextName = i
+	d.SetLength()
+	return nil
+}
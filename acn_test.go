@@ -0,0 +1,62 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestApplicationContext(t *testing.T) {
+	verify.Values(t, "MAP", tcap.ShortMsgMTRelayV3.String(), "shortMsgMTRelayContext-v3")
+	verify.Values(t, "CAP", tcap.CAPv2GsmSSFToGsmSCF.String(), "cap-v2-gsmSSF-to-gsmSCF")
+	verify.Values(t, "unknown", tcap.ApplicationContext{1, 2, 3}.String(), "1.2.3")
+	verify.Values(t, "fallback", tcap.ShortMsgMTRelayV3.WithVersion(2), tcap.ShortMsgMTRelayV2)
+	if tcap.CAPv2GsmSSFToGsmSCF.IsMAP() {
+		t.Error("CAP v2 is MAP")
+	}
+
+	for _, name := range []string{"networkLocUpContext-v3", "cap4-sms", "0.4.0.0.1.0.1.3"} {
+		ac, err := tcap.LookupApplicationContext(name)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if name != "0.4.0.0.1.0.1.3" && ac.String() != name {
+			t.Errorf("%s: got %s", name, ac)
+		}
+	}
+	if _, err := tcap.LookupApplicationContext("noSuchContext-v1"); !errors.Is(err, tcap.ErrUnknownApplicationContext) {
+		t.Errorf("unknown: got %v, want %v", err, tcap.ErrUnknownApplicationContext)
+	}
+
+	// the same encoding as NewApplicationContextName.
+	acn, err := tcap.NewApplicationContextNameOID(tcap.NetworkLocUpV3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "ApplicationContextName", acn, tcap.NewApplicationContextName(tcap.NetworkLocUpContext, 3))
+
+	aarq := tcap.NewAARQ(1, tcap.ShortMsgMTRelayContext, 3)
+	if err := aarq.SetApplicationContext(tcap.CAPv4GsmSSFToGsmSCF); err != nil {
+		t.Fatal(err)
+	}
+	b, err := aarq.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := tcap.ParseDialoguePDU(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ac, err := parsed.ApplicationContext()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "parsed", ac, tcap.CAPv4GsmSSFToGsmSCF)
+}
@@ -0,0 +1,135 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import "io"
+
+// NatureOfAddress is the nature of address indicator of AddressString (3GPP TS 29.002).
+type NatureOfAddress uint8
+
+// NatureOfAddress definitions.
+const (
+	UnknownNumber NatureOfAddress = iota
+	InternationalNumber
+	NationalSignificantNumber
+	NetworkSpecificNumber
+	SubscriberNumber
+	_
+	AbbreviatedNumber
+)
+
+// NumberingPlan is the numbering plan indicator of AddressString (3GPP TS 29.002).
+type NumberingPlan uint8
+
+// NumberingPlan definitions.
+const (
+	UnknownNumberingPlan NumberingPlan = 0
+	ISDNTelephony        NumberingPlan = 1 // E.164
+	DataNumbering        NumberingPlan = 3 // X.121
+	TelexNumbering       NumberingPlan = 4 // F.69
+	LandMobileNumbering  NumberingPlan = 6 // E.212
+	NationalNumbering    NumberingPlan = 8
+	PrivateNumbering     NumberingPlan = 9
+)
+
+// MaxISDNAddressLength is the maximum length of ISDN-AddressString in octets, including the first one.
+const MaxISDNAddressLength = 9
+
+// AddressString is AddressString and ISDN-AddressString in MAP, e.g., MSISDN and the addresses
+// of the network nodes, which is the octet of the indicators followed by the digits in TBCD.
+type AddressString struct {
+	// Extension is the extension bit, which is always set in MAP as no more octet of indicators follows.
+	Extension       bool
+	NatureOfAddress NatureOfAddress
+	NumberingPlan   NumberingPlan
+	Digits          string
+}
+
+// NewE164Address creates a new AddressString of the international E.164 number,
+// given without the leading '+', e.g., "819011112222".
+func NewE164Address(digits string) *AddressString {
+	return &AddressString{
+		Extension:       true,
+		NatureOfAddress: InternationalNumber,
+		NumberingPlan:   ISDNTelephony,
+		Digits:          digits,
+	}
+}
+
+// ParseAddressString decodes the byte sequence as an AddressString.
+func ParseAddressString(b []byte) (*AddressString, error) {
+	a := &AddressString{}
+	if err := a.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// MarshalBinary returns the byte sequence generated from an AddressString.
+func (a *AddressString) MarshalBinary() ([]byte, error) {
+	digits, err := EncodeTBCD(a.Digits)
+	if err != nil {
+		return nil, err
+	}
+
+	first := uint8(a.NatureOfAddress&0x07)<<4 | uint8(a.NumberingPlan&0x0f)
+	if a.Extension {
+		first |= 0x80
+	}
+	return append([]byte{first}, digits...), nil
+}
+
+// UnmarshalBinary sets the values retrieved from byte sequence in an AddressString.
+func (a *AddressString) UnmarshalBinary(b []byte) error {
+	if len(b) == 0 {
+		return io.ErrUnexpectedEOF
+	}
+
+	digits, err := DecodeTBCD(b[1:])
+	if err != nil {
+		return err
+	}
+	a.Extension = b[0]&0x80 != 0
+	a.NatureOfAddress = NatureOfAddress(b[0] >> 4 & 0x07)
+	a.NumberingPlan = NumberingPlan(b[0] & 0x0f)
+	a.Digits = digits
+	return nil
+}
+
+// MarshalLen returns the serial length of AddressString.
+func (a *AddressString) MarshalLen() int {
+	return 1 + (len(a.Digits)+1)/2
+}
+
+// String returns the digits of AddressString, with the leading '+' for the international E.164 number.
+func (a *AddressString) String() string {
+	if a.NatureOfAddress == InternationalNumber && a.NumberingPlan == ISDNTelephony {
+		return "+" + a.Digits
+	}
+	return a.Digits
+}
+
+// NewAddressString creates a new IE with the AddressString encoded, e.g., as a parameter of Invoke.
+func NewAddressString(tag Tag, a *AddressString) (*IE, error) {
+	b, err := a.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return NewIE(tag, b), nil
+}
+
+// AddressString returns the value of IE decoded as an AddressString.
+func (i *IE) AddressString() (*AddressString, error) {
+	return ParseAddressString(i.Value)
+}
+
+// ISDNAddressString returns the value of IE decoded as an ISDN-AddressString,
+// which is an AddressString of MaxISDNAddressLength octets at most.
+func (i *IE) ISDNAddressString() (*AddressString, error) {
+	if len(i.Value) > MaxISDNAddressLength {
+		return nil, &TooLongError{Field: "ISDN-AddressString", Length: len(i.Value), Max: MaxISDNAddressLength}
+	}
+	return ParseAddressString(i.Value)
+}
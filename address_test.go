@@ -0,0 +1,45 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestAddressString(t *testing.T) {
+	i, err := tcap.NewAddressString(tcap.NewContextSpecificPrimitiveTag(1), tcap.NewE164Address("819011112222"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "encoded", i.Value, []byte{0x91, 0x18, 0x09, 0x11, 0x11, 0x22, 0x22})
+
+	a, err := i.ISDNAddressString()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "decoded", a, tcap.NewE164Address("819011112222"))
+	verify.Values(t, "string", a.String(), "+819011112222")
+
+	national, err := tcap.ParseAddressString([]byte{0xa1, 0x90, 0x11, 0xf1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "national", national, &tcap.AddressString{
+		Extension:       true,
+		NatureOfAddress: tcap.NationalSignificantNumber,
+		NumberingPlan:   tcap.ISDNTelephony,
+		Digits:          "09111",
+	})
+
+	long := tcap.NewIE(tcap.NewContextSpecificPrimitiveTag(1), make([]byte, 10))
+	var tooLong *tcap.TooLongError
+	if _, err := long.ISDNAddressString(); !errors.As(err, &tooLong) {
+		t.Errorf("got %v, want TooLongError", err)
+	}
+}
@@ -0,0 +1,231 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+/*
+Package ansi provides handling of ANSI TCAP (T1.114) used in North American SS7 networks.
+
+The API is shaped after the ITU-T implementation in the parent package, and the
+Information Elements are represented with tcap.IE as well.
+*/
+package ansi
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/en-vee/go-tcap"
+)
+
+// TCAP represents a General Structure of ANSI TCAP Information Elements.
+type TCAP struct {
+	Transaction *Transaction
+	Components  *Components
+}
+
+// NewQueryWithPermissionInvoke creates a new TCAP of type Package=QueryWithPermission, Component=InvokeLast.
+func NewQueryWithPermissionInvoke(otid uint32, invID, opCode int, payload []byte) *TCAP {
+	t := &TCAP{
+		Transaction: NewQueryWithPermission(otid, []byte{}),
+		Components:  NewComponents(NewInvoke(invID, -1, opCode, false, true, payload)),
+	}
+	t.SetLength()
+
+	return t
+}
+
+// NewConversationWithPermissionInvoke creates a new TCAP of type Package=ConversationWithPermission, Component=InvokeLast.
+func NewConversationWithPermissionInvoke(otid, rtid uint32, invID, corrID, opCode int, payload []byte) *TCAP {
+	t := &TCAP{
+		Transaction: NewConversationWithPermission(otid, rtid, []byte{}),
+		Components:  NewComponents(NewInvoke(invID, corrID, opCode, false, true, payload)),
+	}
+	t.SetLength()
+
+	return t
+}
+
+// NewResponseReturnResult creates a new TCAP of type Package=Response, Component=ReturnResultLast.
+func NewResponseReturnResult(rtid uint32, corrID int, payload []byte) *TCAP {
+	t := &TCAP{
+		Transaction: NewResponse(rtid, []byte{}),
+		Components:  NewComponents(NewReturnResult(corrID, true, payload)),
+	}
+	t.SetLength()
+
+	return t
+}
+
+// MarshalBinary returns the byte sequence generated from a TCAP instance.
+func (t *TCAP) MarshalBinary() ([]byte, error) {
+	b := make([]byte, t.MarshalLen())
+	if err := t.MarshalTo(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// AppendBinary appends the byte sequence generated from a TCAP instance to b.
+func (t *TCAP) AppendBinary(b []byte) ([]byte, error) {
+	return appendBinary(b, t)
+}
+
+// WriteTo writes the byte sequence generated from a TCAP instance to w.
+func (t *TCAP) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(w, t)
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+func (t *TCAP) MarshalTo(b []byte) error {
+	var offset = 0
+	if portion := t.Transaction; portion != nil {
+		if err := portion.MarshalTo(b[offset : offset+portion.MarshalLen()]); err != nil {
+			return err
+		}
+		offset += portion.MarshalLen()
+	}
+
+	if portion := t.Components; portion != nil {
+		if err := portion.MarshalTo(b[offset : offset+portion.MarshalLen()]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Parse parses given byte sequence as a TCAP.
+func Parse(b []byte) (*TCAP, error) {
+	t := &TCAP{}
+	if err := t.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// UnmarshalBinary sets the values retrieved from byte sequence in a TCAP.
+func (t *TCAP) UnmarshalBinary(b []byte) error {
+	var err error
+	t.Transaction, err = ParseTransaction(b)
+	if err != nil {
+		return err
+	}
+
+	payload := t.Transaction.Payload
+	if len(payload) == 0 {
+		return nil
+	}
+
+	// Dialogue Portion is not supported yet, and is skipped if present.
+	if payload[0] == uint8(tcap.NewPrivateConstructorTag(25)) {
+		d, err := parseIE(payload)
+		if err != nil {
+			return err
+		}
+		payload = payload[d.MarshalLen():]
+	}
+
+	if len(payload) > 0 && payload[0] == uint8(tcap.NewPrivateConstructorTag(8)) {
+		t.Components, err = ParseComponents(payload)
+		if err != nil {
+			return err
+		}
+
+		// Payload keeps only the Dialogue Portion skipped, if any, so that the Components
+		// are not marshaled twice.
+		p := t.Transaction.Payload
+		t.Transaction.Payload = p[:len(p)-len(payload)]
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length of TCAP.
+func (t *TCAP) MarshalLen() int {
+	l := 0
+	if portion := t.Components; portion != nil {
+		l += portion.MarshalLen()
+	}
+	if portion := t.Transaction; portion != nil {
+		l += portion.MarshalLen()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (t *TCAP) SetLength() {
+	if portion := t.Components; portion != nil {
+		portion.SetLength()
+	}
+	if portion := t.Transaction; portion != nil {
+		portion.SetLength()
+		if c := t.Components; c != nil {
+			portion.Length += c.MarshalLen()
+		}
+	}
+}
+
+// ComponentType returns the ComponentType in Component Portion in the list of string.
+//
+// The returned value is of type []string, as it may have multiple Components.
+func (t *TCAP) ComponentType() []string {
+	if c := t.Components; c != nil {
+		var types []string
+		for _, cm := range c.Component {
+			types = append(types, cm.ComponentTypeString())
+		}
+		return types
+	}
+
+	return nil
+}
+
+// CorrelationID returns the Correlation IDs in Component Portion.
+//
+// The returned value is of type []uint8, as it may have multiple Components.
+// Components without Correlation ID are not included.
+func (t *TCAP) CorrelationID() []uint8 {
+	if c := t.Components; c != nil {
+		var ids []uint8
+		for _, cm := range c.Component {
+			if id, ok := cm.CorrID(); ok {
+				ids = append(ids, id)
+			}
+		}
+		return ids
+	}
+
+	return nil
+}
+
+// OTID returns the Originating Transaction ID in Transaction Portion in uint32.
+func (t *TCAP) OTID() uint32 {
+	if ts := t.Transaction; ts != nil {
+		if otid := ts.origTransactionID(); len(otid) == 4 {
+			return binary.BigEndian.Uint32(otid)
+		}
+	}
+
+	return 0
+}
+
+// RTID returns the Responding Transaction ID in Transaction Portion in uint32.
+func (t *TCAP) RTID() uint32 {
+	if ts := t.Transaction; ts != nil {
+		if rtid := ts.respTransactionID(); len(rtid) == 4 {
+			return binary.BigEndian.Uint32(rtid)
+		}
+	}
+
+	return 0
+}
+
+// String returns TCAP in human readable string.
+func (t *TCAP) String() string {
+	return fmt.Sprintf("{Transaction: %s, Components: %s}",
+		t.Transaction,
+		t.Components,
+	)
+}
@@ -0,0 +1,71 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ansi
+
+import "bytes"
+
+// Clone returns a deep copy of the TCAP.
+func (t *TCAP) Clone() *TCAP {
+	if t == nil {
+		return nil
+	}
+
+	return &TCAP{
+		Transaction: t.Transaction.Clone(),
+		Components:  t.Components.Clone(),
+	}
+}
+
+// Clone returns a deep copy of the Transaction.
+func (t *Transaction) Clone() *Transaction {
+	if t == nil {
+		return nil
+	}
+
+	return &Transaction{
+		Type:                 t.Type,
+		Length:               t.Length,
+		TransactionID:        t.TransactionID.Clone(),
+		PAbortCause:          t.PAbortCause.Clone(),
+		UserAbortInformation: t.UserAbortInformation.Clone(),
+		Payload:              bytes.Clone(t.Payload),
+	}
+}
+
+// Clone returns a deep copy of the Components.
+func (c *Components) Clone() *Components {
+	if c == nil {
+		return nil
+	}
+
+	cs := &Components{
+		Tag:    c.Tag,
+		Length: c.Length,
+	}
+	if c.Component != nil {
+		cs.Component = make([]*Component, len(c.Component))
+		for n, comp := range c.Component {
+			cs.Component[n] = comp.Clone()
+		}
+	}
+	return cs
+}
+
+// Clone returns a deep copy of the Component.
+func (c *Component) Clone() *Component {
+	if c == nil {
+		return nil
+	}
+
+	return &Component{
+		Type:          c.Type,
+		Length:        c.Length,
+		ComponentID:   c.ComponentID.Clone(),
+		OperationCode: c.OperationCode.Clone(),
+		ErrorCode:     c.ErrorCode.Clone(),
+		ProblemCode:   c.ProblemCode.Clone(),
+		Parameter:     c.Parameter.Clone(),
+	}
+}
@@ -0,0 +1,239 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ansi_test
+
+import (
+	"encoding"
+	"testing"
+
+	"github.com/en-vee/go-tcap/ansi"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+type serializable interface {
+	encoding.BinaryMarshaler
+	MarshalLen() int
+}
+
+var testcases = []struct {
+	description string
+	structured  serializable
+	serialized  []byte
+	parseFunc   func(b []byte) (serializable, error)
+}{
+	// TCAP (All)
+	{
+		description: "TCAP/QueryWithPermission - InvokeLast / IS-41 LocationRequest",
+		structured: ansi.NewQueryWithPermissionInvoke(
+			0x11111111,                     // OTID
+			1,                              // Invoke ID
+			0x090f,                         // OpCode: family 9, LocationRequest
+			[]byte{0x84, 0x02, 0xde, 0xad}, // Payload
+		),
+		serialized: []byte{
+			// Transaction Portion
+			0xe2, 0x17, 0xc7, 0x04, 0x11, 0x11, 0x11, 0x11,
+			// Component Portion
+			0xe8, 0x0f, 0xe9, 0x0d, 0xcf, 0x01, 0x01, 0xd1, 0x02, 0x09, 0x0f, 0xf2, 0x04, 0x84, 0x02, 0xde,
+			0xad,
+		},
+		parseFunc: func(b []byte) (serializable, error) {
+			v, err := ansi.Parse(b)
+			if err != nil {
+				return nil, err
+			}
+			v.Components.Component[0].Parameter.IE = nil
+
+			return v, nil
+		},
+	}, {
+		description: "TCAP/ConversationWithPermission - InvokeLast with Correlation ID",
+		structured: ansi.NewConversationWithPermissionInvoke(
+			0x11111111, // OTID
+			0x22222222, // RTID
+			2,          // Invoke ID
+			1,          // Correlation ID
+			0x090f,     // OpCode
+			nil,        // Payload
+		),
+		serialized: []byte{
+			// Transaction Portion
+			0xe5, 0x16, 0xc7, 0x08, 0x11, 0x11, 0x11, 0x11, 0x22, 0x22, 0x22, 0x22,
+			// Component Portion
+			0xe8, 0x0a, 0xe9, 0x08, 0xcf, 0x02, 0x02, 0x01, 0xd1, 0x02, 0x09, 0x0f,
+		},
+		parseFunc: func(b []byte) (serializable, error) {
+			v, err := ansi.Parse(b)
+			if err != nil {
+				return nil, err
+			}
+
+			return v, nil
+		},
+	}, {
+		description: "TCAP/Response - ReturnResultLast",
+		structured: ansi.NewResponseReturnResult(
+			0x22222222,                     // RTID
+			1,                              // Correlation ID
+			[]byte{0x84, 0x02, 0xbe, 0xef}, // Payload
+		),
+		serialized: []byte{
+			// Transaction Portion
+			0xe4, 0x13, 0xc7, 0x04, 0x22, 0x22, 0x22, 0x22,
+			// Component Portion
+			0xe8, 0x0b, 0xea, 0x09, 0xcf, 0x01, 0x01, 0xf2, 0x04, 0x84, 0x02, 0xbe, 0xef,
+		},
+		parseFunc: func(b []byte) (serializable, error) {
+			v, err := ansi.Parse(b)
+			if err != nil {
+				return nil, err
+			}
+			v.Components.Component[0].Parameter.IE = nil
+
+			return v, nil
+		},
+	},
+	// Transaction Portion
+	{
+		description: "Transaction/QueryWithPermission",
+		structured:  ansi.NewQueryWithPermission(0xdeadbeef, []byte{0xfa, 0xce}),
+		serialized:  []byte{0xe2, 0x08, 0xc7, 0x04, 0xde, 0xad, 0xbe, 0xef, 0xfa, 0xce},
+		parseFunc:   func(b []byte) (serializable, error) { return ansi.ParseTransaction(b) },
+	}, {
+		description: "Transaction/QueryWithoutPermission",
+		structured:  ansi.NewQueryWithoutPermission(0xdeadbeef, []byte{0xfa, 0xce}),
+		serialized:  []byte{0xe3, 0x08, 0xc7, 0x04, 0xde, 0xad, 0xbe, 0xef, 0xfa, 0xce},
+		parseFunc:   func(b []byte) (serializable, error) { return ansi.ParseTransaction(b) },
+	}, {
+		description: "Transaction/Response",
+		structured:  ansi.NewResponse(0xdeadbeef, []byte{0xfa, 0xce}),
+		serialized:  []byte{0xe4, 0x08, 0xc7, 0x04, 0xde, 0xad, 0xbe, 0xef, 0xfa, 0xce},
+		parseFunc:   func(b []byte) (serializable, error) { return ansi.ParseTransaction(b) },
+	}, {
+		description: "Transaction/ConversationWithPermission",
+		structured:  ansi.NewConversationWithPermission(0xdeadbeef, 0x11111111, []byte{0xfa, 0xce}),
+		serialized: []byte{
+			0xe5, 0x0c, 0xc7, 0x08, 0xde, 0xad, 0xbe, 0xef, 0x11, 0x11, 0x11, 0x11, 0xfa, 0xce,
+		},
+		parseFunc: func(b []byte) (serializable, error) { return ansi.ParseTransaction(b) },
+	}, {
+		description: "Transaction/ConversationWithoutPermission",
+		structured:  ansi.NewConversationWithoutPermission(0xdeadbeef, 0x11111111, []byte{0xfa, 0xce}),
+		serialized: []byte{
+			0xe6, 0x0c, 0xc7, 0x08, 0xde, 0xad, 0xbe, 0xef, 0x11, 0x11, 0x11, 0x11, 0xfa, 0xce,
+		},
+		parseFunc: func(b []byte) (serializable, error) { return ansi.ParseTransaction(b) },
+	}, {
+		description: "Transaction/Abort",
+		structured:  ansi.NewAbort(0xdeadbeef, ansi.ResourceUnavailable, nil),
+		serialized:  []byte{0xf6, 0x09, 0xc7, 0x04, 0xde, 0xad, 0xbe, 0xef, 0xd7, 0x01, 0x06},
+		parseFunc:   func(b []byte) (serializable, error) { return ansi.ParseTransaction(b) },
+	}, {
+		description: "Transaction/UserAbort",
+		structured:  ansi.NewUserAbort(0xdeadbeef, []byte{0xde, 0xad}, nil),
+		serialized:  []byte{0xf6, 0x0a, 0xc7, 0x04, 0xde, 0xad, 0xbe, 0xef, 0xf8, 0x02, 0xde, 0xad},
+		parseFunc:   func(b []byte) (serializable, error) { return ansi.ParseTransaction(b) },
+	},
+	// Component Portion
+	{
+		description: "Components/invokeNotLast",
+		structured:  ansi.NewComponents(ansi.NewInvoke(1, -1, 0x0301, true, false, []byte{0xde, 0xad})),
+		serialized: []byte{
+			0xe8, 0x0d, 0xed, 0x0b, 0xcf, 0x01, 0x01, 0xd0, 0x02, 0x03, 0x01, 0xf2, 0x02, 0xde, 0xad,
+		},
+		parseFunc: func(b []byte) (serializable, error) { return ansi.ParseComponents(b) },
+	}, {
+		description: "Components/invokeLast with Correlation ID only",
+		structured:  ansi.NewComponents(ansi.NewInvoke(-1, 5, 0x0301, true, true, nil)),
+		serialized: []byte{
+			0xe8, 0x0a, 0xe9, 0x08, 0xcf, 0x02, 0x00, 0x05, 0xd0, 0x02, 0x03, 0x01,
+		},
+		parseFunc: func(b []byte) (serializable, error) { return ansi.ParseComponents(b) },
+	}, {
+		description: "Components/returnResultNotLast",
+		structured:  ansi.NewComponents(ansi.NewReturnResult(1, false, []byte{0xde, 0xad})),
+		serialized: []byte{
+			0xe8, 0x09, 0xee, 0x07, 0xcf, 0x01, 0x01, 0xf2, 0x02, 0xde, 0xad,
+		},
+		parseFunc: func(b []byte) (serializable, error) { return ansi.ParseComponents(b) },
+	}, {
+		description: "Components/returnError",
+		structured:  ansi.NewComponents(ansi.NewReturnError(1, 0x81, false, nil)),
+		serialized: []byte{
+			0xe8, 0x08, 0xeb, 0x06, 0xcf, 0x01, 0x01, 0xd4, 0x01, 0x81,
+		},
+		parseFunc: func(b []byte) (serializable, error) { return ansi.ParseComponents(b) },
+	}, {
+		description: "Components/reject",
+		structured:  ansi.NewComponents(ansi.NewReject(1, ansi.InvokeProblem, ansi.InvokeProblemUnrecognizedOperationCode)),
+		serialized: []byte{
+			0xe8, 0x0b, 0xec, 0x09, 0xcf, 0x01, 0x01, 0xd5, 0x02, 0x02, 0x02, 0xf2, 0x00,
+		},
+		parseFunc: func(b []byte) (serializable, error) { return ansi.ParseComponents(b) },
+	}, {
+		description: "Components/reject without Component ID",
+		structured:  ansi.NewComponents(ansi.NewReject(-1, ansi.GeneralProblem, ansi.BadlyStructuredComponentPortion)),
+		serialized: []byte{
+			0xe8, 0x0a, 0xec, 0x08, 0xcf, 0x00, 0xd5, 0x02, 0x01, 0x03, 0xf2, 0x00,
+		},
+		parseFunc: func(b []byte) (serializable, error) { return ansi.ParseComponents(b) },
+	},
+}
+
+func TestCodec(t *testing.T) {
+	t.Helper()
+
+	for _, c := range testcases {
+		t.Run("Parse / "+c.description, func(t *testing.T) {
+			msg, err := c.parseFunc(c.serialized)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := msg, c.structured; !verify.Values(t, "", got, want) {
+				t.Fail()
+			}
+		})
+
+		t.Run("Marshal / "+c.description, func(t *testing.T) {
+			b, err := c.structured.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := b, c.serialized; !verify.Values(t, "", got, want) {
+				t.Fail()
+			}
+		})
+
+		t.Run("Len / "+c.description, func(t *testing.T) {
+			if got, want := c.structured.MarshalLen(), len(c.serialized); got != want {
+				t.Fatalf("got %v want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestParseRoundTrip(t *testing.T) {
+	b, err := ansi.NewQueryWithPermissionInvoke(0x11111111, 1, 0x0901, []byte{0x84, 0x02, 0xde, 0xad}).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// with Dialogue Portion, which is kept as it is.
+	withDialogue := append(append([]byte{b[0], b[1] + 4}, b[2:8]...), 0xf9, 0x02, 0xda, 0x00)
+	withDialogue = append(withDialogue, b[8:]...)
+
+	for _, in := range [][]byte{b, withDialogue} {
+		parsed, err := ansi.Parse(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out, err := parsed.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		verify.Values(t, "round trip", out, in)
+	}
+}
@@ -0,0 +1,636 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ansi
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/en-vee/go-tcap"
+)
+
+// Component Type definitions.
+const (
+	InvokeLast int = iota + 9
+	ReturnResultLast
+	ReturnError
+	Reject
+	InvokeNotLast
+	ReturnResultNotLast
+)
+
+// Problem Type definitions.
+//
+// Note that these are different from the ones in ITU-T Q.773.
+const (
+	GeneralProblem uint8 = iota + 1
+	InvokeProblem
+	ReturnResultProblem
+	ReturnErrorProblem
+	TransactionPortionProblem
+)
+
+// General Problem Specifier definitions.
+const (
+	UnrecognizedComponentType uint8 = iota + 1
+	IncorrectComponentPortion
+	BadlyStructuredComponentPortion
+	IncorrectComponentCoding
+)
+
+// Invoke Problem Specifier definitions.
+const (
+	InvokeProblemDuplicateInvocation uint8 = iota + 1
+	InvokeProblemUnrecognizedOperationCode
+	InvokeProblemIncorrectParameter
+	InvokeProblemUnrecognizedCorrelationID
+)
+
+// ReturnResult Problem Specifier definitions.
+const (
+	ResultProblemUnrecognizedCorrelationID uint8 = iota + 1
+	ResultProblemUnexpectedReturnResult
+	ResultProblemIncorrectParameter
+)
+
+// ReturnError Problem Specifier definitions.
+const (
+	ErrorProblemUnrecognizedCorrelationID uint8 = iota + 1
+	ErrorProblemUnexpectedReturnError
+	ErrorProblemUnrecognizedError
+	ErrorProblemUnexpectedError
+	ErrorProblemIncorrectParameter
+)
+
+// Transaction Portion Problem Specifier definitions.
+const (
+	TransactionProblemUnrecognizedPackageType uint8 = iota + 1
+	TransactionProblemIncorrectTransactionPortion
+	TransactionProblemBadlyStructuredTransactionPortion
+	TransactionProblemUnassignedRespondingTransactionID
+	TransactionProblemPermissionToReleaseProblem
+	TransactionProblemResourceUnavailable
+)
+
+// Components represents an ANSI TCAP Component Sequence.
+//
+// This is a TCAP Components' Header part. Contents are in Component field.
+type Components struct {
+	Tag       tcap.Tag
+	Length    int
+	Component []*Component
+}
+
+// Component represents an ANSI TCAP Component.
+type Component struct {
+	Type          tcap.Tag
+	Length        int
+	ComponentID   *tcap.IE
+	OperationCode *tcap.IE
+	ErrorCode     *tcap.IE
+	ProblemCode   *tcap.IE
+	Parameter     *tcap.IE
+}
+
+// NewComponents creates a new Components.
+func NewComponents(comps ...*Component) *Components {
+	c := &Components{
+		Tag:       tcap.NewPrivateConstructorTag(8),
+		Component: comps,
+	}
+	c.SetLength()
+
+	return c
+}
+
+// NewInvoke returns a new single InvokeLast or InvokeNotLast Component.
+//
+// The corrID is the Invoke ID of the Component to be correlated with this Invoke,
+// and is omitted if negative. The invID is also omitted if negative, which is
+// allowed only when no reply is expected. It is put as zero if the corrID is given,
+// as the Correlation ID is always the second octet of the Component ID.
+func NewInvoke(invID, corrID, opCode int, isNational, isLast bool, param []byte) *Component {
+	tag := InvokeNotLast
+	if isLast {
+		tag = InvokeLast
+	}
+
+	c := &Component{
+		Type:          tcap.NewPrivateConstructorTag(tag),
+		ComponentID:   NewComponentID(invID, corrID),
+		OperationCode: NewOperationCode(opCode, isNational),
+	}
+
+	if param != nil {
+		c.Parameter = NewParameterSet(param)
+	}
+
+	c.SetLength()
+	return c
+}
+
+// NewReturnResult returns a new single ReturnResultLast or ReturnResultNotLast Component.
+//
+// The corrID is the Invoke ID of the Invoke this Component responds to.
+func NewReturnResult(corrID int, isLast bool, param []byte) *Component {
+	tag := ReturnResultNotLast
+	if isLast {
+		tag = ReturnResultLast
+	}
+
+	c := &Component{
+		Type:        tcap.NewPrivateConstructorTag(tag),
+		ComponentID: NewComponentID(corrID, -1),
+	}
+
+	if param != nil {
+		c.Parameter = NewParameterSet(param)
+	}
+
+	c.SetLength()
+	return c
+}
+
+// NewReturnError returns a new single ReturnError Component.
+//
+// The corrID is the Invoke ID of the Invoke this Component responds to.
+func NewReturnError(corrID, errCode int, isNational bool, param []byte) *Component {
+	c := &Component{
+		Type:        tcap.NewPrivateConstructorTag(ReturnError),
+		ComponentID: NewComponentID(corrID, -1),
+		ErrorCode:   NewErrorCode(errCode, isNational),
+	}
+
+	if param != nil {
+		c.Parameter = NewParameterSet(param)
+	}
+
+	c.SetLength()
+	return c
+}
+
+// NewReject returns a new single Reject Component.
+//
+// The corrID is the Invoke ID of the rejected Component. If it is negative,
+// the Component ID is left empty, which is allowed when the rejected Component
+// cannot be identified.
+func NewReject(corrID int, problemType, problemSpecifier uint8) *Component {
+	c := &Component{
+		Type:        tcap.NewPrivateConstructorTag(Reject),
+		ComponentID: NewComponentID(corrID, -1),
+		ProblemCode: &tcap.IE{
+			Tag:   tcap.NewPrivatePrimitiveTag(21),
+			Value: []byte{problemType, problemSpecifier},
+		},
+		// Reject always carries an empty Parameter Set.
+		Parameter: NewParameterSet([]byte{}),
+	}
+	c.SetLength()
+	return c
+}
+
+// NewComponentID returns a new Component ID.
+//
+// The IDs are put in the Component ID in order, and the trailing negative ones are omitted.
+// As the IDs are identified by their positions, a negative one followed by a non-negative one
+// is put as zero, e.g., the Invoke ID of Invoke with the Correlation ID only.
+// In ANSI TCAP, the Component ID holds up to two octets: the Invoke ID and the
+// Correlation ID in Invoke, and only the Correlation ID in the other Components.
+func NewComponentID(ids ...int) *tcap.IE {
+	c := &tcap.IE{
+		Tag:   tcap.NewPrivatePrimitiveTag(15),
+		Value: []byte{},
+	}
+	n := len(ids)
+	for n > 0 && ids[n-1] < 0 {
+		n--
+	}
+	for _, id := range ids[:n] {
+		c.Value = append(c.Value, uint8(max(id, 0)))
+	}
+	c.SetLength()
+
+	return c
+}
+
+// NewOperationCode returns an Operation Code.
+//
+// The code is encoded in two octets, the upper octet being the Operation Family
+// and the lower one being the Operation Specifier.
+func NewOperationCode(code int, isNational bool) *tcap.IE {
+	var tag = 17
+	if isNational {
+		tag = 16
+	}
+	return tcap.NewIE(tcap.NewPrivatePrimitiveTag(tag), []byte{uint8(code >> 8), uint8(code)})
+}
+
+// NewErrorCode returns an Error Code.
+func NewErrorCode(code int, isNational bool) *tcap.IE {
+	var tag = 20
+	if isNational {
+		tag = 19
+	}
+	return tcap.NewIE(tcap.NewPrivatePrimitiveTag(tag), []byte{uint8(code)})
+}
+
+// NewParameterSet returns a new Parameter Set with the given bytes as its value.
+func NewParameterSet(param []byte) *tcap.IE {
+	return tcap.NewIE(tcap.NewPrivateConstructorTag(18), param)
+}
+
+// MarshalBinary returns the byte sequence generated from a Components instance.
+func (c *Components) MarshalBinary() ([]byte, error) {
+	b := make([]byte, c.MarshalLen())
+	if err := c.MarshalTo(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// AppendBinary appends the byte sequence generated from a Components instance to b.
+func (c *Components) AppendBinary(b []byte) ([]byte, error) {
+	return appendBinary(b, c)
+}
+
+// WriteTo writes the byte sequence generated from a Components instance to w.
+func (c *Components) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(w, c)
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+func (c *Components) MarshalTo(b []byte) error {
+	if len(b) < c.MarshalLen() {
+		return io.ErrShortBuffer
+	}
+
+	lenBytes := tcap.MarshalAsn1ElementLength(c.Length)
+	b[0] = uint8(c.Tag)
+	copy(b[1:], lenBytes)
+
+	offset := 1 + len(lenBytes)
+	for _, comp := range c.Component {
+		compLen := comp.MarshalLen()
+		if err := comp.MarshalTo(b[offset : offset+compLen]); err != nil {
+			return err
+		}
+		offset += compLen
+	}
+	return nil
+}
+
+// MarshalBinary returns the byte sequence generated from a Component instance.
+func (c *Component) MarshalBinary() ([]byte, error) {
+	b := make([]byte, c.MarshalLen())
+	if err := c.MarshalTo(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// AppendBinary appends the byte sequence generated from a Component instance to b.
+func (c *Component) AppendBinary(b []byte) ([]byte, error) {
+	return appendBinary(b, c)
+}
+
+// WriteTo writes the byte sequence generated from a Component instance to w.
+func (c *Component) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(w, c)
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+func (c *Component) MarshalTo(b []byte) error {
+	if len(b) < c.MarshalLen() {
+		return io.ErrShortBuffer
+	}
+
+	lenBytes := tcap.MarshalAsn1ElementLength(c.Length)
+	b[0] = uint8(c.Type)
+	copy(b[1:], lenBytes)
+
+	offset := 1 + len(lenBytes)
+	for _, field := range c.fields() {
+		if err := field.MarshalTo(b[offset : offset+field.MarshalLen()]); err != nil {
+			return err
+		}
+		offset += field.MarshalLen()
+	}
+	return nil
+}
+
+// fields returns the non-nil fields in Component in the order of encoding.
+func (c *Component) fields() []*tcap.IE {
+	var fields []*tcap.IE
+	for _, field := range []*tcap.IE{c.ComponentID, c.OperationCode, c.ErrorCode, c.ProblemCode, c.Parameter} {
+		if field != nil {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// ParseComponents parses given byte sequence as an Components.
+func ParseComponents(b []byte) (*Components, error) {
+	c := &Components{}
+	if err := c.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// UnmarshalBinary sets the values retrieved from byte sequence in an Components.
+func (c *Components) UnmarshalBinary(b []byte) error {
+	if len(b) < 2 {
+		return io.ErrUnexpectedEOF
+	}
+
+	c.Tag = tcap.Tag(b[0])
+	valLen, lenByteCount, err := tcap.UnmarshalAsn1ElementLength(b)
+	if err != nil {
+		return err
+	}
+	c.Length = valLen
+
+	headerLen := 1 + lenByteCount
+	if len(b) < headerLen+valLen {
+		return io.ErrUnexpectedEOF
+	}
+
+	data := b[headerLen : headerLen+valLen]
+	for len(data) > 0 {
+		comp, err := ParseComponent(data)
+		if err != nil {
+			return err
+		}
+		c.Component = append(c.Component, comp)
+		data = data[comp.MarshalLen():]
+	}
+
+	return nil
+}
+
+// ParseComponent parses given byte sequence as an Component.
+func ParseComponent(b []byte) (*Component, error) {
+	c := &Component{}
+	if err := c.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// UnmarshalBinary sets the values retrieved from byte sequence in an Component.
+func (c *Component) UnmarshalBinary(b []byte) error {
+	if len(b) < 2 {
+		return io.ErrUnexpectedEOF
+	}
+
+	c.Type = tcap.Tag(b[0])
+	valLen, lenByteCount, err := tcap.UnmarshalAsn1ElementLength(b)
+	if err != nil {
+		return err
+	}
+	c.Length = valLen
+
+	headerLen := 1 + lenByteCount
+	if len(b) < headerLen+valLen {
+		return io.ErrUnexpectedEOF
+	}
+
+	data := b[headerLen : headerLen+valLen]
+	for len(data) > 0 {
+		ie, err := parseIE(data)
+		if err != nil {
+			return err
+		}
+
+		switch ie.Tag {
+		case 0xcf:
+			c.ComponentID = ie
+		case 0xd0, 0xd1:
+			c.OperationCode = ie
+		case 0xd3, 0xd4:
+			c.ErrorCode = ie
+		case 0xd5:
+			c.ProblemCode = ie
+		default:
+			// Parameter Set/Sequence is the last element in Component.
+			c.Parameter, err = tcap.ParseIERecursive(data)
+			if err != nil {
+				return err
+			}
+		}
+		data = data[ie.MarshalLen():]
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length of Components.
+func (c *Components) MarshalLen() int {
+	l := 0
+	for _, comp := range c.Component {
+		l += comp.MarshalLen()
+	}
+	return 1 + len(tcap.MarshalAsn1ElementLength(l)) + l
+}
+
+// MarshalLen returns the serial length of Component.
+func (c *Component) MarshalLen() int {
+	l := c.valueLen()
+	return 1 + len(tcap.MarshalAsn1ElementLength(l)) + l
+}
+
+// valueLen returns the length of the fields in Component.
+func (c *Component) valueLen() int {
+	l := 0
+	for _, field := range c.fields() {
+		l += field.MarshalLen()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (c *Components) SetLength() {
+	c.Length = 0
+	for _, comp := range c.Component {
+		comp.SetLength()
+		c.Length += comp.MarshalLen()
+	}
+}
+
+// SetLength sets the length in Length field.
+func (c *Component) SetLength() {
+	for _, field := range c.fields() {
+		field.SetLength()
+	}
+	c.Length = c.valueLen()
+}
+
+// ComponentTypeString returns the Component Type in string.
+func (c *Component) ComponentTypeString() string {
+	switch c.Type.Code() {
+	case InvokeLast:
+		return "invokeLast"
+	case ReturnResultLast:
+		return "returnResultLast"
+	case ReturnError:
+		return "returnError"
+	case Reject:
+		return "reject"
+	case InvokeNotLast:
+		return "invokeNotLast"
+	case ReturnResultNotLast:
+		return "returnResultNotLast"
+	}
+	return ""
+}
+
+// InvID returns the Invoke ID in Invoke Component.
+//
+// The second returned value is false if the Component does not have Invoke ID.
+func (c *Component) InvID() (uint8, bool) {
+	field := c.ComponentID
+	if field == nil || len(field.Value) == 0 {
+		return 0, false
+	}
+
+	switch c.Type.Code() {
+	case InvokeLast, InvokeNotLast:
+		return field.Value[0], true
+	}
+	return 0, false
+}
+
+// CorrID returns the Correlation ID in Component.
+//
+// The second returned value is false if the Component does not have Correlation ID.
+func (c *Component) CorrID() (uint8, bool) {
+	field := c.ComponentID
+	if field == nil || len(field.Value) == 0 {
+		return 0, false
+	}
+
+	switch c.Type.Code() {
+	case InvokeLast, InvokeNotLast:
+		if len(field.Value) < 2 {
+			return 0, false
+		}
+		return field.Value[1], true
+	}
+	return field.Value[0], true
+}
+
+// OpCode returns the Operation Code in Invoke Component.
+func (c *Component) OpCode() uint16 {
+	if field := c.OperationCode; field != nil && len(field.Value) == 2 {
+		return uint16(field.Value[0])<<8 | uint16(field.Value[1])
+	}
+	return 0
+}
+
+// ErrCode returns the Error Code in ReturnError Component.
+func (c *Component) ErrCode() uint8 {
+	if field := c.ErrorCode; field != nil && len(field.Value) > 0 {
+		return field.Value[0]
+	}
+	return 0
+}
+
+// Problem returns the Problem Type and Problem Specifier in Reject Component.
+func (c *Component) Problem() (problemType, problemSpecifier uint8) {
+	if field := c.ProblemCode; field != nil && len(field.Value) == 2 {
+		return field.Value[0], field.Value[1]
+	}
+	return 0, 0
+}
+
+// ProblemString returns the Problem Code in Reject Component in string.
+func (c *Component) ProblemString() string {
+	ptype, spec := c.Problem()
+	switch ptype {
+	case GeneralProblem:
+		switch spec {
+		case UnrecognizedComponentType:
+			return "General/UnrecognizedComponentType"
+		case IncorrectComponentPortion:
+			return "General/IncorrectComponentPortion"
+		case BadlyStructuredComponentPortion:
+			return "General/BadlyStructuredComponentPortion"
+		case IncorrectComponentCoding:
+			return "General/IncorrectComponentCoding"
+		}
+	case InvokeProblem:
+		switch spec {
+		case InvokeProblemDuplicateInvocation:
+			return "Invoke/DuplicateInvocation"
+		case InvokeProblemUnrecognizedOperationCode:
+			return "Invoke/UnrecognizedOperationCode"
+		case InvokeProblemIncorrectParameter:
+			return "Invoke/IncorrectParameter"
+		case InvokeProblemUnrecognizedCorrelationID:
+			return "Invoke/UnrecognizedCorrelationID"
+		}
+	case ReturnResultProblem:
+		switch spec {
+		case ResultProblemUnrecognizedCorrelationID:
+			return "ReturnResult/UnrecognizedCorrelationID"
+		case ResultProblemUnexpectedReturnResult:
+			return "ReturnResult/UnexpectedReturnResult"
+		case ResultProblemIncorrectParameter:
+			return "ReturnResult/IncorrectParameter"
+		}
+	case ReturnErrorProblem:
+		switch spec {
+		case ErrorProblemUnrecognizedCorrelationID:
+			return "ReturnError/UnrecognizedCorrelationID"
+		case ErrorProblemUnexpectedReturnError:
+			return "ReturnError/UnexpectedReturnError"
+		case ErrorProblemUnrecognizedError:
+			return "ReturnError/UnrecognizedError"
+		case ErrorProblemUnexpectedError:
+			return "ReturnError/UnexpectedError"
+		case ErrorProblemIncorrectParameter:
+			return "ReturnError/IncorrectParameter"
+		}
+	case TransactionPortionProblem:
+		switch spec {
+		case TransactionProblemUnrecognizedPackageType:
+			return "TransactionPortion/UnrecognizedPackageType"
+		case TransactionProblemIncorrectTransactionPortion:
+			return "TransactionPortion/IncorrectTransactionPortion"
+		case TransactionProblemBadlyStructuredTransactionPortion:
+			return "TransactionPortion/BadlyStructuredTransactionPortion"
+		case TransactionProblemUnassignedRespondingTransactionID:
+			return "TransactionPortion/UnassignedRespondingTransactionID"
+		case TransactionProblemPermissionToReleaseProblem:
+			return "TransactionPortion/PermissionToReleaseProblem"
+		case TransactionProblemResourceUnavailable:
+			return "TransactionPortion/ResourceUnavailable"
+		}
+	}
+	return ""
+}
+
+// String returns Components in human readable string.
+func (c *Components) String() string {
+	return fmt.Sprintf("{Tag: %#x, Length: %d, Component: %s}",
+		c.Tag,
+		c.Length,
+		c.Component,
+	)
+}
+
+// String returns Component in human readable string.
+func (c *Component) String() string {
+	return fmt.Sprintf("{Type: %#x, Length: %d, ComponentID: %s, OperationCode: %s, ErrorCode: %s, ProblemCode: %s, Parameter: %s}",
+		c.Type,
+		c.Length,
+		c.ComponentID,
+		c.OperationCode,
+		c.ErrorCode,
+		c.ProblemCode,
+		c.Parameter,
+	)
+}
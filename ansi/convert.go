@@ -0,0 +1,399 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ansi
+
+import (
+	"github.com/en-vee/go-tcap"
+)
+
+// Converter converts TCAP messages between ITU-T and ANSI where semantically possible.
+//
+// The Transaction IDs are carried as they are, the ITU-T Invoke/Linked IDs are mapped
+// to ANSI Invoke/Correlation IDs, and the P-Abort causes and Problem codes are mapped
+// to their counterparts. The Dialogue Portion is not converted.
+//
+// The zero value is ready to use.
+type Converter struct {
+	// OperationCodeToANSI converts ITU-T Operation Code into ANSI one.
+	// If nil, the local Operation Code is converted into the private one with the same value.
+	OperationCodeToANSI func(op *tcap.IE) (*tcap.IE, error)
+
+	// OperationCodeToITU converts ANSI Operation Code into ITU-T one.
+	// If nil, the Operation Code is converted into the local one with the same value.
+	OperationCodeToITU func(op *tcap.IE) (*tcap.IE, error)
+
+	// ResultOperationCode returns the ITU-T Operation Code to be put in ReturnResult that
+	// responds to the Invoke identified by invID.
+	//
+	// ANSI ReturnResult does not carry the Operation Code while ITU-T one requires it when
+	// the Parameter is present, so that the conversion fails if this is nil or returns false.
+	ResultOperationCode func(invID uint8) (*tcap.IE, bool)
+}
+
+// FromITU converts ITU-T TCAP into ANSI TCAP with the default Converter.
+func FromITU(t *tcap.TCAP) (*TCAP, error) {
+	return (&Converter{}).ToANSI(t)
+}
+
+// ToITU converts ANSI TCAP into ITU-T TCAP with the default Converter.
+func (t *TCAP) ToITU() (*tcap.TCAP, error) {
+	return (&Converter{}).ToITU(t)
+}
+
+// ToANSI converts ITU-T TCAP into ANSI TCAP.
+func (c *Converter) ToANSI(t *tcap.TCAP) (*TCAP, error) {
+	a := &TCAP{}
+	if tx := t.Transaction; tx != nil {
+		var err error
+		a.Transaction, err = c.transactionToANSI(tx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if comps := t.Components; comps != nil {
+		a.Components = NewComponents()
+		for _, comp := range comps.Component {
+			ac, err := c.componentToANSI(comp)
+			if err != nil {
+				return nil, err
+			}
+			a.Components.Component = append(a.Components.Component, ac)
+		}
+	}
+
+	a.SetLength()
+	return a, nil
+}
+
+// ToITU converts ANSI TCAP into ITU-T TCAP.
+func (c *Converter) ToITU(t *TCAP) (*tcap.TCAP, error) {
+	i := &tcap.TCAP{}
+	if tx := t.Transaction; tx != nil {
+		var err error
+		i.Transaction, err = c.transactionToITU(tx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if comps := t.Components; comps != nil {
+		i.Components = tcap.NewComponents()
+		for _, comp := range comps.Component {
+			ic, err := c.componentToITU(comp)
+			if err != nil {
+				return nil, err
+			}
+			i.Components.Component = append(i.Components.Component, ic)
+		}
+	}
+
+	i.SetLength()
+	return i, nil
+}
+
+func (c *Converter) transactionToANSI(tx *tcap.Transaction) (*Transaction, error) {
+	var a *Transaction
+	switch tx.Type.Code() {
+	case tcap.Unidirectional:
+		a = NewTransaction(Unidirectional, 0, 0, []byte{})
+	case tcap.Begin:
+		a = NewTransaction(QueryWithPermission, 0, 0, []byte{})
+		a.TransactionID.Value = valueOf(tx.OrigTransactionID)
+	case tcap.Continue:
+		a = NewTransaction(ConversationWithPermission, 0, 0, []byte{})
+		otid, dtid := valueOf(tx.OrigTransactionID), valueOf(tx.DestTransactionID)
+		// ANSI TCAP splits the Transaction ID into halves, so the IDs must be of the same length.
+		for len(otid) < len(dtid) {
+			otid = append([]byte{0}, otid...)
+		}
+		for len(dtid) < len(otid) {
+			dtid = append([]byte{0}, dtid...)
+		}
+		a.TransactionID.Value = append(otid, dtid...)
+	case tcap.End:
+		a = NewTransaction(Response, 0, 0, []byte{})
+		a.TransactionID.Value = valueOf(tx.DestTransactionID)
+	case tcap.Abort:
+		a = NewTransaction(Abort, 0, 0, []byte{})
+		a.TransactionID.Value = valueOf(tx.DestTransactionID)
+		if cause := tx.PAbortCause; cause != nil && len(cause.Value) > 0 {
+			ac, ok := pAbortCauseToANSI[cause.Value[0]]
+			if !ok {
+				return nil, &ConversionError{Field: "P-Abort Cause", Value: int(cause.Value[0])}
+			}
+			a.PAbortCause = tcap.NewIE(tcap.NewPrivatePrimitiveTag(23), []byte{ac})
+		} else {
+			a.UserAbortInformation = tcap.NewIE(tcap.NewPrivateConstructorTag(24), []byte{})
+		}
+	default:
+		return nil, &ConversionError{Field: "Message Type", Value: tx.Type.Code()}
+	}
+
+	a.SetLength()
+	return a, nil
+}
+
+func (c *Converter) transactionToITU(tx *Transaction) (*tcap.Transaction, error) {
+	var i *tcap.Transaction
+	switch tx.Type.Code() {
+	case Unidirectional:
+		i = tcap.NewUnidirectional([]byte{})
+	case QueryWithPermission, QueryWithoutPermission:
+		i = tcap.NewBegin(0, []byte{})
+		i.OrigTransactionID.Value = tx.origTransactionID()
+	case ConversationWithPermission, ConversationWithoutPermission:
+		i = tcap.NewContinue(0, 0, []byte{})
+		i.OrigTransactionID.Value = tx.origTransactionID()
+		i.DestTransactionID.Value = tx.respTransactionID()
+	case Response:
+		i = tcap.NewEnd(0, []byte{})
+		i.DestTransactionID.Value = tx.respTransactionID()
+	case Abort:
+		i = tcap.NewAbort(0, 0, []byte{})
+		i.DestTransactionID.Value = tx.respTransactionID()
+		i.PAbortCause = nil
+		if cause := tx.PAbortCause; cause != nil && len(cause.Value) > 0 {
+			ic, ok := pAbortCauseToITU[cause.Value[0]]
+			if !ok {
+				return nil, &ConversionError{Field: "P-Abort Cause", Value: int(cause.Value[0])}
+			}
+			i.PAbortCause = tcap.NewIE(tcap.NewApplicationWidePrimitiveTag(10), []byte{ic})
+		}
+	default:
+		return nil, &ConversionError{Field: "Package Type", Value: tx.Type.Code()}
+	}
+
+	i.SetLength()
+	return i, nil
+}
+
+func (c *Converter) componentToANSI(comp *tcap.Component) (*Component, error) {
+	var a *Component
+	invID := -1
+	if comp.InvokeID != nil && len(comp.InvokeID.Value) > 0 {
+		invID = int(comp.InvID())
+	}
+	switch comp.Type.Code() {
+	case tcap.Invoke:
+		corrID := -1
+		if lk, ok := comp.LinkedInvokeID(); ok {
+			corrID = int(uint8(lk))
+		}
+		a = NewInvoke(invID, corrID, 0, false, true, nil)
+
+		op, err := c.opCodeToANSI(comp.OperationCode)
+		if err != nil {
+			return nil, err
+		}
+		a.OperationCode = op
+	case tcap.ReturnResultLast, tcap.ReturnResultNotLast:
+		a = NewReturnResult(invID, comp.Type.Code() == tcap.ReturnResultLast, nil)
+	case tcap.ReturnError:
+		a = NewReturnError(invID, int(comp.OpCode()), false, nil)
+	case tcap.Reject:
+		if comp.ProblemCode == nil || len(comp.ProblemCode.Value) == 0 {
+			return nil, &ConversionError{Field: "Problem Code", Value: -1}
+		}
+		ptype, spec, ok := problemToANSI(comp.ProblemCode.Tag.Code(), comp.ProblemCode.Value[0])
+		if !ok {
+			return nil, &ConversionError{Field: "Problem Code", Value: int(comp.ProblemCode.Value[0])}
+		}
+		a = NewReject(invID, ptype, spec)
+	default:
+		return nil, &ConversionError{Field: "Component Type", Value: comp.Type.Code()}
+	}
+
+	if p := comp.Parameter; p != nil && comp.Type.Code() != tcap.Reject {
+		a.Parameter = NewParameterSet(p.Value)
+	}
+
+	a.SetLength()
+	return a, nil
+}
+
+func (c *Converter) componentToITU(comp *Component) (*tcap.Component, error) {
+	var i *tcap.Component
+	switch comp.Type.Code() {
+	case InvokeLast, InvokeNotLast:
+		invID, _ := comp.InvID()
+		i = tcap.NewInvoke(int(invID), -1, 0, true, nil)
+		if id, ok := comp.CorrID(); ok {
+			i.SetLinkedID(int(id))
+		}
+
+		op, err := c.opCodeToITU(comp.OperationCode)
+		if err != nil {
+			return nil, err
+		}
+		i.OperationCode = op
+	case ReturnResultLast, ReturnResultNotLast:
+		corrID, _ := comp.CorrID()
+		isLast := comp.Type.Code() == ReturnResultLast
+		if comp.Parameter == nil {
+			i = tcap.NewReturnResult(int(corrID), 0, true, isLast, nil)
+			i.ResultRetres = nil
+			i.OperationCode = nil
+			break
+		}
+
+		var op *tcap.IE
+		var ok bool
+		if c.ResultOperationCode != nil {
+			op, ok = c.ResultOperationCode(corrID)
+		}
+		if !ok {
+			return nil, &ConversionError{Field: "Operation Code in ReturnResult for Invoke ID", Value: int(corrID)}
+		}
+		i = tcap.NewReturnResult(int(corrID), 0, true, isLast, nil)
+		i.OperationCode = op
+	case ReturnError:
+		corrID, _ := comp.CorrID()
+		i = tcap.NewReturnError(int(corrID), int(comp.ErrCode()), true, nil)
+	case Reject:
+		ptype, spec := comp.Problem()
+		itype, icode, ok := problemToITU(ptype, spec)
+		if !ok {
+			return nil, &ConversionError{Field: "Problem Code", Value: int(ptype)<<8 | int(spec)}
+		}
+		corrID, ok := comp.CorrID()
+		i = tcap.NewReject(int(corrID), itype, icode, nil)
+		if !ok {
+			// NULL is used when the Invoke ID is not available.
+			i.InvokeID = &tcap.IE{Tag: tcap.NewUniversalPrimitiveTag(5)}
+		}
+	default:
+		return nil, &ConversionError{Field: "Component Type", Value: comp.Type.Code()}
+	}
+
+	if p := comp.Parameter; p != nil && comp.Type.Code() != Reject {
+		i.Parameter = tcap.NewIE(tcap.NewUniversalConstructorTag(0x10), p.Value)
+	}
+
+	i.SetLength()
+	return i, nil
+}
+
+func (c *Converter) opCodeToANSI(op *tcap.IE) (*tcap.IE, error) {
+	if c.OperationCodeToANSI != nil {
+		return c.OperationCodeToANSI(op)
+	}
+
+	if op == nil || op.Tag != tcap.NewUniversalPrimitiveTag(2) {
+		return nil, &ConversionError{Field: "global Operation Code", Value: -1}
+	}
+	code := 0
+	for _, b := range op.Value {
+		code = code<<8 | int(b)
+	}
+	return NewOperationCode(code, false), nil
+}
+
+func (c *Converter) opCodeToITU(op *tcap.IE) (*tcap.IE, error) {
+	if c.OperationCodeToITU != nil {
+		return c.OperationCodeToITU(op)
+	}
+
+	if op == nil {
+		return nil, &ConversionError{Field: "Operation Code", Value: -1}
+	}
+	code := 0
+	for _, b := range op.Value {
+		code = code<<8 | int(b)
+	}
+
+	// INTEGER is encoded in the minimum number of octets in two's complement.
+	v := []byte{uint8(code)}
+	for code >>= 8; code > 0; code >>= 8 {
+		v = append([]byte{uint8(code)}, v...)
+	}
+	if v[0]&0x80 != 0 {
+		v = append([]byte{0}, v...)
+	}
+	return tcap.NewIE(tcap.NewUniversalPrimitiveTag(2), v), nil
+}
+
+// valueOf returns a copy of the Value in IE, or nil if IE is nil.
+func valueOf(i *tcap.IE) []byte {
+	if i == nil {
+		return nil
+	}
+	return append([]byte{}, i.Value...)
+}
+
+var pAbortCauseToANSI = map[uint8]uint8{
+	tcap.UnrecognizedMessageType:          UnrecognizedPackageType,
+	tcap.UnrecognizedTransactionID:        UnassignedRespondingTransactionID,
+	tcap.BadlyFormattedTransactionPortion: BadlyStructuredTransactionPortion,
+	tcap.IncorrectTransactionPortion:      IncorrectTransactionPortion,
+	tcap.ResourceLimitation:               ResourceUnavailable,
+}
+
+var pAbortCauseToITU = map[uint8]uint8{
+	UnrecognizedPackageType:           tcap.UnrecognizedMessageType,
+	UnassignedRespondingTransactionID: tcap.UnrecognizedTransactionID,
+	BadlyStructuredTransactionPortion: tcap.BadlyFormattedTransactionPortion,
+	IncorrectTransactionPortion:       tcap.IncorrectTransactionPortion,
+	ResourceUnavailable:               tcap.ResourceLimitation,
+}
+
+// invokeProblemToANSI maps ITU-T Invoke Problem codes, which are not in the same order as ANSI ones.
+var invokeProblemToANSI = map[uint8]uint8{
+	tcap.InvokeProblemDuplicateInvokeID:     InvokeProblemDuplicateInvocation,
+	tcap.InvokeProblemUnrecognizedOperation: InvokeProblemUnrecognizedOperationCode,
+	tcap.InvokeProblemMistypedParameter:     InvokeProblemIncorrectParameter,
+	tcap.InvokeProblemUnrecognizedLinkedID:  InvokeProblemUnrecognizedCorrelationID,
+}
+
+// problemToANSI maps ITU-T Problem Type (tag code) and Problem Code into ANSI ones.
+//
+// ITU-T General, ReturnResult and ReturnError Problem codes start from 0 while ANSI ones
+// start from 1, and are in the same order.
+func problemToANSI(ptype int, code uint8) (uint8, uint8, bool) {
+	switch ptype {
+	case tcap.GeneralProblem:
+		if code <= tcap.BadlyStructuredComponent {
+			return GeneralProblem, code + 1, true
+		}
+	case tcap.InvokeProblem:
+		if spec, ok := invokeProblemToANSI[code]; ok {
+			return InvokeProblem, spec, true
+		}
+	case tcap.ReturnResultProblem:
+		if code <= tcap.ResultProblemMistypedParameter {
+			return ReturnResultProblem, code + 1, true
+		}
+	case tcap.ReturnErrorProblem:
+		if code <= tcap.ErrorProblemMistypedParameter {
+			return ReturnErrorProblem, code + 1, true
+		}
+	}
+	return 0, 0, false
+}
+
+// problemToITU maps ANSI Problem Type and Specifier into ITU-T ones.
+func problemToITU(ptype, spec uint8) (int, uint8, bool) {
+	switch ptype {
+	case GeneralProblem:
+		if spec >= UnrecognizedComponentType && spec <= BadlyStructuredComponentPortion {
+			return tcap.GeneralProblem, spec - 1, true
+		}
+	case InvokeProblem:
+		for itu, ansi := range invokeProblemToANSI {
+			if ansi == spec {
+				return tcap.InvokeProblem, itu, true
+			}
+		}
+	case ReturnResultProblem:
+		if spec >= ResultProblemUnrecognizedCorrelationID && spec <= ResultProblemIncorrectParameter {
+			return tcap.ReturnResultProblem, spec - 1, true
+		}
+	case ReturnErrorProblem:
+		if spec >= ErrorProblemUnrecognizedCorrelationID && spec <= ErrorProblemIncorrectParameter {
+			return tcap.ReturnErrorProblem, spec - 1, true
+		}
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,119 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ansi_test
+
+import (
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/en-vee/go-tcap/ansi"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestConverter(t *testing.T) {
+	t.Run("Begin/Invoke", func(t *testing.T) {
+		itu := tcap.NewBeginInvoke(0x11111111, 1, 15, []byte{0x04, 0x01, 0x00})
+		a, err := ansi.FromITU(itu)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got, want := a, ansi.NewQueryWithPermissionInvoke(0x11111111, 1, 15, []byte{0x04, 0x01, 0x00}); !verify.Values(t, "", got, want) {
+			t.Fail()
+		}
+
+		back, err := a.ToITU()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := back.OTID(), itu.OTID(); got != want {
+			t.Errorf("OTID: got %x, want %x", got, want)
+		}
+		if got, want := back.OpCode(), itu.OpCode(); !verify.Values(t, "OpCode", got, want) {
+			t.Fail()
+		}
+	})
+
+	t.Run("Continue", func(t *testing.T) {
+		itu := tcap.NewContinueInvoke(0x11111111, 0x22222222, 1, 15, nil)
+		a, err := ansi.FromITU(itu)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := a.OTID(), uint32(0x11111111); got != want {
+			t.Errorf("OTID: got %x, want %x", got, want)
+		}
+		if got, want := a.RTID(), uint32(0x22222222); got != want {
+			t.Errorf("RTID: got %x, want %x", got, want)
+		}
+	})
+
+	t.Run("Response/ReturnResult", func(t *testing.T) {
+		a := ansi.NewResponseReturnResult(0x22222222, 1, []byte{0xde, 0xad})
+		if _, err := a.ToITU(); err == nil {
+			t.Fatal("expected error without ResultOperationCode")
+		}
+
+		conv := &ansi.Converter{
+			ResultOperationCode: func(invID uint8) (*tcap.IE, bool) {
+				return tcap.NewOperationCode(15, true), true
+			},
+		}
+		itu, err := conv.ToITU(a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := itu.Transaction.MessageTypeString(), "End"; got != want {
+			t.Errorf("got %s, want %s", got, want)
+		}
+		if got, want := itu.OpCode(), []uint8{15}; !verify.Values(t, "OpCode", got, want) {
+			t.Fail()
+		}
+	})
+
+	t.Run("Abort", func(t *testing.T) {
+		a, err := ansi.FromITU(&tcap.TCAP{Transaction: tcap.NewAbort(0x22222222, tcap.ResourceLimitation, nil)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := a.Transaction.AbortCause(), "ResourceUnavailable"; got != want {
+			t.Errorf("got %s, want %s", got, want)
+		}
+	})
+
+	t.Run("Reject", func(t *testing.T) {
+		a := &ansi.TCAP{
+			Transaction: ansi.NewResponse(0x22222222, nil),
+			Components:  ansi.NewComponents(ansi.NewReject(1, ansi.InvokeProblem, ansi.InvokeProblemUnrecognizedOperationCode)),
+		}
+		itu, err := a.ToITU()
+		if err != nil {
+			t.Fatal(err)
+		}
+		comp := itu.Components.Component[0]
+		if got, want := comp.ComponentTypeString(), "reject"; got != want {
+			t.Errorf("got %s, want %s", got, want)
+		}
+		if got, want := comp.ProblemCode.Value[0], tcap.InvokeProblemUnrecognizedOperation; got != want {
+			t.Errorf("got %d, want %d", got, want)
+		}
+	})
+
+	t.Run("Reject/NotDerivable", func(t *testing.T) {
+		itu := tcap.NewEndMessage(tcap.WithDTID(0x22222222),
+			tcap.WithComponents(tcap.NewRejectNotDerivable(tcap.ProblemBadlyStructuredComponent)))
+		a, err := ansi.FromITU(itu)
+		if err != nil {
+			t.Fatal(err)
+		}
+		comp := a.Components.Component[0]
+		if _, ok := comp.CorrID(); ok {
+			t.Error("got Correlation ID, want none")
+		}
+		if got, want := comp.ComponentID.Value, []byte{}; !verify.Values(t, "Component ID", got, want) {
+			t.Fail()
+		}
+	})
+}
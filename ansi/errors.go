@@ -0,0 +1,29 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ansi
+
+import "fmt"
+
+// ConversionError indicates that the value cannot be converted between ITU-T and ANSI TCAP.
+type ConversionError struct {
+	Field string
+	Value int
+}
+
+// Error returns error message with violating content.
+func (e *ConversionError) Error() string {
+	return fmt.Sprintf("ansi: cannot convert %s: %d", e.Field, e.Value)
+}
+
+// InvalidOperationError indicates that the Operation Code or its name cannot be registered.
+type InvalidOperationError struct {
+	Code int
+	Name string
+}
+
+// Error returns error message with violating content.
+func (e *InvalidOperationError) Error() string {
+	return fmt.Sprintf("ansi: invalid operation %q: %d", e.Name, e.Code)
+}
@@ -0,0 +1,326 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ansi
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/en-vee/go-tcap"
+)
+
+// Operation Family definitions for national Operation Codes (T1.114).
+const (
+	FamilyParameter uint8 = iota + 1
+	FamilyCharging
+	FamilyProvideInstructions
+	FamilyConnectionControl
+	FamilyCallerInteraction
+	FamilySendNotification
+	FamilyNetworkManagement
+	FamilyProcedural
+	FamilyOperationControl
+	FamilyReportEvent
+	FamilyMiscellaneous uint8 = 126
+)
+
+// FamilyIS41 is the Operation Family used in private Operation Codes of IS-41 (ANSI-41) MAP and WIN.
+const FamilyIS41 uint8 = 9
+
+// replyRequired is the bit in Operation Family that indicates the reply is required.
+const replyRequired uint8 = 0x80
+
+// IS-41 (ANSI-41) Operation Specifier definitions.
+const (
+	OpHandoffMeasurementRequest uint8 = iota + 1
+	OpFacilitiesDirective
+	OpMobileOnChannel
+	OpHandoffBack
+	OpFacilitiesRelease
+	OpQualificationRequest
+	OpQualificationDirective
+	OpBlocking
+	OpUnblocking
+	OpResetCircuit
+	OpTrunkTest
+	OpTrunkTestDisconnect
+	OpRegistrationNotification
+	OpRegistrationCancellation
+	OpLocationRequest
+	OpRoutingRequest
+	OpFeatureRequest
+	_
+	_
+	OpUnreliableRoamerDataDirective
+	_
+	OpMSInactive
+	OpTransferToNumberRequest
+	OpRedirectionRequest
+	OpHandoffToThird
+	OpFlashRequest
+	OpAuthenticationDirective
+	OpAuthenticationRequest
+	OpBaseStationChallenge
+	OpAuthenticationFailureReport
+	OpCountRequest
+	OpInterSystemPage
+	OpUnsolicitedResponse
+	OpBulkDeregistration
+	OpHandoffMeasurementRequest2
+	OpFacilitiesDirective2
+	OpHandoffBack2
+	OpHandoffToThird2
+	OpAuthenticationDirectiveForward
+	OpAuthenticationStatusReport
+	_
+	OpInformationDirective
+	OpInformationForward
+	OpInterSystemAnswer
+	OpInterSystemPage2
+	OpInterSystemSetup
+	OpOriginationRequest
+	OpRandomVariableRequest
+	OpRedirectionDirective
+	OpRemoteUserInteractionDirective
+	OpSMSDeliveryBackward
+	OpSMSDeliveryForward
+	OpSMSDeliveryPointToPoint
+	OpSMSNotification
+	OpSMSRequest
+)
+
+// WIN (Wireless Intelligent Network) Operation Specifier definitions.
+const (
+	OpAnalyzedInformation uint8 = iota + 64
+	OpConnectionFailureReport
+	OpConnectResource
+	OpDisconnectResource
+	OpFacilitySelectedAndAvailable
+	OpInstructionRequest
+	OpModify
+	OpResetTimer
+	OpSearch
+	OpSeizeResource
+	OpSRFDirective
+	OpTBusy
+	OpTNoAnswer
+)
+
+var (
+	builtinOpNames = map[bool]map[uint16]string{
+		// national
+		true: {
+			uint16(FamilyParameter)<<8 | 1:           "ProvideValue",
+			uint16(FamilyParameter)<<8 | 2:           "SetValue",
+			uint16(FamilyCharging)<<8 | 1:            "BillCall",
+			uint16(FamilyProvideInstructions)<<8 | 1: "Start",
+			uint16(FamilyProvideInstructions)<<8 | 2: "Assist",
+			uint16(FamilyConnectionControl)<<8 | 1:   "Connect",
+			uint16(FamilyConnectionControl)<<8 | 2:   "TemporaryConnect",
+			uint16(FamilyConnectionControl)<<8 | 3:   "Disconnect",
+			uint16(FamilyConnectionControl)<<8 | 4:   "ForwardDisconnect",
+			uint16(FamilyCallerInteraction)<<8 | 1:   "PlayAnnouncement",
+			uint16(FamilyCallerInteraction)<<8 | 2:   "PlayAnnouncementAndCollectDigits",
+			uint16(FamilyCallerInteraction)<<8 | 3:   "IndicateInformationWaiting",
+			uint16(FamilyCallerInteraction)<<8 | 4:   "IndicateInformationProvided",
+			uint16(FamilySendNotification)<<8 | 1:    "WhenPartyFree",
+			uint16(FamilyNetworkManagement)<<8 | 1:   "AutomaticCodeGap",
+			uint16(FamilyProcedural)<<8 | 1:          "TemporaryHandover",
+			uint16(FamilyProcedural)<<8 | 2:          "ReportAssistTermination",
+			uint16(FamilyProcedural)<<8 | 3:          "Security",
+			uint16(FamilyOperationControl)<<8 | 1:    "Cancel",
+			uint16(FamilyReportEvent)<<8 | 1:         "VoiceMessageAvailable",
+			uint16(FamilyReportEvent)<<8 | 2:         "VoiceMessageRetrieved",
+			uint16(FamilyMiscellaneous)<<8 | 1:       "QueueCall",
+			uint16(FamilyMiscellaneous)<<8 | 2:       "DequeueCall",
+		},
+		// private
+		false: {
+			is41(OpHandoffMeasurementRequest):      "HandoffMeasurementRequest",
+			is41(OpFacilitiesDirective):            "FacilitiesDirective",
+			is41(OpMobileOnChannel):                "MobileOnChannel",
+			is41(OpHandoffBack):                    "HandoffBack",
+			is41(OpFacilitiesRelease):              "FacilitiesRelease",
+			is41(OpQualificationRequest):           "QualificationRequest",
+			is41(OpQualificationDirective):         "QualificationDirective",
+			is41(OpBlocking):                       "Blocking",
+			is41(OpUnblocking):                     "Unblocking",
+			is41(OpResetCircuit):                   "ResetCircuit",
+			is41(OpTrunkTest):                      "TrunkTest",
+			is41(OpTrunkTestDisconnect):            "TrunkTestDisconnect",
+			is41(OpRegistrationNotification):       "RegistrationNotification",
+			is41(OpRegistrationCancellation):       "RegistrationCancellation",
+			is41(OpLocationRequest):                "LocationRequest",
+			is41(OpRoutingRequest):                 "RoutingRequest",
+			is41(OpFeatureRequest):                 "FeatureRequest",
+			is41(OpUnreliableRoamerDataDirective):  "UnreliableRoamerDataDirective",
+			is41(OpMSInactive):                     "MSInactive",
+			is41(OpTransferToNumberRequest):        "TransferToNumberRequest",
+			is41(OpRedirectionRequest):             "RedirectionRequest",
+			is41(OpHandoffToThird):                 "HandoffToThird",
+			is41(OpFlashRequest):                   "FlashRequest",
+			is41(OpAuthenticationDirective):        "AuthenticationDirective",
+			is41(OpAuthenticationRequest):          "AuthenticationRequest",
+			is41(OpBaseStationChallenge):           "BaseStationChallenge",
+			is41(OpAuthenticationFailureReport):    "AuthenticationFailureReport",
+			is41(OpCountRequest):                   "CountRequest",
+			is41(OpInterSystemPage):                "InterSystemPage",
+			is41(OpUnsolicitedResponse):            "UnsolicitedResponse",
+			is41(OpBulkDeregistration):             "BulkDeregistration",
+			is41(OpHandoffMeasurementRequest2):     "HandoffMeasurementRequest2",
+			is41(OpFacilitiesDirective2):           "FacilitiesDirective2",
+			is41(OpHandoffBack2):                   "HandoffBack2",
+			is41(OpHandoffToThird2):                "HandoffToThird2",
+			is41(OpAuthenticationDirectiveForward): "AuthenticationDirectiveForward",
+			is41(OpAuthenticationStatusReport):     "AuthenticationStatusReport",
+			is41(OpInformationDirective):           "InformationDirective",
+			is41(OpInformationForward):             "InformationForward",
+			is41(OpInterSystemAnswer):              "InterSystemAnswer",
+			is41(OpInterSystemPage2):               "InterSystemPage2",
+			is41(OpInterSystemSetup):               "InterSystemSetup",
+			is41(OpOriginationRequest):             "OriginationRequest",
+			is41(OpRandomVariableRequest):          "RandomVariableRequest",
+			is41(OpRedirectionDirective):           "RedirectionDirective",
+			is41(OpRemoteUserInteractionDirective): "RemoteUserInteractionDirective",
+			is41(OpSMSDeliveryBackward):            "SMSDeliveryBackward",
+			is41(OpSMSDeliveryForward):             "SMSDeliveryForward",
+			is41(OpSMSDeliveryPointToPoint):        "SMSDeliveryPointToPoint",
+			is41(OpSMSNotification):                "SMSNotification",
+			is41(OpSMSRequest):                     "SMSRequest",
+			is41(OpAnalyzedInformation):            "AnalyzedInformation",
+			is41(OpConnectionFailureReport):        "ConnectionFailureReport",
+			is41(OpConnectResource):                "ConnectResource",
+			is41(OpDisconnectResource):             "DisconnectResource",
+			is41(OpFacilitySelectedAndAvailable):   "FacilitySelectedAndAvailable",
+			is41(OpInstructionRequest):             "InstructionRequest",
+			is41(OpModify):                         "Modify",
+			is41(OpResetTimer):                     "ResetTimer",
+			is41(OpSearch):                         "Search",
+			is41(OpSeizeResource):                  "SeizeResource",
+			is41(OpSRFDirective):                   "SRFDirective",
+			is41(OpTBusy):                          "TBusy",
+			is41(OpTNoAnswer):                      "TNoAnswer",
+		},
+	}
+	opNames   = cloneOpNames(builtinOpNames)
+	opNamesMu sync.RWMutex
+)
+
+// cloneOpNames returns a copy of the names of Operation Codes.
+func cloneOpNames(names map[bool]map[uint16]string) map[bool]map[uint16]string {
+	m := map[bool]map[uint16]string{true: {}, false: {}}
+	for isNational, codes := range names {
+		for code, name := range codes {
+			m[isNational][code] = name
+		}
+	}
+	return m
+}
+
+// is41 returns the Operation Code of IS-41 (ANSI-41) from the Operation Specifier.
+func is41(spec uint8) uint16 {
+	return uint16(FamilyIS41)<<8 | uint16(spec)
+}
+
+// OperationCode returns the Operation Code from the Operation Family and Specifier.
+//
+// The reply required indicator is not included in the returned value.
+func OperationCode(family, specifier uint8) int {
+	return int(family&^replyRequired)<<8 | int(specifier)
+}
+
+// NewOperationCodeWithFamily returns an Operation Code built from the Operation Family and Specifier.
+func NewOperationCodeWithFamily(family, specifier uint8, isNational, isReplyRequired bool) *tcap.IE {
+	if isReplyRequired {
+		family |= replyRequired
+	}
+	return NewOperationCode(int(family)<<8|int(specifier), isNational)
+}
+
+// RegisterOperation registers the name of an Operation Code, which is used in OpCodeString.
+//
+// The reply required indicator in code is ignored. Registering the name for the
+// code that is already registered overrides the existing one.
+func RegisterOperation(code int, isNational bool, name string) {
+	opNamesMu.Lock()
+	defer opNamesMu.Unlock()
+
+	opNames[isNational][uint16(code)&^(uint16(replyRequired)<<8)] = name
+}
+
+// ReplaceOperations replaces all the names registered with RegisterOperation with the ones
+// given at once, keyed by Operation Code, so that the set of operations can be reloaded at runtime.
+//
+// The built-in names are kept unless overridden. Nothing is changed if any of the
+// codes does not fit in 16 bits or any of the names is empty.
+func ReplaceOperations(national, private map[int]string) error {
+	names := cloneOpNames(builtinOpNames)
+	for isNational, codes := range map[bool]map[int]string{true: national, false: private} {
+		for code, name := range codes {
+			if code < 0 || code > 0xffff || name == "" {
+				return &InvalidOperationError{Code: code, Name: name}
+			}
+			names[isNational][uint16(code)&^(uint16(replyRequired)<<8)] = name
+		}
+	}
+
+	opNamesMu.Lock()
+	defer opNamesMu.Unlock()
+
+	opNames = names
+	return nil
+}
+
+// OperationName returns the name of the Operation Code registered.
+//
+// It returns empty string if the code is not known.
+func OperationName(code int, isNational bool) string {
+	opNamesMu.RLock()
+	defer opNamesMu.RUnlock()
+
+	return opNames[isNational][uint16(code)&^(uint16(replyRequired)<<8)]
+}
+
+// IsNational reports whether the Operation Code in Component is National TCAP one.
+func (c *Component) IsNational() bool {
+	if field := c.OperationCode; field != nil {
+		return field.Tag == tcap.NewPrivatePrimitiveTag(16)
+	}
+	if field := c.ErrorCode; field != nil {
+		return field.Tag == tcap.NewPrivatePrimitiveTag(19)
+	}
+	return false
+}
+
+// OpFamily returns the Operation Family in Invoke Component.
+//
+// The reply required indicator is not included in the returned value.
+func (c *Component) OpFamily() uint8 {
+	return uint8(c.OpCode()>>8) &^ replyRequired
+}
+
+// OpSpecifier returns the Operation Specifier in Invoke Component.
+func (c *Component) OpSpecifier() uint8 {
+	return uint8(c.OpCode())
+}
+
+// IsReplyRequired reports whether the reply required indicator is set in the Operation Family.
+func (c *Component) IsReplyRequired() bool {
+	return uint8(c.OpCode()>>8)&replyRequired != 0
+}
+
+// OpCodeString returns the name of Operation Code in Invoke Component.
+//
+// If the name is not registered, it returns the Operation Family and Specifier in numbers.
+func (c *Component) OpCodeString() string {
+	if c.OperationCode == nil {
+		return ""
+	}
+
+	if name := OperationName(int(c.OpCode()), c.IsNational()); name != "" {
+		return name
+	}
+	return fmt.Sprintf("%d/%d", c.OpFamily(), c.OpSpecifier())
+}
@@ -0,0 +1,56 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ansi_test
+
+import (
+	"testing"
+
+	"github.com/en-vee/go-tcap/ansi"
+)
+
+func TestOperation(t *testing.T) {
+	c := ansi.NewInvoke(1, -1, 0, false, true, nil)
+	c.OperationCode = ansi.NewOperationCodeWithFamily(ansi.FamilyIS41, ansi.OpLocationRequest, false, true)
+
+	if got, want := c.OpFamily(), ansi.FamilyIS41; got != want {
+		t.Errorf("OpFamily: got %d, want %d", got, want)
+	}
+	if !c.IsReplyRequired() {
+		t.Error("IsReplyRequired: got false, want true")
+	}
+	if got, want := c.OpCodeString(), "LocationRequest"; got != want {
+		t.Errorf("OpCodeString: got %s, want %s", got, want)
+	}
+
+	c.OperationCode = ansi.NewOperationCode(ansi.OperationCode(ansi.FamilyIS41, 200), false)
+	if got, want := c.OpCodeString(), "9/200"; got != want {
+		t.Errorf("OpCodeString: got %s, want %s", got, want)
+	}
+
+	ansi.RegisterOperation(ansi.OperationCode(ansi.FamilyIS41, 200), false, "VendorSpecific")
+	if got, want := c.OpCodeString(), "VendorSpecific"; got != want {
+		t.Errorf("OpCodeString: got %s, want %s", got, want)
+	}
+
+	if err := ansi.ReplaceOperations(nil, map[int]string{ansi.OperationCode(ansi.FamilyIS41, 201): "Other"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := c.OpCodeString(), "9/200"; got != want {
+		t.Errorf("OpCodeString after replace: got %s, want %s", got, want)
+	}
+	if got, want := ansi.OperationName(ansi.OperationCode(ansi.FamilyIS41, 201), false), "Other"; got != want {
+		t.Errorf("OperationName: got %s, want %s", got, want)
+	}
+	if got, want := ansi.OperationName(ansi.OperationCode(ansi.FamilyIS41, ansi.OpLocationRequest), false), "LocationRequest"; got != want {
+		t.Errorf("built-in OperationName: got %s, want %s", got, want)
+	}
+
+	if err := ansi.ReplaceOperations(nil, map[int]string{0x10000: "TooLarge"}); err == nil {
+		t.Error("ReplaceOperations: expected error for invalid code")
+	}
+	if got, want := ansi.OperationName(ansi.OperationCode(ansi.FamilyIS41, 201), false), "Other"; got != want {
+		t.Errorf("OperationName after failed replace: got %s, want %s", got, want)
+	}
+}
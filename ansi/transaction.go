@@ -0,0 +1,399 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ansi
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/en-vee/go-tcap"
+)
+
+// Package Type definitions.
+const (
+	Unidirectional int = iota + 1
+	QueryWithPermission
+	QueryWithoutPermission
+	Response
+	ConversationWithPermission
+	ConversationWithoutPermission
+	Abort int = 22
+)
+
+// P-Abort Cause definitions.
+const (
+	UnrecognizedPackageType uint8 = iota + 1
+	IncorrectTransactionPortion
+	BadlyStructuredTransactionPortion
+	UnassignedRespondingTransactionID
+	PermissionToReleaseProblem
+	ResourceUnavailable
+	UnrecognizedDialoguePortionID
+	BadlyStructuredDialoguePortion
+	MissingDialoguePortion
+	InconsistentDialoguePortion
+)
+
+// Transaction represents a Transaction Portion of ANSI TCAP.
+//
+// Unlike ITU-T, ANSI TCAP carries the Originating and Responding Transaction IDs
+// in a single Transaction ID element, whose length is determined by the Package Type.
+type Transaction struct {
+	Type                 tcap.Tag
+	Length               int
+	TransactionID        *tcap.IE
+	PAbortCause          *tcap.IE
+	UserAbortInformation *tcap.IE
+	Payload              []byte
+}
+
+// NewTransaction returns a new Transaction Portion.
+//
+// The otid and rtid are put in the Transaction ID only when the Package Type given requires them.
+func NewTransaction(ptype int, otid, rtid uint32, payload []byte) *Transaction {
+	t := &Transaction{
+		Type: tcap.NewPrivateConstructorTag(ptype),
+		TransactionID: &tcap.IE{
+			Tag: tcap.NewPrivatePrimitiveTag(7),
+		},
+		Payload: payload,
+	}
+
+	switch ptype {
+	case QueryWithPermission, QueryWithoutPermission:
+		t.TransactionID.Value = make([]byte, 4)
+		binary.BigEndian.PutUint32(t.TransactionID.Value, otid)
+	case Response, Abort:
+		t.TransactionID.Value = make([]byte, 4)
+		binary.BigEndian.PutUint32(t.TransactionID.Value, rtid)
+	case ConversationWithPermission, ConversationWithoutPermission:
+		t.TransactionID.Value = make([]byte, 8)
+		binary.BigEndian.PutUint32(t.TransactionID.Value[:4], otid)
+		binary.BigEndian.PutUint32(t.TransactionID.Value[4:], rtid)
+	}
+	t.SetLength()
+
+	return t
+}
+
+// NewQueryWithPermission returns QueryWithPermission type of Transaction Portion.
+func NewQueryWithPermission(otid uint32, payload []byte) *Transaction {
+	return NewTransaction(QueryWithPermission, otid, 0, payload)
+}
+
+// NewQueryWithoutPermission returns QueryWithoutPermission type of Transaction Portion.
+func NewQueryWithoutPermission(otid uint32, payload []byte) *Transaction {
+	return NewTransaction(QueryWithoutPermission, otid, 0, payload)
+}
+
+// NewResponse returns Response type of Transaction Portion.
+func NewResponse(rtid uint32, payload []byte) *Transaction {
+	return NewTransaction(Response, 0, rtid, payload)
+}
+
+// NewConversationWithPermission returns ConversationWithPermission type of Transaction Portion.
+func NewConversationWithPermission(otid, rtid uint32, payload []byte) *Transaction {
+	return NewTransaction(ConversationWithPermission, otid, rtid, payload)
+}
+
+// NewConversationWithoutPermission returns ConversationWithoutPermission type of Transaction Portion.
+func NewConversationWithoutPermission(otid, rtid uint32, payload []byte) *Transaction {
+	return NewTransaction(ConversationWithoutPermission, otid, rtid, payload)
+}
+
+// NewAbort returns Abort type of Transaction Portion with P-Abort Cause.
+func NewAbort(rtid uint32, cause uint8, payload []byte) *Transaction {
+	t := NewTransaction(Abort, 0, rtid, payload)
+	t.PAbortCause = &tcap.IE{
+		Tag:   tcap.NewPrivatePrimitiveTag(23),
+		Value: []byte{cause},
+	}
+	t.SetLength()
+
+	return t
+}
+
+// NewUserAbort returns Abort type of Transaction Portion with User Abort Information.
+//
+// The info is expected to be the encoded EXTERNAL, which is put in the User Abort Information as it is.
+func NewUserAbort(rtid uint32, info, payload []byte) *Transaction {
+	t := NewTransaction(Abort, 0, rtid, payload)
+	t.UserAbortInformation = &tcap.IE{
+		Tag:   tcap.NewPrivateConstructorTag(24),
+		Value: info,
+	}
+	t.SetLength()
+
+	return t
+}
+
+// MarshalBinary returns the byte sequence generated from a Transaction instance.
+func (t *Transaction) MarshalBinary() ([]byte, error) {
+	b := make([]byte, t.MarshalLen())
+	if err := t.MarshalTo(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// AppendBinary appends the byte sequence generated from a Transaction instance to b.
+func (t *Transaction) AppendBinary(b []byte) ([]byte, error) {
+	return appendBinary(b, t)
+}
+
+// WriteTo writes the byte sequence generated from a Transaction instance to w.
+func (t *Transaction) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(w, t)
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+func (t *Transaction) MarshalTo(b []byte) error {
+	l := t.MarshalLen()
+	if len(b) < l {
+		return io.ErrShortBuffer
+	}
+
+	lenBytes := tcap.MarshalAsn1ElementLength(t.Length)
+	b[0] = uint8(t.Type)
+	copy(b[1:], lenBytes)
+	offset := 1 + len(lenBytes)
+
+	if field := t.TransactionID; field != nil {
+		if err := field.MarshalTo(b[offset : offset+field.MarshalLen()]); err != nil {
+			return err
+		}
+		offset += field.MarshalLen()
+	}
+
+	if field := t.PAbortCause; field != nil {
+		if err := field.MarshalTo(b[offset : offset+field.MarshalLen()]); err != nil {
+			return err
+		}
+		offset += field.MarshalLen()
+	}
+
+	if field := t.UserAbortInformation; field != nil {
+		if err := field.MarshalTo(b[offset : offset+field.MarshalLen()]); err != nil {
+			return err
+		}
+		offset += field.MarshalLen()
+	}
+
+	copy(b[offset:l], t.Payload)
+	return nil
+}
+
+// ParseTransaction parses given byte sequence as an Transaction.
+func ParseTransaction(b []byte) (*Transaction, error) {
+	t := &Transaction{}
+	if err := t.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// UnmarshalBinary sets the values retrieved from byte sequence in an Transaction.
+func (t *Transaction) UnmarshalBinary(b []byte) error {
+	if len(b) < 2 {
+		return io.ErrUnexpectedEOF
+	}
+
+	var err error
+	t.Type = tcap.Tag(b[0])
+	lLength := 0
+	if t.Length, lLength, err = tcap.UnmarshalAsn1ElementLength(b); err != nil {
+		return err
+	}
+
+	offset := 1 + lLength
+	t.TransactionID, err = parseTransactionID(b[offset:])
+	if err != nil {
+		return err
+	}
+	offset += t.TransactionID.MarshalLen()
+
+	if t.Type.Code() == Abort && offset < len(b) {
+		switch b[offset] {
+		case uint8(tcap.NewPrivatePrimitiveTag(23)):
+			t.PAbortCause, err = parseIE(b[offset:])
+			if err != nil {
+				return err
+			}
+			offset += t.PAbortCause.MarshalLen()
+		case uint8(tcap.NewPrivateConstructorTag(24)):
+			t.UserAbortInformation, err = parseIE(b[offset:])
+			if err != nil {
+				return err
+			}
+			offset += t.UserAbortInformation.MarshalLen()
+		}
+	}
+
+	t.Payload = b[offset:]
+	return nil
+}
+
+// parseTransactionID parses the Transaction ID, which can be empty in some Package Types.
+func parseTransactionID(b []byte) (*tcap.IE, error) {
+	if len(b) < 2 {
+		return nil, io.ErrUnexpectedEOF
+	}
+	if tag := tcap.Tag(b[0]); tag != tcap.NewPrivatePrimitiveTag(7) {
+		return nil, fmt.Errorf("ansi: got unexpected tag for Transaction ID: %#x", tag)
+	}
+
+	return parseIE(b)
+}
+
+// MarshalLen returns the serial length of Transaction.
+func (t *Transaction) MarshalLen() int {
+	return 1 + len(tcap.MarshalAsn1ElementLength(t.Length)) + t.valueLen()
+}
+
+// valueLen returns the length of the fields and Payload in Transaction.
+func (t *Transaction) valueLen() int {
+	l := 0
+	if field := t.TransactionID; field != nil {
+		l += field.MarshalLen()
+	}
+	if field := t.PAbortCause; field != nil {
+		l += field.MarshalLen()
+	}
+	if field := t.UserAbortInformation; field != nil {
+		l += field.MarshalLen()
+	}
+	return l + len(t.Payload)
+}
+
+// SetLength sets the length in Length field.
+func (t *Transaction) SetLength() {
+	if field := t.TransactionID; field != nil {
+		field.SetLength()
+	}
+	if field := t.PAbortCause; field != nil {
+		field.SetLength()
+	}
+	if field := t.UserAbortInformation; field != nil {
+		field.SetLength()
+	}
+	t.Length = t.valueLen()
+}
+
+// PackageTypeString returns the name of Package Type in string.
+func (t *Transaction) PackageTypeString() string {
+	switch t.Type.Code() {
+	case Unidirectional:
+		return "Unidirectional"
+	case QueryWithPermission:
+		return "QueryWithPermission"
+	case QueryWithoutPermission:
+		return "QueryWithoutPermission"
+	case Response:
+		return "Response"
+	case ConversationWithPermission:
+		return "ConversationWithPermission"
+	case ConversationWithoutPermission:
+		return "ConversationWithoutPermission"
+	case Abort:
+		return "Abort"
+	}
+	return ""
+}
+
+// OTID returns the Originating Transaction ID in string.
+func (t *Transaction) OTID() string {
+	if otid := t.origTransactionID(); otid != nil {
+		return fmt.Sprintf("%04x", otid)
+	}
+	return ""
+}
+
+// RTID returns the Responding Transaction ID in string.
+func (t *Transaction) RTID() string {
+	if rtid := t.respTransactionID(); rtid != nil {
+		return fmt.Sprintf("%04x", rtid)
+	}
+	return ""
+}
+
+// origTransactionID returns the part of Transaction ID that holds the Originating Transaction ID.
+func (t *Transaction) origTransactionID() []byte {
+	field := t.TransactionID
+	if field == nil {
+		return nil
+	}
+
+	switch t.Type.Code() {
+	case QueryWithPermission, QueryWithoutPermission:
+		return field.Value
+	case ConversationWithPermission, ConversationWithoutPermission:
+		// both IDs are of the same length.
+		return field.Value[:len(field.Value)/2]
+	}
+	return nil
+}
+
+// respTransactionID returns the part of Transaction ID that holds the Responding Transaction ID.
+func (t *Transaction) respTransactionID() []byte {
+	field := t.TransactionID
+	if field == nil {
+		return nil
+	}
+
+	switch t.Type.Code() {
+	case Response, Abort:
+		return field.Value
+	case ConversationWithPermission, ConversationWithoutPermission:
+		return field.Value[len(field.Value)/2:]
+	}
+	return nil
+}
+
+// AbortCause returns the P-Abort Cause in string.
+func (t *Transaction) AbortCause() string {
+	cause := t.PAbortCause
+	if cause == nil || len(cause.Value) == 0 {
+		return ""
+	}
+
+	if t.Type.Code() == Abort {
+		switch cause.Value[0] {
+		case UnrecognizedPackageType:
+			return "UnrecognizedPackageType"
+		case IncorrectTransactionPortion:
+			return "IncorrectTransactionPortion"
+		case BadlyStructuredTransactionPortion:
+			return "BadlyStructuredTransactionPortion"
+		case UnassignedRespondingTransactionID:
+			return "UnassignedRespondingTransactionID"
+		case PermissionToReleaseProblem:
+			return "PermissionToReleaseProblem"
+		case ResourceUnavailable:
+			return "ResourceUnavailable"
+		case UnrecognizedDialoguePortionID:
+			return "UnrecognizedDialoguePortionID"
+		case BadlyStructuredDialoguePortion:
+			return "BadlyStructuredDialoguePortion"
+		case MissingDialoguePortion:
+			return "MissingDialoguePortion"
+		case InconsistentDialoguePortion:
+			return "InconsistentDialoguePortion"
+		}
+	}
+	return ""
+}
+
+// String returns Transaction in human readable string.
+func (t *Transaction) String() string {
+	return fmt.Sprintf("{Type: %#x, Length: %d, TransactionID: %s, PAbortCause: %s, UserAbortInformation: %s, Payload: %x}",
+		t.Type,
+		t.Length,
+		t.TransactionID,
+		t.PAbortCause,
+		t.UserAbortInformation,
+		t.Payload,
+	)
+}
@@ -0,0 +1,62 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ansi
+
+import (
+	"io"
+	"slices"
+
+	"github.com/en-vee/go-tcap"
+)
+
+// parseIE parses given byte sequence as an IE.
+//
+// Unlike tcap.ParseIE, it accepts the zero-length IE at the end of the buffer,
+// which appears frequently in ANSI TCAP (e.g., empty Transaction ID, Component ID
+// and Parameter Set).
+func parseIE(b []byte) (*tcap.IE, error) {
+	if len(b) < 2 {
+		return nil, io.ErrUnexpectedEOF
+	}
+	if b[1] == 0 {
+		return &tcap.IE{Tag: tcap.Tag(b[0]), Value: []byte{}}, nil
+	}
+
+	i, err := tcap.ParseIE(b)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) < i.MarshalLen() {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return i, nil
+}
+
+// marshaler is implemented by the types that put their byte sequence in a byte array of MarshalLen.
+type marshaler interface {
+	MarshalLen() int
+	MarshalTo(b []byte) error
+}
+
+// appendBinary appends the byte sequence generated from m to b.
+func appendBinary(b []byte, m marshaler) ([]byte, error) {
+	l, n := len(b), m.MarshalLen()
+	b = slices.Grow(b, n)[:l+n]
+	clear(b[l:])
+	if err := m.MarshalTo(b[l:]); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// writeTo writes the byte sequence generated from m to w.
+func writeTo(w io.Writer, m marshaler) (int64, error) {
+	b, err := appendBinary(nil, m)
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(b)
+	return int64(n), err
+}
@@ -0,0 +1,175 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import (
+	"encoding"
+	"sync"
+)
+
+// AnyValue is a value of open type (ANY) that keeps the raw encoding until it is decoded.
+//
+// It is used for the values whose type is known only by the upper layer, such as
+// the Parameter of Components and the extension containers.
+type AnyValue struct {
+	raw []byte
+}
+
+// NewAnyValue creates a new AnyValue from the encoding including tag and length.
+func NewAnyValue(b []byte) *AnyValue {
+	return &AnyValue{raw: append([]byte{}, b...)}
+}
+
+// AnyValueOf creates a new AnyValue from the IE.
+func AnyValueOf(i *IE) (*AnyValue, error) {
+	b, err := i.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return &AnyValue{raw: b}, nil
+}
+
+// Bytes returns the raw encoding of AnyValue.
+func (a *AnyValue) Bytes() []byte {
+	return a.raw
+}
+
+// MarshalBinary returns the raw encoding of AnyValue.
+func (a *AnyValue) MarshalBinary() ([]byte, error) {
+	return append([]byte{}, a.raw...), nil
+}
+
+// UnmarshalBinary stores the byte sequence as the raw encoding of AnyValue.
+func (a *AnyValue) UnmarshalBinary(b []byte) error {
+	a.raw = append([]byte{}, b...)
+	return nil
+}
+
+// Tag returns the Tag of the value, or 0 if it is empty or malformed.
+func (a *AnyValue) Tag() Tag {
+	t, _, err := ParseTag(a.raw)
+	if err != nil {
+		return 0
+	}
+	return t
+}
+
+// IE decodes AnyValue as an IE recursively.
+func (a *AnyValue) IE() (*IE, error) {
+	return ParseIERecursive(a.raw)
+}
+
+// Decode decodes AnyValue into v, which is typically a type generated for the schema.
+func (a *AnyValue) Decode(v encoding.BinaryUnmarshaler) error {
+	return v.UnmarshalBinary(a.raw)
+}
+
+// DecodeFunc decodes AnyValue as an IE and passes it to the decoder, e.g., Choice.Decode.
+func (a *AnyValue) DecodeFunc(decoder func(*IE) error) error {
+	i, err := a.IE()
+	if err != nil {
+		return err
+	}
+	return decoder(i)
+}
+
+// AnyParameter returns the Parameter of the Component as an AnyValue, or nil if not present.
+func (c *Component) AnyParameter() (*AnyValue, error) {
+	if c.Parameter == nil {
+		return nil, nil
+	}
+	return AnyValueOf(c.Parameter)
+}
+
+// SetAnyParameter sets the Parameter of the Component from the AnyValue and updates the length.
+func (c *Component) SetAnyParameter(a *AnyValue) error {
+	i, err := a.IE()
+	if err != nil {
+		return err
+	}
+	c.Parameter = i
+	c.SetLength()
+	return nil
+}
+
+// contextCodec is the key of the codecs registered for an OperationCode in an application context.
+type contextCodec struct {
+	context string
+	op      opKey
+}
+
+var (
+	contextCodecMu sync.RWMutex
+	contextCodecs  = map[contextCodec][2]ParameterCodec{}
+)
+
+// RegisterParameterCodec registers the codecs of the Parameters for the OperationCode in the
+// application context, i.e., argument for Invoke and result for ReturnResult, either of which
+// can be nil. It replaces the ones already registered, if any.
+func RegisterParameterCodec(ac ApplicationContext, code OperationCode, argument, result ParameterCodec) {
+	contextCodecMu.Lock()
+	defer contextCodecMu.Unlock()
+
+	contextCodecs[contextCodec{OID(ac).String(), code.key()}] = [2]ParameterCodec{argument, result}
+}
+
+// LookupParameterCodec returns the codec registered for the Parameter of the Component, i.e.,
+// Invoke or ReturnResult, with its OperationCode in the application context, or nil if none.
+func LookupParameterCodec(ac ApplicationContext, c *Component) ParameterCodec {
+	code, err := c.Operation()
+	if err != nil {
+		return nil
+	}
+
+	contextCodecMu.RLock()
+	defer contextCodecMu.RUnlock()
+
+	codecs := contextCodecs[contextCodec{OID(ac).String(), code.key()}]
+	if c.Type.Code() == Invoke {
+		return codecs[0]
+	}
+	return codecs[1]
+}
+
+// LazyParameter is the Parameter of a Component kept as the raw encoding, which is decoded
+// into the value of the application with the codec registered only when Value is called.
+//
+// It is safe for concurrent use.
+type LazyParameter struct {
+	*AnyValue
+
+	codec ParameterCodec
+	once  sync.Once
+	value any
+	err   error
+}
+
+// LazyParameter returns the Parameter of the Invoke or ReturnResult to be decoded with the codec
+// registered for the application context, or nil if the Parameter is not present.
+func (c *Component) LazyParameter(ac ApplicationContext) (*LazyParameter, error) {
+	a, err := c.AnyParameter()
+	if err != nil || a == nil {
+		return nil, err
+	}
+	return &LazyParameter{AnyValue: a, codec: LookupParameterCodec(ac, c)}, nil
+}
+
+// HasCodec reports whether a codec is registered for the Parameter.
+func (p *LazyParameter) HasCodec() bool {
+	return p.codec != nil
+}
+
+// Value decodes the Parameter with the codec at the first call, and returns the same result
+// afterwards. It returns ErrUnknownOperation if no codec is registered, in which case the raw
+// encoding is still available with Bytes.
+func (p *LazyParameter) Value() (any, error) {
+	if p.codec == nil {
+		return nil, ErrUnknownOperation
+	}
+	p.once.Do(func() {
+		p.value, p.err = p.codec(p.AnyValue)
+	})
+	return p.value, p.err
+}
@@ -0,0 +1,91 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestAnyValue(t *testing.T) {
+	comp := tcap.NewInvoke(1, 0, 71, true, []byte{0xa0, 0x03, 0x80, 0x01, 0x05})
+
+	a, err := comp.AnyParameter()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "raw", a.Bytes(), []byte{0x30, 0x05, 0xa0, 0x03, 0x80, 0x01, 0x05})
+	if got, want := a.Tag(), tcap.NewUniversalConstructorTag(0x10); got != want {
+		t.Errorf("Tag: got %#x, want %#x", got, want)
+	}
+
+	var v int64
+	err = a.DecodeFunc(func(i *tcap.IE) error {
+		var err error
+		v, err = i.IE[0].IE[0].Int64()
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != 5 {
+		t.Errorf("decoded: got %d, want 5", v)
+	}
+
+	other := tcap.NewInvoke(2, 0, 71, true, nil)
+	if err := other.SetAnyParameter(a); err != nil {
+		t.Fatal(err)
+	}
+	b1, _ := comp.MarshalBinary()
+	b2, _ := other.MarshalBinary()
+	b1[4] = 2 // invoke ID
+	verify.Values(t, "component", b2, b1)
+}
+
+func TestLazyParameter(t *testing.T) {
+	inv := tcap.NewInvoke(1, -1, int(tcap.MAPOpMOForwardSM), true, []byte{0x04, 0x01, 0xaa})
+	ac := tcap.ShortMsgMORelayV3
+
+	p, err := inv.LazyParameter(ac)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Value(); !errors.Is(err, tcap.ErrUnknownOperation) {
+		t.Errorf("no codec: got %v, want %v", err, tcap.ErrUnknownOperation)
+	}
+	verify.Values(t, "raw", p.Bytes(), []byte{0x30, 0x03, 0x04, 0x01, 0xaa})
+
+	calls := 0
+	tcap.RegisterParameterCodec(ac, tcap.LocalOperationCode(tcap.MAPOpMOForwardSM), func(a *tcap.AnyValue) (any, error) {
+		calls++
+		i, err := a.IE()
+		if err != nil {
+			return nil, err
+		}
+		return i.IE[0].Value, nil
+	}, nil)
+
+	if p, err = inv.LazyParameter(ac); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 0 {
+		t.Error("decoded before Value")
+	}
+	for n := 0; n < 2; n++ {
+		v, err := p.Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+		verify.Values(t, "value", v, []byte{0xaa})
+	}
+	verify.Values(t, "calls", calls, 1)
+
+	if p, _ := inv.LazyParameter(tcap.ShortMsgMORelayV2); p.HasCodec() {
+		t.Error("codec found in other context")
+	}
+}
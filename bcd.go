@@ -0,0 +1,189 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrCheckDigit is returned when the check digit of a BCDString does not match.
+var ErrCheckDigit = errors.New("tcap: check digit mismatch")
+
+// BCDFormat describes how the digits of a BCDString are encoded.
+type BCDFormat struct {
+	// SwappedNibbles puts the first digit in the lower nibble of each octet,
+	// as in IMSI, MSISDN and the most of the digit parameters in MAP and CAP.
+	SwappedNibbles bool
+
+	// Filler is the nibble to fill the last octet when the number of digits is odd.
+	// It should be one of 0xa-0xf, otherwise it cannot be distinguished from a digit on decoding.
+	Filler uint8
+
+	// Extended accepts '*', '#', 'a', 'b' and 'c' as the digits 0xa-0xe, as in TBCD-STRING of MAP.
+	Extended bool
+
+	// CheckDigit appends the Luhn check digit on encoding, and verifies and removes it on decoding.
+	CheckDigit bool
+}
+
+// Predefined BCDFormats.
+var (
+	// PackedBCD puts the first digit in the upper nibble, filling with 0xf.
+	PackedBCD = BCDFormat{Filler: 0xf}
+	// SwappedBCD puts the first digit in the lower nibble, filling with 0xf.
+	SwappedBCD = BCDFormat{SwappedNibbles: true, Filler: 0xf}
+	// TBCD is TBCD-STRING in 3GPP TS 29.002, which is SwappedBCD with the extended digits.
+	TBCD = BCDFormat{SwappedNibbles: true, Filler: 0xf, Extended: true}
+)
+
+// tbcdDigits are the characters of the digits in TBCD-STRING, from 0x0 to 0xe.
+const tbcdDigits = "0123456789*#abc"
+
+// EncodeTBCD encodes the digits as a TBCD-STRING, filling the last octet with 0xf if needed.
+func EncodeTBCD(digits string) ([]byte, error) {
+	return (&BCDString{Digits: digits, Format: TBCD}).MarshalBinary()
+}
+
+// DecodeTBCD decodes the byte sequence as a TBCD-STRING.
+func DecodeTBCD(b []byte) (string, error) {
+	s, err := ParseBCDString(b, TBCD)
+	if err != nil {
+		return "", err
+	}
+	return s.Digits, nil
+}
+
+// BCDString is a string of decimal digits encoded in BCD.
+type BCDString struct {
+	Digits string
+	Format BCDFormat
+}
+
+// NewBCDString creates a new BCDString after validating the digits.
+func NewBCDString(digits string, format BCDFormat) (*BCDString, error) {
+	for _, d := range digits {
+		if _, ok := format.digit(d); !ok {
+			return nil, &InvalidDigitError{Digit: d}
+		}
+	}
+	return &BCDString{Digits: digits, Format: format}, nil
+}
+
+// ParseBCDString decodes the byte sequence as a BCDString in the format.
+func ParseBCDString(b []byte, format BCDFormat) (*BCDString, error) {
+	s := &BCDString{Format: format}
+	if err := s.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// MarshalBinary returns the byte sequence generated from a BCDString.
+func (s *BCDString) MarshalBinary() ([]byte, error) {
+	digits := s.Digits
+	if s.Format.CheckDigit {
+		digits += string(luhn(digits))
+	}
+
+	b := make([]byte, (len(digits)+1)/2)
+	for n, d := range digits {
+		v, ok := s.Format.digit(d)
+		if !ok {
+			return nil, &InvalidDigitError{Digit: d}
+		}
+		s.putNibble(b, n, v)
+	}
+	if len(digits)%2 == 1 {
+		s.putNibble(b, len(digits), s.Format.Filler)
+	}
+	return b, nil
+}
+
+// digit returns the nibble of the digit in the format.
+func (f BCDFormat) digit(d rune) (uint8, bool) {
+	if d >= '0' && d <= '9' {
+		return uint8(d - '0'), true
+	}
+	if f.Extended {
+		if n := strings.IndexRune(tbcdDigits, d); n >= 0 {
+			return uint8(n), true
+		}
+	}
+	return 0, false
+}
+
+func (s *BCDString) putNibble(b []byte, n int, v uint8) {
+	upper := n%2 == 0
+	if s.Format.SwappedNibbles {
+		upper = !upper
+	}
+	if upper {
+		b[n/2] |= v << 4
+	} else {
+		b[n/2] |= v & 0x0f
+	}
+}
+
+// UnmarshalBinary sets the digits decoded from the byte sequence in the Format of BCDString.
+//
+// The Filler is accepted only as the last nibble.
+func (s *BCDString) UnmarshalBinary(b []byte) error {
+	var sb strings.Builder
+	for n := 0; n < len(b)*2; n++ {
+		v := b[n/2] >> 4
+		if (n%2 == 1) != s.Format.SwappedNibbles {
+			v = b[n/2] & 0x0f
+		}
+
+		if v == s.Format.Filler && n == len(b)*2-1 {
+			break
+		}
+		if v > 9 && (!s.Format.Extended || int(v) >= len(tbcdDigits)) {
+			return &InvalidDigitError{Digit: rune("0123456789abcdef"[v])}
+		}
+		sb.WriteByte(tbcdDigits[v])
+	}
+
+	digits := sb.String()
+	if s.Format.CheckDigit {
+		if len(digits) == 0 || luhn(digits[:len(digits)-1]) != digits[len(digits)-1] {
+			return ErrCheckDigit
+		}
+		digits = digits[:len(digits)-1]
+	}
+	s.Digits = digits
+	return nil
+}
+
+// MarshalLen returns the serial length of BCDString.
+func (s *BCDString) MarshalLen() int {
+	l := len(s.Digits)
+	if s.Format.CheckDigit {
+		l++
+	}
+	return (l + 1) / 2
+}
+
+// String returns the digits of BCDString.
+func (s *BCDString) String() string {
+	return s.Digits
+}
+
+// luhn returns the Luhn check digit for the digits.
+func luhn(digits string) byte {
+	sum := 0
+	for n := 0; n < len(digits); n++ {
+		d := int(digits[len(digits)-1-n] - '0')
+		if n%2 == 0 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
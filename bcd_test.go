@@ -0,0 +1,87 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestBCDString(t *testing.T) {
+	cases := []struct {
+		description string
+		digits      string
+		format      tcap.BCDFormat
+		encoded     []byte
+	}{
+		{"IMSI", "001010123456789", tcap.SwappedBCD, []byte{0x00, 0x01, 0x01, 0x21, 0x43, 0x65, 0x87, 0xf9}},
+		{"even", "8190", tcap.SwappedBCD, []byte{0x18, 0x09}},
+		{"packed", "12345", tcap.PackedBCD, []byte{0x12, 0x34, 0x5f}},
+		{"TBCD", "*100#a", tcap.TBCD, []byte{0x1a, 0x00, 0xcb}},
+		{"TBCD odd", "12c", tcap.TBCD, []byte{0x21, 0xfe}},
+		{"check digit", "49015420323751", tcap.BCDFormat{SwappedNibbles: true, Filler: 0xf, CheckDigit: true}, []byte{0x94, 0x10, 0x45, 0x02, 0x23, 0x73, 0x15, 0xf8}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			s, err := tcap.NewBCDString(c.digits, c.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := s.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			verify.Values(t, "encoded", b, c.encoded)
+			if got, want := s.MarshalLen(), len(c.encoded); got != want {
+				t.Errorf("MarshalLen: got %d, want %d", got, want)
+			}
+
+			parsed, err := tcap.ParseBCDString(c.encoded, c.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := parsed.String(); got != c.digits {
+				t.Errorf("decoded: got %s, want %s", got, c.digits)
+			}
+		})
+	}
+
+	var digitErr *tcap.InvalidDigitError
+	if _, err := tcap.NewBCDString("12a", tcap.SwappedBCD); !errors.As(err, &digitErr) {
+		t.Errorf("invalid digit: got %v, want InvalidDigitError", err)
+	}
+	if _, err := tcap.ParseBCDString([]byte{0xf1, 0x32}, tcap.SwappedBCD); !errors.As(err, &digitErr) {
+		t.Errorf("filler in the middle: got %v, want InvalidDigitError", err)
+	}
+	if _, err := tcap.ParseBCDString([]byte{0x94, 0x10, 0x45, 0x02, 0x23, 0x73, 0x15, 0xf9}, tcap.BCDFormat{SwappedNibbles: true, Filler: 0xf, CheckDigit: true}); !errors.Is(err, tcap.ErrCheckDigit) {
+		t.Errorf("check digit: got %v, want %v", err, tcap.ErrCheckDigit)
+	}
+}
+
+func TestTBCD(t *testing.T) {
+	b, err := tcap.EncodeTBCD("*21#")
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "encoded", b, []byte{0x2a, 0xb1})
+
+	digits, err := tcap.DecodeTBCD([]byte{0x19, 0xf2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "decoded", digits, "912")
+
+	var digitErr *tcap.InvalidDigitError
+	if _, err := tcap.EncodeTBCD("12d"); !errors.As(err, &digitErr) {
+		t.Errorf("invalid digit: got %v, want InvalidDigitError", err)
+	}
+	if _, err := tcap.DecodeTBCD([]byte{0xf1, 0x32}); !errors.As(err, &digitErr) {
+		t.Errorf("filler in the middle: got %v, want InvalidDigitError", err)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import "errors"
+
+// ErrInvalidBitString is returned when the value of IE is not a valid BIT STRING.
+var ErrInvalidBitString = errors.New("tcap: invalid BIT STRING")
+
+// BitString is a BIT STRING, e.g., supportedCamelPhases and ODB data in MAP parameters.
+//
+// The bits are numbered from 0, which is the most significant bit of the first octet,
+// in the same way as the named bits in ASN.1.
+type BitString struct {
+	Bytes     []byte
+	BitLength int
+}
+
+// At returns the bit n, or false if it is out of the BitString, as the named bits
+// not encoded are zero.
+func (s BitString) At(n int) bool {
+	if n < 0 || n >= s.BitLength {
+		return false
+	}
+	return s.Bytes[n/8]&(0x80>>(n%8)) != 0
+}
+
+// Set sets the bit n to v, extending the BitString if it is beyond the end.
+func (s *BitString) Set(n int, v bool) {
+	if n < 0 {
+		return
+	}
+	if n >= s.BitLength {
+		if !v {
+			return
+		}
+		for len(s.Bytes) <= n/8 {
+			s.Bytes = append(s.Bytes, 0)
+		}
+		s.BitLength = n + 1
+	}
+
+	if v {
+		s.Bytes[n/8] |= 0x80 >> (n % 8)
+	} else {
+		s.Bytes[n/8] &^= 0x80 >> (n % 8)
+	}
+}
+
+// MarshalBinary returns the contents octets of the BitString in BER, with the unused bits cleared.
+func (s BitString) MarshalBinary() ([]byte, error) {
+	n := (s.BitLength + 7) / 8
+	if s.BitLength < 0 || n > len(s.Bytes) {
+		return nil, ErrInvalidBitString
+	}
+
+	b := make([]byte, n+1)
+	b[0] = uint8(n*8 - s.BitLength)
+	copy(b[1:], s.Bytes[:n])
+	if n > 0 {
+		b[n] &= 0xff << b[0]
+	}
+	return b, nil
+}
+
+// UnmarshalBinary sets the BitString decoded from the contents octets in BER.
+func (s *BitString) UnmarshalBinary(b []byte) error {
+	if len(b) == 0 || b[0] > 7 || (len(b) == 1 && b[0] != 0) {
+		return ErrInvalidBitString
+	}
+
+	s.Bytes = b[1:]
+	s.BitLength = (len(b)-1)*8 - int(b[0])
+	return nil
+}
+
+// NewBitString creates a new IE with the BitString encoded as a BIT STRING.
+func NewBitString(tag Tag, s BitString) (*IE, error) {
+	b, err := s.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return NewIE(tag, b), nil
+}
+
+// BitString returns the value of IE decoded as a BIT STRING.
+//
+// The Bytes of BitString refer to the Value of IE.
+func (i *IE) BitString() (BitString, error) {
+	var s BitString
+	if err := s.UnmarshalBinary(i.Value); err != nil {
+		return BitString{}, err
+	}
+	return s, nil
+}
@@ -0,0 +1,52 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestBitString(t *testing.T) {
+	// supportedCamelPhases with phase1, phase2 and phase3.
+	var s tcap.BitString
+	s.Set(0, true)
+	s.Set(1, true)
+	s.Set(2, true)
+	s.Set(9, false)
+
+	i, err := tcap.NewBitString(tcap.NewUniversalPrimitiveTag(3), s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "encoded", i.Value, []byte{0x05, 0xe0})
+
+	got, err := i.BitString()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "bit length", got.BitLength, 3)
+	for n, want := range []bool{true, true, true, false} {
+		if got.At(n) != want {
+			t.Errorf("bit %d: got %v, want %v", n, got.At(n), want)
+		}
+	}
+
+	// the unused bits are cleared.
+	b, err := tcap.BitString{Bytes: []byte{0xff, 0xff}, BitLength: 10}.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "unused bits", b, []byte{0x06, 0xff, 0xc0})
+
+	for _, v := range [][]byte{{}, {0x08, 0x00}, {0x01}} {
+		if _, err := tcap.NewIE(tcap.NewUniversalPrimitiveTag(3), v).BitString(); !errors.Is(err, tcap.ErrInvalidBitString) {
+			t.Errorf("%x: got %v, want %v", v, err, tcap.ErrInvalidBitString)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+// ComponentList builds an ordered list of Components of any types to be put in a message, e.g.,
+//
+//	comps := tcap.NewComponentList().
+//		ReturnResult(1, 45, result).
+//		Invoke(2, 46, param).
+//		Reject(3, tcap.ProblemUnrecognizedOperation)
+//	m := tcap.NewContinueMessage(tcap.WithOTID(otid), tcap.WithDTID(dtid), comps.Option())
+//
+// The Operation Codes and Error Codes are local. The ones built otherwise can be put with Add.
+type ComponentList struct {
+	comps []*Component
+}
+
+// NewComponentList creates a new empty ComponentList.
+func NewComponentList() *ComponentList {
+	return &ComponentList{}
+}
+
+// Add appends the Components as they are.
+func (l *ComponentList) Add(comps ...*Component) *ComponentList {
+	l.comps = append(l.comps, comps...)
+	return l
+}
+
+// Invoke appends an Invoke.
+func (l *ComponentList) Invoke(invID, opCode int, param []byte) *ComponentList {
+	return l.Add(NewInvoke(invID, -1, opCode, true, param))
+}
+
+// LinkedInvoke appends an Invoke linked to the one of linkedID.
+func (l *ComponentList) LinkedInvoke(invID, linkedID, opCode int, param []byte) *ComponentList {
+	return l.Add(NewLinkedInvoke(invID, linkedID, opCode, true, param))
+}
+
+// ReturnResult appends a ReturnResultLast.
+func (l *ComponentList) ReturnResult(invID, opCode int, param []byte) *ComponentList {
+	return l.Add(NewReturnResult(invID, opCode, true, true, param))
+}
+
+// ReturnResultNotLast appends a ReturnResultNotLast, i.e., a segment of the result.
+func (l *ComponentList) ReturnResultNotLast(invID, opCode int, param []byte) *ComponentList {
+	return l.Add(NewReturnResult(invID, opCode, true, false, param))
+}
+
+// ReturnError appends a ReturnError.
+func (l *ComponentList) ReturnError(invID, errCode int, param []byte) *ComponentList {
+	return l.Add(NewReturnError(invID, errCode, true, param))
+}
+
+// Reject appends a Reject.
+func (l *ComponentList) Reject(invID int, p Problem) *ComponentList {
+	return l.Add(NewRejectWithProblem(invID, p))
+}
+
+// Len returns the number of Components in the list.
+func (l *ComponentList) Len() int {
+	return len(l.comps)
+}
+
+// Components returns the Component Portion of the Components in order, or nil if empty.
+func (l *ComponentList) Components() *Components {
+	if len(l.comps) == 0 {
+		return nil
+	}
+	return NewComponents(append([]*Component{}, l.comps...)...)
+}
+
+// Option returns the MessageOption appending the Components in order to the Component Portion.
+func (l *ComponentList) Option() MessageOption {
+	return WithComponents(append([]*Component{}, l.comps...)...)
+}
+
+// AppendComponents appends the Components to the Component Portion of the TCAP, e.g., the one
+// created by NewBeginDialogue, and updates the lengths.
+func (t *TCAP) AppendComponents(comps ...*Component) {
+	if len(comps) == 0 {
+		return
+	}
+	WithComponents(comps...)(t)
+	t.SetLength()
+}
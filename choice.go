@@ -0,0 +1,75 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+// Choice decodes a CHOICE by dispatching to the decoder registered for the tag of the alternative.
+//
+// For example, the Identity of cancelLocation can be decoded as follows:
+//
+//	identity := tcap.NewChoice[string]().
+//		Register(tcap.NewUniversalPrimitiveTag(4), decodeIMSI).
+//		Register(tcap.NewUniversalConstructorTag(16), decodeIMSIWithLMSI)
+//	v, err := identity.Decode(ie)
+type Choice[T any] struct {
+	alternatives []alternative[T]
+}
+
+// alternative is an alternative of Choice and its decoder.
+type alternative[T any] struct {
+	tag     Tag
+	decoder func(*IE) (T, error)
+}
+
+// NewChoice creates a new Choice with no alternatives.
+func NewChoice[T any]() *Choice[T] {
+	return &Choice[T]{}
+}
+
+// Register registers the decoder for the alternative with the tag.
+//
+// The decoder replaces the one already registered for the tag, if any.
+// It returns the Choice itself to make it easy to chain.
+func (c *Choice[T]) Register(tag Tag, decoder func(*IE) (T, error)) *Choice[T] {
+	for n := range c.alternatives {
+		if c.alternatives[n].tag == tag {
+			c.alternatives[n].decoder = decoder
+			return c
+		}
+	}
+	c.alternatives = append(c.alternatives, alternative[T]{tag: tag, decoder: decoder})
+	return c
+}
+
+// Tags returns the tags of the alternatives registered, in the order of registration.
+func (c *Choice[T]) Tags() []Tag {
+	tags := make([]Tag, 0, len(c.alternatives))
+	for _, a := range c.alternatives {
+		tags = append(tags, a.tag)
+	}
+	return tags
+}
+
+// Decode decodes the IE with the decoder registered for its tag.
+//
+// InvalidTagError is returned if no alternative is registered for the tag.
+func (c *Choice[T]) Decode(i *IE) (T, error) {
+	for _, a := range c.alternatives {
+		if a.tag == i.Tag {
+			return a.decoder(i)
+		}
+	}
+	var zero T
+	return zero, &InvalidTagError{Tag: i.Tag}
+}
+
+// DecodeBytes parses the byte sequence as an IE and decodes it.
+func (c *Choice[T]) DecodeBytes(b []byte) (T, error) {
+	i, err := ParseIE(b)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return c.Decode(i)
+}
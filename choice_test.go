@@ -0,0 +1,57 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestChoice(t *testing.T) {
+	ch := tcap.NewChoice[string]().
+		Register(tcap.NewContextSpecificPrimitiveTag(0), func(i *tcap.IE) (string, error) {
+			v, err := i.Int64()
+			return fmt.Sprintf("local:%d", v), err
+		}).
+		Register(tcap.NewUniversalPrimitiveTag(6), func(i *tcap.IE) (string, error) {
+			return fmt.Sprintf("global:%x", i.Value), nil
+		})
+
+	cases := []struct {
+		b    []byte
+		want string
+	}{
+		{[]byte{0x80, 0x01, 0x03}, "local:3"},
+		{[]byte{0x06, 0x02, 0x2b, 0x06}, "global:2b06"},
+	}
+	for _, c := range cases {
+		got, err := ch.DecodeBytes(c.b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != c.want {
+			t.Errorf("got %s, want %s", got, c.want)
+		}
+	}
+
+	var tagErr *tcap.InvalidTagError
+	if _, err := ch.DecodeBytes([]byte{0x81, 0x01, 0x00}); !errors.As(err, &tagErr) {
+		t.Errorf("unknown alternative: got %v, want InvalidTagError", err)
+	}
+	verify.Values(t, "tags", ch.Tags(), []tcap.Tag{tcap.NewContextSpecificPrimitiveTag(0), tcap.NewUniversalPrimitiveTag(6)})
+
+	// Registering the tag again replaces the decoder in its place.
+	ch.Register(tcap.NewContextSpecificPrimitiveTag(0), func(i *tcap.IE) (string, error) {
+		return "replaced", nil
+	})
+	if got, err := ch.DecodeBytes([]byte{0x80, 0x01, 0x03}); err != nil || got != "replaced" {
+		t.Errorf("replaced: got %s, %v", got, err)
+	}
+	verify.Values(t, "tags after replacement", ch.Tags(), []tcap.Tag{tcap.NewContextSpecificPrimitiveTag(0), tcap.NewUniversalPrimitiveTag(6)})
+}
@@ -0,0 +1,140 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import "bytes"
+
+// Clone returns a deep copy of the IE, including the Value bytes and the children.
+//
+// The copy does not share any memory with the original, and has no parent.
+// The children deferred by Lazy of ParseOptions are parsed first.
+func (i *IE) Clone() *IE {
+	if i == nil {
+		return nil
+	}
+	_ = i.DecodeChildren()
+
+	c := &IE{
+		Tag:    i.Tag,
+		Length: i.Length,
+		Value:  bytes.Clone(i.Value),
+		raw:    bytes.Clone(i.raw),
+		offset: i.offset,
+	}
+	if i.IE != nil {
+		c.IE = make([]*IE, len(i.IE))
+		for n, child := range i.IE {
+			c.IE[n] = child.Clone()
+			if child.parent == i {
+				c.IE[n].parent = c
+			}
+		}
+	}
+	return c
+}
+
+// Clone returns a deep copy of the TCAP.
+func (t *TCAP) Clone() *TCAP {
+	if t == nil {
+		return nil
+	}
+
+	return &TCAP{
+		Transaction: t.Transaction.Clone(),
+		Dialogue:    t.Dialogue.Clone(),
+		Components:  t.Components.Clone(),
+	}
+}
+
+// Clone returns a deep copy of the Transaction.
+func (t *Transaction) Clone() *Transaction {
+	if t == nil {
+		return nil
+	}
+
+	return &Transaction{
+		Type:              t.Type,
+		Length:            t.Length,
+		OrigTransactionID: t.OrigTransactionID.Clone(),
+		DestTransactionID: t.DestTransactionID.Clone(),
+		PAbortCause:       t.PAbortCause.Clone(),
+		Payload:           bytes.Clone(t.Payload),
+	}
+}
+
+// Clone returns a deep copy of the Dialogue.
+func (d *Dialogue) Clone() *Dialogue {
+	if d == nil {
+		return nil
+	}
+
+	return &Dialogue{
+		Tag:              d.Tag,
+		Length:           d.Length,
+		ExternalTag:      d.ExternalTag,
+		ExternalLength:   d.ExternalLength,
+		ObjectIdentifier: d.ObjectIdentifier.Clone(),
+		SingleAsn1Type:   d.SingleAsn1Type.Clone(),
+		DialoguePDU:      d.DialoguePDU.Clone(),
+		Payload:          bytes.Clone(d.Payload),
+	}
+}
+
+// Clone returns a deep copy of the DialoguePDU.
+func (d *DialoguePDU) Clone() *DialoguePDU {
+	if d == nil {
+		return nil
+	}
+
+	return &DialoguePDU{
+		Type:                   d.Type,
+		Length:                 d.Length,
+		ProtocolVersion:        d.ProtocolVersion.Clone(),
+		ApplicationContextName: d.ApplicationContextName.Clone(),
+		Result:                 d.Result.Clone(),
+		ResultSourceDiagnostic: d.ResultSourceDiagnostic.Clone(),
+		AbortSource:            d.AbortSource.Clone(),
+		UserInformation:        d.UserInformation.Clone(),
+	}
+}
+
+// Clone returns a deep copy of the Components.
+func (c *Components) Clone() *Components {
+	if c == nil {
+		return nil
+	}
+
+	cs := &Components{
+		Tag:    c.Tag,
+		Length: c.Length,
+	}
+	if c.Component != nil {
+		cs.Component = make([]*Component, len(c.Component))
+		for n, comp := range c.Component {
+			cs.Component[n] = comp.Clone()
+		}
+	}
+	return cs
+}
+
+// Clone returns a deep copy of the Component.
+func (c *Component) Clone() *Component {
+	if c == nil {
+		return nil
+	}
+
+	return &Component{
+		Type:          c.Type,
+		Length:        c.Length,
+		InvokeID:      c.InvokeID.Clone(),
+		LinkedID:      c.LinkedID.Clone(),
+		ResultRetres:  c.ResultRetres.Clone(),
+		SequenceTag:   c.SequenceTag.Clone(),
+		OperationCode: c.OperationCode.Clone(),
+		ErrorCode:     c.ErrorCode.Clone(),
+		ProblemCode:   c.ProblemCode.Clone(),
+		Parameter:     c.Parameter.Clone(),
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestClone(t *testing.T) {
+	m := tcap.NewBeginInvoke(0x11111111, 1, 45, []byte{0x80, 0x01, 0xaa})
+	m.Dialogue = tcap.NewDialogue(1, 1, tcap.NewAARQ(1, tcap.NetworkUnstructuredSsContext, 2), []byte{})
+	m.SetLength()
+	b, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := tcap.Parse(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := parsed.Clone()
+	verify.Values(t, "Clone", c, parsed)
+	want, err := parsed.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// neither the input buffer nor the original affects the copy.
+	for n := range b {
+		b[n] = 0
+	}
+	parsed.Components.Component[0].Parameter.IE[0].Value[0] = 0xbb
+
+	got, err := c.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "MarshalBinary", got, want)
+}
@@ -5,6 +5,7 @@
 package tcap_test
 
 import (
+	"bytes"
 	"encoding"
 	"testing"
 
@@ -17,11 +18,17 @@ type serializable interface {
 	MarshalLen() int
 }
 
+// longPayload is the Parameter long enough to make the lengths of its parents in long form.
+var longPayload = bytes.Repeat([]byte{0x04, 0x02, 0x00, 0x00}, 50)
+
 var testcases = []struct {
 	description string
 	structured  serializable
 	serialized  []byte
 	parseFunc   func(b []byte) (serializable, error)
+
+	// parseOnly is set if the serialized is not the one marshaled, e.g., in indefinite-length form.
+	parseOnly bool
 }{
 	// TCAP (All)
 	// TODO: Add more patterns
@@ -228,6 +235,44 @@ var testcases = []struct {
 			return v, nil
 		},
 	},
+	{
+		description: "TCAP/End - AARE - ReturnResultLast / long-form length",
+		structured: tcap.NewEndReturnResultWithDialogue(
+			0x11111111,                     // OTID
+			tcap.DialogueAsID,              // DialogueType
+			tcap.AnyTimeInfoEnquiryContext, // ACN
+			3,                              // ACN Version
+			1,                              // Invoke Id
+			71,                             // OpCode
+			true,                           // Last or not
+			longPayload,                    // Payload
+		),
+		serialized: append([]byte{
+			// Transaction Portion
+			0x64, 0x82, 0x01, 0x0c, 0x49, 0x04, 0x11, 0x11, 0x11, 0x11,
+			// Dialogue Portion
+			0x6b, 0x2a, 0x28, 0x28, 0x06, 0x07, 0x00, 0x11, 0x86, 0x05, 0x01, 0x01, 0x01, 0xa0, 0x1d, 0x61,
+			0x1b, 0x80, 0x02, 0x07, 0x80, 0xa1, 0x09, 0x06, 0x07, 0x04, 0x00, 0x00, 0x01, 0x00, 0x1d, 0x03,
+			0xa2, 0x03, 0x02, 0x01, 0x00, 0xa3, 0x05, 0xa1,
+			0x03, 0x02, 0x01, 0x00,
+			// Component Portion
+			0x6c, 0x81, 0xd7, 0xa2, 0x81, 0xd4, 0x02, 0x01, 0x01, 0x30, 0x81, 0xce, 0x02, 0x01, 0x47, 0x30,
+			0x81, 0xc8,
+		}, longPayload...),
+		parseFunc: func(b []byte) (serializable, error) {
+			v, err := tcap.Parse(b)
+			if err != nil {
+				return nil, err
+			}
+			// clear unnecessary payload
+			v.Transaction.Payload = nil
+			v.Dialogue.SingleAsn1Type.Value = nil
+			v.Dialogue.Payload = nil
+			v.Components.Component[0].ResultRetres.Value = nil
+
+			return v, nil
+		},
+	},
 	// Transaction Portion
 	{
 		description: "Transaction/Unidirectional",
@@ -405,6 +450,30 @@ var testcases = []struct {
 			return v, nil
 		},
 	},
+	{
+		description: "TCAP/Begin - Invoke / indefinite-length",
+		structured:  tcap.NewBeginInvoke(0x11111111, 1, 45, []byte{0x04, 0x01, 0xaa}),
+		serialized: []byte{
+			// Transaction Portion
+			0x62, 0x80, 0x48, 0x04, 0x11, 0x11, 0x11, 0x11,
+			// Component Portion
+			0x6c, 0x80, 0xa1, 0x80, 0x02, 0x01, 0x01, 0x02, 0x01, 0x2d, 0x30, 0x80, 0x04, 0x01, 0xaa, 0x00,
+			0x00, 0x00, 0x00, 0x00, 0x00,
+			// end-of-contents of Transaction Portion
+			0x00, 0x00,
+		},
+		parseFunc: func(b []byte) (serializable, error) {
+			v, err := tcap.Parse(b)
+			if err != nil {
+				return nil, err
+			}
+			// clear unnecessary payload
+			v.Transaction.Payload = nil
+
+			return v, nil
+		},
+		parseOnly: true,
+	},
 	// Generic IE
 	{
 		description: "IE/Single",
@@ -429,6 +498,10 @@ func TestCodec(t *testing.T) {
 			}
 		})
 
+		if c.parseOnly {
+			continue
+		}
+
 		t.Run("Marshal / "+c.description, func(t *testing.T) {
 			b, err := c.structured.MarshalBinary()
 			if err != nil {
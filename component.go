@@ -127,6 +127,16 @@ func NewInvoke(invID, lkID, opCode int, isLocal bool, param []byte) *Component {
 	return c
 }
 
+// NewLinkedInvoke returns a new single Invoke Component linked to the Invoke of linkedID,
+// i.e., a linked operation invoked in response to the one by the peer.
+//
+// Unlike NewInvoke, the linkedID is always set, including zero and negative ones.
+func NewLinkedInvoke(invID, linkedID, opCode int, isLocal bool, param []byte) *Component {
+	c := NewInvoke(invID, -1, opCode, isLocal, param)
+	c.SetLinkedID(linkedID)
+	return c
+}
+
 // NewReturnResult returns a new single ReturnResultLast or ReturnResultNotLast Component.
 func NewReturnResult(invID, opCode int, isLocal, isLast bool, param []byte) *Component {
 	tag := ReturnResultNotLast
@@ -182,7 +192,7 @@ func NewReturnError(invID, errCode int, isLocal bool, param []byte) *Component {
 // NewReject returns a new single Reject Component.
 func NewReject(invID, problemType int, problemCode uint8, param []byte) *Component {
 	c := &Component{
-		Type: NewContextSpecificConstructorTag(Invoke),
+		Type: NewContextSpecificConstructorTag(Reject),
 		InvokeID: &IE{
 			Tag:    NewUniversalPrimitiveTag(2),
 			Length: 1,
@@ -232,6 +242,16 @@ func (c *Components) MarshalBinary() ([]byte, error) {
 	return b, nil
 }
 
+// AppendBinary appends the byte sequence generated from a Components instance to b.
+func (c *Components) AppendBinary(b []byte) ([]byte, error) {
+	return appendBinary(b, c)
+}
+
+// WriteTo writes the byte sequence generated from a Components instance to w.
+func (c *Components) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(w, c)
+}
+
 // MarshalTo puts the byte sequence in the byte array given as b.
 func (c *Components) MarshalTo(b []byte) error {
 	// 1. Calculate dynamic length bytes using your util
@@ -274,6 +294,16 @@ func (c *Component) MarshalBinary() ([]byte, error) {
 	return b, nil
 }
 
+// AppendBinary appends the byte sequence generated from a Component instance to b.
+func (c *Component) AppendBinary(b []byte) ([]byte, error) {
+	return appendBinary(b, c)
+}
+
+// WriteTo writes the byte sequence generated from a Component instance to w.
+func (c *Component) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(w, c)
+}
+
 // MarshalTo puts the byte sequence in the byte array given as b.
 func (c *Component) MarshalTo(b []byte) error {
 	// 1. Calculate dynamic length bytes
@@ -321,11 +351,19 @@ func (c *Component) MarshalTo(b []byte) error {
 			}
 		}
 	case ReturnResultLast, ReturnResultNotLast:
+		// ResultRetres is a SEQUENCE wrapping OperationCode and Parameter,
+		// so only its Tag and Length are written here.
 		if field := c.ResultRetres; field != nil {
-			if err := field.MarshalTo(b[offset : offset+field.MarshalLen()]); err != nil {
+			n, err := field.Tag.MarshalTo(b[offset:])
+			if err != nil {
 				return err
 			}
-			offset += field.MarshalLen()
+			lenBytes := MarshalAsn1ElementLength(field.Length)
+			if len(b) < offset+n+len(lenBytes) {
+				return io.ErrShortBuffer
+			}
+			copy(b[offset+n:], lenBytes)
+			offset += n + len(lenBytes)
 		}
 
 		if field := c.OperationCode; field != nil {
@@ -403,7 +441,7 @@ func (c *Components) UnmarshalBinary(b []byte) error {
 		// ParseComponent internally calls UnmarshalBinary for a single Component.
 		comp, err := ParseComponent(data)
 		if err != nil {
-			return err
+			return parseErrorAt(err, b, offsetOf(b, data), c.Tag)
 		}
 		c.Component = append(c.Component, comp)
 
@@ -458,16 +496,25 @@ func (c *Component) UnmarshalBinary(b []byte) error {
 	// 4. Parse Invoke ID (Common to almost all components)
 	c.InvokeID, err = ParseIE(b[offset:])
 	if err != nil {
-		return err
+		return parseErrorAt(err, b, offset, c.Type)
 	}
 	offset += c.InvokeID.MarshalLen()
 
 	switch c.Type.Code() {
 	case Invoke:
+		// Parse Linked ID if present
+		if offset < len(b) && b[offset] == uint8(NewContextSpecificPrimitiveTag(0)) {
+			c.LinkedID, err = ParseIE(b[offset:])
+			if err != nil {
+				return parseErrorAt(err, b, offset, c.Type)
+			}
+			offset += c.LinkedID.MarshalLen()
+		}
+
 		// Parse Operation Code
 		c.OperationCode, err = ParseIE(b[offset:])
 		if err != nil {
-			return err
+			return parseErrorAt(err, b, offset, c.Type)
 		}
 		offset += c.OperationCode.MarshalLen()
 
@@ -476,14 +523,14 @@ func (c *Component) UnmarshalBinary(b []byte) error {
 		if offset < len(b) && offset < headerLen+valLen {
 			c.Parameter, err = ParseIERecursive(b[offset:])
 			if err != nil {
-				return err
+				return parseErrorAt(err, b, offset, c.Type)
 			}
 		}
 
 	case ReturnResultLast, ReturnResultNotLast:
 		c.ResultRetres, err = ParseIE(b[offset:])
 		if err != nil {
-			return err
+			return parseErrorAt(err, b, offset, c.Type)
 		}
 
 		// RESULT data is inside the Value of the IE we just parsed
@@ -493,7 +540,8 @@ func (c *Component) UnmarshalBinary(b []byte) error {
 		if innerOffset < len(innerBytes) {
 			c.OperationCode, err = ParseIE(innerBytes[innerOffset:])
 			if err != nil {
-				return err
+				err = parseErrorAt(err, innerBytes, innerOffset, c.ResultRetres.Tag)
+				return parseErrorAt(err, b, offsetOf(b, innerBytes), c.Type)
 			}
 			innerOffset += c.OperationCode.MarshalLen()
 		}
@@ -501,28 +549,29 @@ func (c *Component) UnmarshalBinary(b []byte) error {
 		if innerOffset < len(innerBytes) {
 			c.Parameter, err = ParseIERecursive(innerBytes[innerOffset:])
 			if err != nil {
-				return err
+				err = parseErrorAt(err, innerBytes, innerOffset, c.ResultRetres.Tag)
+				return parseErrorAt(err, b, offsetOf(b, innerBytes), c.Type)
 			}
 		}
 
 	case ReturnError:
 		c.ErrorCode, err = ParseIE(b[offset:])
 		if err != nil {
-			return err
+			return parseErrorAt(err, b, offset, c.Type)
 		}
 		offset += c.ErrorCode.MarshalLen()
 
 		if offset < len(b) && offset < headerLen+valLen {
 			c.Parameter, err = ParseIERecursive(b[offset:])
 			if err != nil {
-				return err
+				return parseErrorAt(err, b, offset, c.Type)
 			}
 		}
 
 	case Reject:
 		c.ProblemCode, err = ParseIE(b[offset:])
 		if err != nil {
-			return err
+			return parseErrorAt(err, b, offset, c.Type)
 		}
 	}
 	return nil
@@ -576,6 +625,8 @@ func (c *Components) SetValsFrom(berParsed *IE) error {
 					} else {
 						comp.OperationCode = iex
 					}
+				case 0x80:
+					comp.LinkedID = iex
 				case 0x30:
 					comp.Parameter = iex
 				}
@@ -622,16 +673,25 @@ func (c *Components) SetValsFrom(berParsed *IE) error {
 
 // MarshalLen returns the serial length of Components.
 func (c *Components) MarshalLen() int {
-	var l = 2
+	var l = 0
 	for _, comp := range c.Component {
 		l += comp.MarshalLen()
 	}
-	return l
+	return headerLen(c.Tag, l) + l
 }
 
 // MarshalLen returns the serial length of Component.
 func (c *Component) MarshalLen() int {
-	var l = 2 + c.InvokeID.MarshalLen()
+	l := c.valueLen()
+	return headerLen(c.Type, l) + l
+}
+
+// valueLen returns the length of the fields in Component.
+func (c *Component) valueLen() int {
+	var l = 0
+	if field := c.InvokeID; field != nil {
+		l += field.MarshalLen()
+	}
 	switch c.Type.Code() {
 	case Invoke:
 		if field := c.LinkedID; field != nil {
@@ -645,7 +705,7 @@ func (c *Component) MarshalLen() int {
 		}
 	case ReturnResultLast, ReturnResultNotLast:
 		if field := c.ResultRetres; field != nil {
-			l += field.MarshalLen()
+			l += headerLen(field.Tag, field.Length)
 		}
 		if field := c.OperationCode; field != nil {
 			l += field.MarshalLen()
@@ -709,7 +769,7 @@ func (c *Component) SetLength() {
 	if field := c.ResultRetres; field != nil {
 		field.Length = (l)
 	}
-	c.Length = (c.MarshalLen() - 2)
+	c.Length = c.valueLen()
 }
 
 // ComponentTypeString returns the Component Type in string.
@@ -737,6 +797,61 @@ func (c *Component) InvID() uint8 {
 	return 0
 }
 
+// SetLinkedID sets the Linked ID of the Invoke in the range of MinInvokeID to MaxInvokeID,
+// encoded in two's complement, e.g., 0xfb for -5.
+func (c *Component) SetLinkedID(id int) {
+	c.LinkedID = NewIE(NewContextSpecificPrimitiveTag(0), []byte{uint8(id)})
+	c.SetLength()
+}
+
+// RemoveLinkedID removes the Linked ID of the Invoke, if any.
+func (c *Component) RemoveLinkedID() {
+	c.LinkedID = nil
+	c.SetLength()
+}
+
+// LinkedInvokeID returns the Linked ID of the Invoke as a signed integer in the same way as
+// the Invoke ID, and whether it is present.
+func (c *Component) LinkedInvokeID() (int, bool) {
+	if c.Type.Code() != Invoke || c.LinkedID == nil || len(c.LinkedID.Value) == 0 {
+		return 0, false
+	}
+	v, err := c.LinkedID.Int64()
+	if err != nil {
+		return 0, false
+	}
+	return int(v), true
+}
+
+// Linked returns the Invokes linked to the Invoke of the invID, in order.
+func (c *Components) Linked(invID int) []*Component {
+	var linked []*Component
+	for _, comp := range c.Component {
+		if id, ok := comp.LinkedInvokeID(); ok && id == invID {
+			linked = append(linked, comp)
+		}
+	}
+	return linked
+}
+
+// LinkedParent returns the Invoke in the Components that the Component is linked to,
+// or nil if it is not linked or the parent is not in the Components, e.g., in the previous message.
+func (c *Components) LinkedParent(comp *Component) *Component {
+	id, ok := comp.LinkedInvokeID()
+	if !ok {
+		return nil
+	}
+	for _, p := range c.Component {
+		if p == comp || p.Type.Code() != Invoke {
+			continue
+		}
+		if v, err := p.invokeID(); err == nil && v == id {
+			return p
+		}
+	}
+	return nil
+}
+
 // OpCode returns the OpCode in string.
 func (c *Component) OpCode() uint8 {
 	if c.Type.Code() == ReturnError {
@@ -749,7 +864,7 @@ func (c *Component) OpCode() uint8 {
 
 // String returns Components in human readable string.
 func (c *Components) String() string {
-	return fmt.Sprintf("{Tag: %#x, Length: %d, Component: %v}",
+	return fmt.Sprintf("{Tag: %#x, Length: %d, Component: %s}",
 		c.Tag,
 		c.Length,
 		c.Component,
@@ -758,7 +873,7 @@ func (c *Components) String() string {
 
 // String returns Component in human readable string.
 func (c *Component) String() string {
-	return fmt.Sprintf("{Type: %#x, Length: %d, ResultRetres: %v, InvokeID: %v, LinkedID: %v, OperationCode: %v, ErrorCode: %v, ProblemCode: %v, Parameter: %v}",
+	return fmt.Sprintf("{Type: %#x, Length: %d, ResultRetres: %s, InvokeID: %s, LinkedID: %s, OperationCode: %s, ErrorCode: %s, ProblemCode: %s, Parameter: %s}",
 		c.Type,
 		c.Length,
 		c.ResultRetres,
@@ -0,0 +1,313 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestLinkedInvoke(t *testing.T) {
+	m := tcap.NewContinueMessage(
+		tcap.WithOTID(0x22222222), tcap.WithDTID(0x11111111),
+		tcap.WithComponents(
+			tcap.NewInvoke(0, -1, 35, true, []byte{0x04, 0x01, 0xaa}),
+			tcap.NewLinkedInvoke(1, 0, 36, true, []byte{0x04, 0x01, 0xbb}),
+			tcap.NewLinkedInvoke(2, 0, 37, true, nil),
+		),
+	)
+	b, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := tcap.Parse(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	comps := parsed.Components
+
+	if _, ok := comps.Component[0].LinkedInvokeID(); ok {
+		t.Error("Invoke 0: got linked ID")
+	}
+	id, ok := comps.Component[1].LinkedInvokeID()
+	if !ok {
+		t.Fatal("Invoke 1: no linked ID")
+	}
+	verify.Values(t, "linked ID", id, 0)
+	verify.Values(t, "opcodes", parsed.OpCode(), []uint8{35, 36, 37})
+	verify.Values(t, "linked", len(comps.Linked(0)), 2)
+	if p := comps.LinkedParent(comps.Component[2]); p != comps.Component[0] {
+		t.Errorf("parent: got %v", p)
+	}
+
+	comps.Component[2].RemoveLinkedID()
+	if _, ok := comps.Component[2].LinkedInvokeID(); ok {
+		t.Error("removed: got linked ID")
+	}
+}
+
+func TestLinkedInvokeNegative(t *testing.T) {
+	for _, lk := range []int{tcap.MinInvokeID, -5, -1, tcap.MaxInvokeID} {
+		m := tcap.NewContinueMessage(
+			tcap.WithOTID(0x22222222), tcap.WithDTID(0x11111111),
+			tcap.WithComponents(
+				tcap.NewInvoke(lk, -1, 35, true, nil),
+				tcap.NewLinkedInvoke(1, lk, 36, true, nil),
+			),
+		)
+		b, err := m.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed, err := tcap.Parse(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		comps := parsed.Components
+
+		id, ok := comps.Component[1].LinkedInvokeID()
+		if !ok {
+			t.Fatalf("%d: no linked ID", lk)
+		}
+		verify.Values(t, "linked ID", id, lk)
+		verify.Values(t, "encoded", comps.Component[1].LinkedID.Value, []byte{uint8(lk)})
+		verify.Values(t, "linked", len(comps.Linked(lk)), 1)
+		if p := comps.LinkedParent(comps.Component[1]); p != comps.Component[0] {
+			t.Errorf("%d: parent: got %v", lk, p)
+		}
+	}
+}
+
+func TestResultReassembler(t *testing.T) {
+	m := tcap.NewContinueMessage(
+		tcap.WithOTID(0x22222222), tcap.WithDTID(0x11111111),
+		tcap.WithComponents(
+			tcap.NewReturnResult(1, 56, true, false, []byte{0x04, 0x02, 0x01, 0x02}),
+			tcap.NewReturnResult(2, 45, true, true, []byte{0x04, 0x01, 0xff}),
+			tcap.NewReturnResult(1, 56, true, false, []byte{0x04, 0x01, 0x03}),
+			tcap.NewReturnResult(1, 56, true, true, []byte{0x04, 0x02, 0x04, 0x05}),
+		),
+	)
+	b, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := tcap.Parse(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := tcap.NewResultReassembler()
+	var results []*tcap.Component
+	for _, c := range parsed.Components.Component {
+		res, done, err := r.Add(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if done {
+			results = append(results, res)
+		}
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	verify.Values(t, "pending", r.Pending(), 0)
+	verify.Values(t, "unsegmented", results[0], parsed.Components.Component[1])
+
+	got, err := results[1].MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the contents of the parameters (SEQUENCE) are concatenated.
+	want, err := tcap.NewReturnResult(1, 56, true, true, []byte{0x04, 0x02, 0x01, 0x02, 0x04, 0x01, 0x03, 0x04, 0x02, 0x04, 0x05}).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "reassembled", got, want)
+
+	if _, _, err := r.Add(tcap.NewInvoke(0, -1, 56, true, nil)); !errors.Is(err, tcap.ErrNotReturnResult) {
+		t.Errorf("Invoke: got %v, want %v", err, tcap.ErrNotReturnResult)
+	}
+	if _, done, _ := r.Add(parsed.Components.Component[0]); done || r.Pending() != 1 {
+		t.Errorf("segment: got done=%v, pending=%d", done, r.Pending())
+	}
+	r.Discard(1)
+	verify.Values(t, "discarded", r.Pending(), 0)
+}
+
+func TestRejectProblem(t *testing.T) {
+	m := tcap.NewEndMessage(
+		tcap.WithDTID(0x11111111),
+		tcap.WithComponents(
+			tcap.NewRejectWithProblem(3, tcap.ProblemDuplicateInvokeID),
+			tcap.NewRejectNotDerivable(tcap.ProblemBadlyStructuredComponent),
+		),
+	)
+	b, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := tcap.Parse(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	comps := parsed.Components.Component
+	if len(comps) != 2 {
+		t.Fatalf("got %d components, want 2", len(comps))
+	}
+	p, err := comps[0].Problem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "problem", p, tcap.ProblemDuplicateInvokeID)
+	verify.Values(t, "string", p.String(), "invoke: duplicateInvokeID")
+	verify.Values(t, "derivable", comps[0].IsInvokeIDDerivable(), true)
+
+	if p, err = comps[1].Problem(); err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "not derivable problem", p, tcap.ProblemBadlyStructuredComponent)
+	verify.Values(t, "not derivable", comps[1].IsInvokeIDDerivable(), false)
+
+	if _, err := tcap.NewInvoke(0, -1, 56, true, nil).Problem(); !errors.Is(err, tcap.ErrInvalidProblem) {
+		t.Errorf("Invoke: got %v, want %v", err, tcap.ErrInvalidProblem)
+	}
+}
+
+func TestReturnErrorCode(t *testing.T) {
+	local, err := tcap.NewReturnErrorWithCode(1, tcap.LocalErrorCode(tcap.MAPAbsentSubscriberSM), []byte{0x30, 0x03, 0x80, 0x01, 0x01})
+	if err != nil {
+		t.Fatal(err)
+	}
+	global, err := tcap.NewReturnErrorWithCode(2, tcap.GlobalErrorCode(tcap.OID{1, 3, 6, 1, 4, 1, 99999, 1}), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := tcap.NewEndMessage(tcap.WithDTID(0x11111111), tcap.WithComponents(local, global))
+	b, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := tcap.Parse(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code, err := parsed.Components.Component[0].ErrCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "local", code, tcap.LocalErrorCode(tcap.MAPAbsentSubscriberSM))
+	verify.Values(t, "MAP name", code.Name("map"), "absentSubscriberSM")
+	info, ok := tcap.LookupError("map", code.Local)
+	if !ok {
+		t.Fatal("absentSubscriberSM not registered")
+	}
+	verify.Values(t, "parameter", info.Parameter, "AbsentSubscriberSM-Param")
+
+	if code, err = parsed.Components.Component[1].ErrCode(); err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "global", code.String(), "1.3.6.1.4.1.99999.1")
+
+	tcap.RegisterErrors("vendor", map[int64]tcap.ErrorInfo{100: {Name: "quotaExceeded"}})
+	verify.Values(t, "registered", tcap.LocalErrorCode(100).Name("vendor"), "quotaExceeded")
+	verify.Values(t, "unregistered", tcap.LocalErrorCode(100).Name("map"), "100")
+
+	if _, err := tcap.NewInvoke(0, -1, 56, true, nil).ErrCode(); !errors.Is(err, tcap.ErrInvalidErrorCode) {
+		t.Errorf("Invoke: got %v, want %v", err, tcap.ErrInvalidErrorCode)
+	}
+}
+
+func TestComponentList(t *testing.T) {
+	comps := tcap.NewComponentList().
+		ReturnResult(1, 45, []byte{0x04, 0x01, 0xaa}).
+		Invoke(2, 46, []byte{0x04, 0x01, 0xbb}).
+		ReturnError(3, 34, nil).
+		Reject(4, tcap.ProblemUnrecognizedOperation)
+	verify.Values(t, "Len", comps.Len(), 4)
+
+	m := tcap.NewContinueMessage(tcap.WithOTID(0x22222222), tcap.WithDTID(0x11111111), comps.Option())
+	m.AppendComponents(tcap.NewLinkedInvoke(5, 2, 47, true, nil))
+	b, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := tcap.Parse(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var types []string
+	for n, c := range parsed.Components.Indexed() {
+		verify.Values(t, "invoke ID", c.InvID(), uint8(n+1))
+		types = append(types, c.ComponentTypeString())
+	}
+	verify.Values(t, "types", types, []string{"returnResultLast", "invoke", "returnError", "reject", "invoke"})
+
+	if c := tcap.NewComponentList().Components(); c != nil {
+		t.Errorf("empty: got %v", c)
+	}
+}
+
+func TestTypedComponents(t *testing.T) {
+	code, err := tcap.NewReturnErrorWithCode(3, tcap.LocalErrorCode(tcap.MAPSystemFailure), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := tcap.NewContinueMessage(
+		tcap.WithOTID(0x22222222), tcap.WithDTID(0x11111111),
+		tcap.WithComponents(
+			tcap.NewInvoke(1, -1, 46, true, []byte{0x04, 0x01, 0xaa}),
+			tcap.NewLinkedInvoke(2, 1, 47, true, nil),
+			tcap.NewReturnResult(0, 45, true, false, []byte{0x04, 0x01, 0xbb}),
+			code,
+			tcap.NewRejectNotDerivable(tcap.ProblemMistypedComponent),
+		),
+	)
+	b, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := tcap.Parse(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	invokes, err := parsed.Invokes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(invokes) != 2 {
+		t.Fatalf("got %d invokes, want 2", len(invokes))
+	}
+	verify.Values(t, "invoke", []any{invokes[0].InvokeID, invokes[0].OpCode, invokes[0].Parameter, invokes[0].LinkedID},
+		[]any{1, int64(46), []byte{0x04, 0x01, 0xaa}, (*int)(nil)})
+	if lk := invokes[1].LinkedID; lk == nil || *lk != 1 {
+		t.Errorf("linked ID: got %v, want 1", lk)
+	}
+
+	results, err := parsed.ReturnResults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "result", []any{len(results), results[0].InvokeID, results[0].Last, results[0].OpCode}, []any{1, 0, false, int64(45)})
+
+	errs, err := parsed.ReturnErrors()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "error", errs[0].Code.Name("map"), "systemFailure")
+
+	rejects, err := parsed.Rejects()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "reject", []any{rejects[0].InvokeID, rejects[0].Problem}, []any{(*int)(nil), tcap.ProblemMistypedComponent})
+}
@@ -0,0 +1,151 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import (
+	"bufio"
+	"io"
+)
+
+// Decoder reads and decodes TCAP messages or IEs one at a time from an input stream,
+// e.g., a TCP connection or a large capture file, without buffering everything in memory.
+//
+// The Decoder may read data from r beyond the element returned.
+type Decoder struct {
+	r *bufio.Reader
+
+	// Options is used to limit the elements read and to parse them.
+	// Copy is implied, as every element is read into a new byte array.
+	Options ParseOptions
+}
+
+// NewDecoder creates a new Decoder reading from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: bufio.NewReader(r)}
+}
+
+// ReadElement reads the bytes of the next element, in definite-length or indefinite-length form.
+//
+// It returns io.EOF if no more element is available, and io.ErrUnexpectedEOF if the
+// stream ends in the middle of an element.
+func (d *Decoder) ReadElement() ([]byte, error) {
+	if _, err := d.r.Peek(1); err != nil {
+		return nil, err
+	}
+
+	b, err := d.readElement(nil, 1)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return b, err
+}
+
+// Decode reads the next TCAP message.
+func (d *Decoder) Decode() (*TCAP, error) {
+	b, err := d.ReadElement()
+	if err != nil {
+		return nil, err
+	}
+	return d.Options.ParseTCAP(b)
+}
+
+// DecodeIE reads the next IE and parses its children recursively.
+func (d *Decoder) DecodeIE() (*IE, error) {
+	b, err := d.ReadElement()
+	if err != nil {
+		return nil, err
+	}
+
+	o := d.Options
+	o.Copy = false
+	ies, err := o.ParseAsBER(b)
+	if err != nil {
+		return nil, err
+	}
+	return ies[0], nil
+}
+
+// readElement appends the bytes of the next element at the nesting level depth to b.
+func (d *Decoder) readElement(b []byte, depth int) ([]byte, error) {
+	o := d.Options
+	if o.MaxDepth > 0 && depth > o.MaxDepth {
+		return nil, ErrMaxDepthExceeded
+	}
+
+	first, err := d.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	b = append(b, first)
+
+	if first&0x1f == highTagNumber {
+		for n := 0; ; n++ {
+			if n == 4 {
+				return nil, ErrTagOverflow
+			}
+			c, err := d.r.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+			b = append(b, c)
+			if c&0x80 == 0 {
+				break
+			}
+		}
+	}
+
+	l, err := d.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	b = append(b, l)
+
+	if l == 0x80 {
+		if first&0x20 == 0 {
+			_, _, err := UnmarshalAsn1ElementLength([]byte{first, l})
+			return nil, err
+		}
+		for {
+			eoc, err := d.r.Peek(2)
+			if err != nil {
+				return nil, err
+			}
+			if eoc[0] == 0 && eoc[1] == 0 {
+				_, _ = d.r.Discard(2)
+				return append(b, 0x00, 0x00), nil
+			}
+			if b, err = d.readElement(b, depth+1); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	length := int(l)
+	if l > 0x80 {
+		n := int(l & 0x7f)
+		if n > 4 {
+			return nil, ErrLengthTooLong
+		}
+		length = 0
+		for i := 0; i < n; i++ {
+			c, err := d.r.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+			b = append(b, c)
+			length = length<<8 | int(c)
+		}
+	}
+	if err := o.checkLength(length, len(b)+length); err != nil {
+		return nil, err
+	}
+
+	start := len(b)
+	b = append(b, make([]byte, length)...)
+	if _, err := io.ReadFull(d.r, b[start:]); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
@@ -0,0 +1,92 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestDecoder(t *testing.T) {
+	var stream []byte
+	for _, m := range []*tcap.TCAP{
+		tcap.NewBeginInvoke(0x11111111, 1, 45, nil),
+		tcap.NewEndReturnResult(0x22222222, 2, 45, true, nil),
+	} {
+		b, err := m.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		stream = append(stream, b...)
+	}
+	indefinite := []byte{0x30, 0x80, 0xa1, 0x80, 0x02, 0x01, 0x05, 0x00, 0x00, 0x9f, 0x20, 0x01, 0xaa, 0x00, 0x00}
+	stream = append(stream, indefinite...)
+
+	d := tcap.NewDecoder(iotest.OneByteReader(bytes.NewReader(stream)))
+
+	begin, err := d.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := begin.OTID(), uint32(0x11111111); got != want {
+		t.Errorf("OTID: got %x, want %x", got, want)
+	}
+	end, err := d.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := end.DTID(), uint32(0x22222222); got != want {
+		t.Errorf("DTID: got %x, want %x", got, want)
+	}
+
+	i, err := d.DecodeIE()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(i.IE), 2; got != want {
+		t.Fatalf("children: got %d, want %d", got, want)
+	}
+	verify.Values(t, "Value", i.IE[1].Value, []byte{0xaa})
+
+	if _, err := d.ReadElement(); err != io.EOF {
+		t.Errorf("end of stream: got %v, want %v", err, io.EOF)
+	}
+
+	d = tcap.NewDecoder(bytes.NewReader(indefinite[:10]))
+	if _, err := d.ReadElement(); err != io.ErrUnexpectedEOF {
+		t.Errorf("truncated: got %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+}
+
+func TestDecoderIndefiniteLength(t *testing.T) {
+	begin := []byte{
+		0x62, 0x80, 0x48, 0x04, 0x11, 0x11, 0x11, 0x11,
+		0x6c, 0x80, 0xa1, 0x80, 0x02, 0x01, 0x01, 0x02, 0x01, 0x2d, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00,
+	}
+	end, err := tcap.NewEndReturnResult(0x22222222, 1, 45, true, nil).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := tcap.NewDecoder(iotest.OneByteReader(bytes.NewReader(append(begin, end...))))
+
+	m, err := d.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "Begin", []any{m.OTID(), m.OpCode()}, []any{uint32(0x11111111), []uint8{45}})
+
+	if m, err = d.Decode(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := m.DTID(), uint32(0x22222222); got != want {
+		t.Errorf("DTID: got %x, want %x", got, want)
+	}
+}
@@ -13,8 +13,12 @@ import (
 const (
 	AARQ = iota
 	AARE
+	_
+	_
 	ABRT
-	// AUDT = 0
+
+	// AUDT is the code in UniDialoguePDU, which is the same as AARQ.
+	AUDT = AARQ
 )
 
 // Application Context definitions.
@@ -83,7 +87,8 @@ const (
 	Null uint8 = iota
 	NoReasonGiven
 	ApplicationContextNameNotSupplied
-	NoCommonDialoguePortion = 2 // same as above...
+	NoCommonDialoguePortion            = 2 // same as above...
+	ApplicationContextNameNotSupported = 2 // same as above, spelled as in Q.773.
 )
 
 // Abort Source defnitions.
@@ -238,31 +243,25 @@ func NewABRT(abortsrc uint8, userinfo ...*IE) *DialoguePDU {
 	return d
 }
 
-/*
 // NewAUDT returns a new AUDT(Unidirectional Dialogue).
+//
+// AUDT is encoded in the same way as AARQ, and is distinguished by UnidialogueAsID of the Dialogue.
 func NewAUDT(protover int, context, contextver uint8, userinfo ...*IE) *DialoguePDU {
-	d := NewDialoguePDU(
-		AUDT,
-		protover,
-		context,
-		contextver,
-		0,
-		0,
-		0,
-		0,
-	)
-	if len(userinfo) > 0 {
-		d.UserInformation = userinfo[0]
-	}
-	d.ProtocolVersion.Clear()
-	d.ApplicationContextName.Clear()
-	d.Result.Clear()
-	d.ResultSourceDiagnostic.Clear()
-	d.SetLength()
+	return NewAARQ(protover, context, contextver, userinfo...)
+}
 
-	return d
+// NewAUDTWithContext returns a new AUDT(Unidirectional Dialogue) of the application context,
+// with the Externals in the user information, if any.
+func NewAUDTWithContext(ac ApplicationContext, externals ...*External) (*DialoguePDU, error) {
+	d := NewAUDT(1, 0, 0)
+	if err := d.SetApplicationContext(ac); err != nil {
+		return nil, err
+	}
+	if err := d.SetExternals(externals...); err != nil {
+		return nil, err
+	}
+	return d, nil
 }
-*/
 
 // MarshalBinary returns the byte sequence generated from a DialoguePDU.
 func (d *DialoguePDU) MarshalBinary() ([]byte, error) {
@@ -273,6 +272,16 @@ func (d *DialoguePDU) MarshalBinary() ([]byte, error) {
 	return b, nil
 }
 
+// AppendBinary appends the byte sequence generated from a DialoguePDU instance to b.
+func (d *DialoguePDU) AppendBinary(b []byte) ([]byte, error) {
+	return appendBinary(b, d)
+}
+
+// WriteTo writes the byte sequence generated from a DialoguePDU instance to w.
+func (d *DialoguePDU) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(w, d)
+}
+
 func (d *DialoguePDU) MarshalTo(b []byte) error {
 	if len(b) < 2 {
 		return io.ErrUnexpectedEOF
@@ -305,7 +314,7 @@ func (d *DialoguePDU) MarshalTo(b []byte) error {
 }
 
 func (d *DialoguePDU) marshalAARQTo(b []byte) error {
-	var offset = 2
+	var offset = 0
 	if field := d.ProtocolVersion; field != nil {
 		if err := field.MarshalTo(b[offset : offset+field.MarshalLen()]); err != nil {
 			return err
@@ -330,7 +339,7 @@ func (d *DialoguePDU) marshalAARQTo(b []byte) error {
 }
 
 func (d *DialoguePDU) marshalAARETo(b []byte) error {
-	var offset = 2
+	var offset = 0
 	if field := d.ProtocolVersion; field != nil {
 		if err := field.MarshalTo(b[offset : offset+field.MarshalLen()]); err != nil {
 			return err
@@ -369,7 +378,7 @@ func (d *DialoguePDU) marshalAARETo(b []byte) error {
 }
 
 func (d *DialoguePDU) marshalABRTTo(b []byte) error {
-	var offset = 2
+	var offset = 0
 	if field := d.AbortSource; field != nil {
 		if err := field.MarshalTo(b[offset : offset+field.MarshalLen()]); err != nil {
 			return err
@@ -520,7 +529,12 @@ func (d *DialoguePDU) parseABRTFromBytes(b []byte) error {
 
 // MarshalLen returns the serial length of DialoguePDU.
 func (d *DialoguePDU) MarshalLen() int {
-	l := 2
+	return headerLen(d.Type, d.Length) + d.valueLen()
+}
+
+// valueLen returns the length of the fields in DialoguePDU.
+func (d *DialoguePDU) valueLen() int {
+	l := 0
 	switch d.Type.Code() {
 	case AARQ:
 		if field := d.ProtocolVersion; field != nil {
@@ -585,7 +599,7 @@ func (d *DialoguePDU) SetLength() {
 	if field := d.UserInformation; field != nil {
 		field.SetLength()
 	}
-	d.Length = (d.MarshalLen() - 2)
+	d.Length = d.valueLen()
 }
 
 // DialogueType returns the name of Dialogue Type in string.
@@ -621,88 +635,7 @@ func (d *DialoguePDU) Context() string {
 	}
 
 	if d.Type.Code() == AARQ || d.Type.Code() == AARE {
-		switch appCtx.Value[7] {
-		case NetworkLocUpContext:
-			return "networkLocUpContext"
-		case LocationCancellationContext:
-			return "locationCancellationContext"
-		case RoamingNumberEnquiryContext:
-			return "roamingNumberEnquiryContext"
-		case IstAlertingContext:
-			return "istAlertingContext"
-		case LocationInfoRetrievalContext:
-			return "locationInfoRetrievalContext"
-		case CallControlTransferContext:
-			return "callControlTransferContext"
-		case ReportingContext:
-			return "reportingContext"
-		case CallCompletionContext:
-			return "callCompletionContext"
-		case ServiceTerminationContext:
-			return "serviceTerminationContext"
-		case ResetContext:
-			return "resetContext"
-		case HandoverControlContext:
-			return "handoverControlContext"
-		case SIWFSAllocationContext:
-			return "sIWFSAllocationContext"
-		case EquipmentMngtContext:
-			return "equipmentMngtContext"
-		case InfoRetrievalContext:
-			return "infoRetrievalContext"
-		case InterVlrInfoRetrievalContext:
-			return "interVlrInfoRetrievalContext"
-		case SubscriberDataMngtContext:
-			return "SubscriberDataMngtContext"
-		case TracingContext:
-			return "tracingContext"
-		case NetworkFunctionalSsContext:
-			return "networkFunctionalSsContext"
-		case NetworkUnstructuredSsContext:
-			return "networkUnstructuredSsContext"
-		case ShortMsgGatewayContext:
-			return "shortMsgGatewayContext"
-		case ShortMsgRelayContext:
-			return "shortMsgRelayContext"
-		case SubscriberDataModificationNotificationContext:
-			return "subscriberDataModificationNotificationContext"
-		case ShortMsgAlertContext:
-			return "shortMsgAlertContext"
-		case MwdMngtContext:
-			return "mwdMngtContext"
-		case ShortMsgMTRelayContext:
-			return "shortMsgMTRelayContext"
-		case ImsiRetrievalContext:
-			return "imsiRetrievalContext"
-		case MsPurgingContext:
-			return "msPurgingContext"
-		case SubscriberInfoEnquiryContext:
-			return "subscriberInfoEnquiryContext"
-		case AnyTimeInfoEnquiryContext:
-			return "anyTimeInfoEnquiryContext"
-		case GroupCallControlContext:
-			return "groupCallControlContext"
-		case GprsLocationUpdateContext:
-			return "gprsLocationUpdateContext"
-		case GprsLocationInfoRetrievalContext:
-			return "gprsLocationInfoRetrievalContext"
-		case FailureReportContext:
-			return "failureReportContext"
-		case GprsNotifyContext:
-			return "gprsNotifyContext"
-		case SsInvocationNotificationContext:
-			return "ssInvocationNotificationContext"
-		case LocationSvcGatewayContext:
-			return "locationSvcGatewayContext"
-		case LocationSvcEnquiryContext:
-			return "locationSvcEnquiryContext"
-		case AuthenticationFailureReportContext:
-			return "authenticationFailureReportContext"
-		case MmEventReportingContext:
-			return "mmEventReportingContext"
-		case AnyTimeInfoHandlingContext:
-			return "anyTimeInfoHandlingContext"
-		}
+		return mapContextName(appCtx.Value[7])
 	}
 
 	return ""
@@ -726,7 +659,7 @@ func (d *DialoguePDU) ContextVersion() string {
 
 // String returns DialoguePDU in human readable string.
 func (d *DialoguePDU) String() string {
-	return fmt.Sprintf("{Type: %#x, Length: %d, ProtocolVersion: %v, ApplicationContextName: %v, Result: %v, ResultSourceDiagnostic: %v, AbortSource: %v, UserInformation: %v}",
+	return fmt.Sprintf("{Type: %#x, Length: %d, ProtocolVersion: %s, ApplicationContextName: %s, Result: %s, ResultSourceDiagnostic: %s, AbortSource: %s, UserInformation: %s}",
 		d.Type,
 		d.Length,
 		d.ProtocolVersion,
@@ -5,6 +5,8 @@
 package tcap
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
 )
@@ -49,6 +51,69 @@ func NewDialogue(oid, ver uint8, pdu *DialoguePDU, payload []byte) *Dialogue {
 	return d
 }
 
+// ErrNotUnidialogue is returned when the TCAP has no AUDT in Unidialogue-As-Id.
+var ErrNotUnidialogue = errors.New("tcap: no unidialogue")
+
+// AUDT returns the AUDT in the Dialogue Portion of the Unidirectional, or ErrNotUnidialogue
+// if the Dialogue Portion is absent or is not Unidialogue-As-Id.
+func (t *TCAP) AUDT() (*DialoguePDU, error) {
+	d := t.Dialogue
+	if d == nil || !d.IsUnidialogue() || d.DialoguePDU == nil || d.DialoguePDU.Type.Code() != AUDT {
+		return nil, ErrNotUnidialogue
+	}
+	return d.DialoguePDU, nil
+}
+
+// IsUnidialogue reports whether the Dialogue is Unidialogue-As-Id, whose DialoguePDU is AUDT.
+func (d *Dialogue) IsUnidialogue() bool {
+	oid := d.ObjectIdentifier
+	return oid != nil && len(oid.Value) == 7 && oid.Value[5] == UnidialogueAsID
+}
+
+// Anomalies returns the deviations of the Dialogue Portion from Q.773 that are tolerated in decoding,
+// e.g., the EXTERNAL in octet-aligned encoding instead of single-ASN1-type, or the protocol-version
+// without the unused bits octet. It returns nil if there is none.
+func (d *Dialogue) Anomalies() []Violation {
+	var v violations
+	if d.ExternalTag != NewUniversalConstructorTag(8) {
+		v.add("ExternalTag", "%#x instead of EXTERNAL", uint32(d.ExternalTag))
+	}
+	if oid := d.ObjectIdentifier; oid == nil || oid.Tag != NewUniversalPrimitiveTag(6) {
+		v.add("ObjectIdentifier", "missing direct-reference")
+	} else if len(oid.Value) != 7 || !bytes.Equal(oid.Value[:5], []byte{0, 17, 134, 5, 1}) ||
+		(oid.Value[5] != DialogueAsID && oid.Value[5] != UnidialogueAsID) {
+		v.add("ObjectIdentifier", "neither dialogue-as-id nor unidialogue-as-id")
+	}
+	if enc := d.SingleAsn1Type; enc == nil || enc.Tag != NewContextSpecificConstructorTag(0) {
+		v.add("SingleAsn1Type", "encoding is not single-ASN1-type")
+	}
+
+	pdu := d.DialoguePDU
+	if pdu == nil {
+		return v
+	}
+	switch pdu.Type.Code() {
+	case AARQ, AARE:
+		if pv := pdu.ProtocolVersion; pv != nil {
+			if _, err := pv.BitString(); err != nil {
+				v.add("DialoguePDU.ProtocolVersion", "malformed BIT STRING, e.g., missing unused bits octet")
+			}
+		}
+		if _, err := pdu.ApplicationContext(); err != nil {
+			v.add("DialoguePDU.ApplicationContextName", "missing or malformed")
+		}
+	}
+	if pdu.UserInformation != nil {
+		if _, err := pdu.Externals(); err != nil {
+			v.add("DialoguePDU.UserInformation", "malformed EXTERNAL")
+		}
+	}
+	if len(v) == 0 {
+		return nil
+	}
+	return v
+}
+
 // MarshalBinary returns the byte sequence generated from a Dialogue.
 func (d *Dialogue) MarshalBinary() ([]byte, error) {
 	b := make([]byte, d.MarshalLen())
@@ -58,6 +123,16 @@ func (d *Dialogue) MarshalBinary() ([]byte, error) {
 	return b, nil
 }
 
+// AppendBinary appends the byte sequence generated from a Dialogue instance to b.
+func (d *Dialogue) AppendBinary(b []byte) ([]byte, error) {
+	return appendBinary(b, d)
+}
+
+// WriteTo writes the byte sequence generated from a Dialogue instance to w.
+func (d *Dialogue) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(w, d)
+}
+
 func (d *Dialogue) MarshalTo(b []byte) error {
 	// 1. Prepare Length Headers
 	dialLenBytes := MarshalAsn1ElementLength(d.Length)
@@ -146,9 +221,12 @@ func (d *Dialogue) UnmarshalBinary(b []byte) error {
 		return err
 	}
 	d.ExternalTag = Tag(b[1+lLength])
-	d.ExternalLength = int(b[2+lLength])
+	eLength := 0
+	if d.ExternalLength, eLength, err = UnmarshalAsn1ElementLength(b[1+lLength:]); err != nil {
+		return err
+	}
 
-	var offset = 3 + lLength
+	var offset = 2 + lLength + eLength
 	d.ObjectIdentifier, err = ParseIE(b[offset:])
 	if err != nil {
 		return err
@@ -216,12 +294,18 @@ func (d *Dialogue) SetValsFrom(berParsed *IE) error {
 
 // MarshalLen returns the serial length of Dialogue.
 func (d *Dialogue) MarshalLen() int {
-	l := 4
+	return headerLen(d.Tag, d.Length) + headerLen(d.ExternalTag, d.ExternalLength) + d.externalValueLen()
+}
+
+// externalValueLen returns the length of the contents of EXTERNAL in Dialogue.
+func (d *Dialogue) externalValueLen() int {
+	l := 0
 	if field := d.ObjectIdentifier; field != nil {
 		l += field.MarshalLen()
 	}
 	if field := d.DialoguePDU; field != nil {
-		l += field.MarshalLen() + 2 // 2 = singleAsn1Type IE Header
+		pduLen := field.MarshalLen()
+		l += headerLen(NewContextSpecificConstructorTag(0), pduLen) + pduLen // singleAsn1Type IE Header + DialoguePDU
 	}
 
 	return l + len(d.Payload)
@@ -234,15 +318,18 @@ func (d *Dialogue) SetLength() {
 	}
 	if d.DialoguePDU != nil {
 		d.DialoguePDU.SetLength()
+		if d.SingleAsn1Type != nil {
+			d.SingleAsn1Type.Length = d.DialoguePDU.MarshalLen()
+		}
 	}
 
-	d.Length = (d.MarshalLen() - 2)
-	d.ExternalLength = (d.MarshalLen() - 4)
+	d.ExternalLength = d.externalValueLen()
+	d.Length = headerLen(d.ExternalTag, d.ExternalLength) + d.ExternalLength
 }
 
 // String returns the SCCP common header values in human readable format.
 func (d *Dialogue) String() string {
-	return fmt.Sprintf("{Tag: %#x, Length: %d, ExternalTag: %x, ExternalLength: %d, ObjectIdentifier: %v, SingleAsn1Type: %v, DialoguePDU: %v, Payload: %x}",
+	return fmt.Sprintf("{Tag: %#x, Length: %d, ExternalTag: %x, ExternalLength: %d, ObjectIdentifier: %s, SingleAsn1Type: %s, DialoguePDU: %s, Payload: %x}",
 		d.Tag,
 		d.Length,
 		d.ExternalTag,
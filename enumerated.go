@@ -0,0 +1,120 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Enumerated is a value of ENUMERATED type with the name of the field it belongs to.
+//
+// The field name is used to resolve the symbolic name of the value through
+// the names registered with RegisterEnumerated.
+type Enumerated struct {
+	Field string
+	Value int64
+}
+
+var (
+	enumMu    sync.RWMutex
+	enumNames = map[string]map[int64]string{
+		"eventTypeBCSM": {
+			2:  "collectedInfo",
+			3:  "analyzedInformation",
+			4:  "routeSelectFailure",
+			5:  "oCalledPartyBusy",
+			6:  "oNoAnswer",
+			7:  "oAnswer",
+			8:  "oMidCall",
+			9:  "oDisconnect",
+			10: "oAbandon",
+			12: "termAttemptAuthorized",
+			13: "tBusy",
+			14: "tNoAnswer",
+			15: "tAnswer",
+			16: "tMidCall",
+			17: "tDisconnect",
+			18: "tAbandon",
+			19: "oTermSeized",
+			27: "callAccepted",
+			50: "oChangeOfPosition",
+			51: "tChangeOfPosition",
+			52: "oServiceChange",
+			53: "tServiceChange",
+		},
+	}
+)
+
+// RegisterEnumerated registers the symbolic names of the values of the field.
+//
+// The names given are merged into the ones already registered for the field.
+func RegisterEnumerated(field string, names map[int64]string) {
+	enumMu.Lock()
+	defer enumMu.Unlock()
+
+	m, ok := enumNames[field]
+	if !ok {
+		m = map[int64]string{}
+		enumNames[field] = m
+	}
+	for v, name := range names {
+		m[v] = name
+	}
+}
+
+// ReplaceEnumerated replaces the symbolic names of the values of the field at once,
+// so that they can be reloaded at runtime. The field is removed if names is empty.
+func ReplaceEnumerated(field string, names map[int64]string) {
+	m := make(map[int64]string, len(names))
+	for v, name := range names {
+		m[v] = name
+	}
+
+	enumMu.Lock()
+	defer enumMu.Unlock()
+
+	if len(m) == 0 {
+		delete(enumNames, field)
+		return
+	}
+	enumNames[field] = m
+}
+
+// EnumeratedName returns the symbolic name registered for the value of the field.
+func EnumeratedName(field string, v int64) (string, bool) {
+	enumMu.RLock()
+	defer enumMu.RUnlock()
+
+	name, ok := enumNames[field][v]
+	return name, ok
+}
+
+// NewEnumerated creates a new IE with v encoded as an ENUMERATED.
+func NewEnumerated(tag Tag, v int64) *IE {
+	return NewInteger(tag, v)
+}
+
+// Enumerated returns the value of IE decoded as an ENUMERATED of the field.
+func (i *IE) Enumerated(field string) (Enumerated, error) {
+	v, err := i.Int64()
+	if err != nil {
+		return Enumerated{}, err
+	}
+	return Enumerated{Field: field, Value: v}, nil
+}
+
+// String returns the symbolic name of the value, or the value in decimal if not registered.
+func (e Enumerated) String() string {
+	if name, ok := EnumeratedName(e.Field, e.Value); ok {
+		return name
+	}
+	return fmt.Sprintf("%d", e.Value)
+}
+
+// MarshalText implements encoding.TextMarshaler, so that JSON output shows the name.
+func (e Enumerated) MarshalText() ([]byte, error) {
+	return []byte(e.String()), nil
+}
@@ -0,0 +1,60 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+)
+
+func TestEnumerated(t *testing.T) {
+	tag := tcap.NewContextSpecificPrimitiveTag(0)
+
+	e, err := tcap.NewEnumerated(tag, 7).Enumerated("eventTypeBCSM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := e.String(), "oAnswer"; got != want {
+		t.Errorf("builtin: got %s, want %s", got, want)
+	}
+
+	tcap.RegisterEnumerated("testField", map[int64]string{1: "one"})
+	cases := []struct {
+		v    int64
+		want string
+	}{
+		{1, "one"},
+		{2, "2"},
+	}
+	for _, c := range cases {
+		e, err := tcap.NewEnumerated(tag, c.v).Enumerated("testField")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := e.String(); got != c.want {
+			t.Errorf("String: got %s, want %s", got, c.want)
+		}
+	}
+
+	tcap.ReplaceEnumerated("testField", map[int64]string{2: "two"})
+	if _, ok := tcap.EnumeratedName("testField", 1); ok {
+		t.Error("EnumeratedName after Replace: got ok for removed value")
+	}
+	if got, _ := tcap.EnumeratedName("testField", 2); got != "two" {
+		t.Errorf("EnumeratedName after Replace: got %s, want two", got)
+	}
+
+	b, err := json.Marshal(struct {
+		Event tcap.Enumerated `json:"event"`
+	}{e})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b), `{"event":"oAnswer"}`; got != want {
+		t.Errorf("JSON: got %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import (
+	"bytes"
+	"slices"
+)
+
+// Equal reports whether the IEs have the same content, regardless of the differences in
+// the form of length (e.g., long-form or indefinite-length) of them and their descendants.
+func Equal(a, b *IE) bool {
+	return Diff(a, b) == nil
+}
+
+// Diff returns the path of tags to the first element in which the IEs differ, or nil if they are Equal.
+//
+// The path ends with the tag of the element in a, or the one in b if a has no element there.
+func Diff(a, b *IE) []Tag {
+	return diff(nil, a, b)
+}
+
+// DiffTCAP returns the path of tags to the first element in which the TCAP messages differ
+// when encoded, or nil if they are the same.
+func DiffTCAP(a, b *TCAP) ([]Tag, error) {
+	x, err := a.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	y, err := b.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	xs, err := splitIEs(x)
+	if err != nil {
+		return nil, err
+	}
+	ys, err := splitIEs(y)
+	if err != nil {
+		return nil, err
+	}
+	return diffAll(nil, xs, ys), nil
+}
+
+// diff returns the path to the first element in which the IEs under the path differ.
+func diff(path []Tag, a, b *IE) []Tag {
+	switch {
+	case a == nil && b == nil:
+		return nil
+	case a == nil:
+		return append(slices.Clone(path), b.Tag)
+	case b == nil || a.Tag != b.Tag:
+		return append(slices.Clone(path), a.Tag)
+	}
+
+	path = append(path, a.Tag)
+	if a.Tag.Form() == Constructor {
+		x, errX := splitIEs(a.Value)
+		y, errY := splitIEs(b.Value)
+		if errX == nil && errY == nil {
+			return diffAll(path, x, y)
+		}
+	}
+
+	if !bytes.Equal(a.Value, b.Value) {
+		return slices.Clone(path)
+	}
+	return nil
+}
+
+// diffAll returns the path to the first element in which the lists of IEs under the path differ.
+func diffAll(path []Tag, x, y []*IE) []Tag {
+	for n := 0; n < max(len(x), len(y)); n++ {
+		var a, b *IE
+		if n < len(x) {
+			a = x[n]
+		}
+		if n < len(y) {
+			b = y[n]
+		}
+		if d := diff(path, a, b); d != nil {
+			return d
+		}
+	}
+	return nil
+}
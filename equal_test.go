@@ -0,0 +1,53 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestEqual(t *testing.T) {
+	parse := func(b []byte) *tcap.IE {
+		i, err := tcap.ParseIE(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return i
+	}
+
+	definite := parse([]byte{0x30, 0x06, 0xa1, 0x04, 0x02, 0x02, 0x01, 0x00})
+	cases := []struct {
+		description string
+		other       *tcap.IE
+		diff        []tcap.Tag
+	}{
+		{"LongForm", parse([]byte{0x30, 0x81, 0x08, 0xa1, 0x82, 0x00, 0x04, 0x02, 0x02, 0x01, 0x00}), nil},
+		{"Indefinite", parse([]byte{0x30, 0x80, 0xa1, 0x80, 0x02, 0x02, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00}), nil},
+		{"Value", parse([]byte{0x30, 0x06, 0xa1, 0x04, 0x02, 0x02, 0x01, 0x01}), []tcap.Tag{0x30, 0xa1, 0x02}},
+		{"Tag", parse([]byte{0x30, 0x06, 0xa2, 0x04, 0x02, 0x02, 0x01, 0x00}), []tcap.Tag{0x30, 0xa1}},
+		{"Extra", parse([]byte{0x30, 0x08, 0xa1, 0x04, 0x02, 0x02, 0x01, 0x00, 0x05, 0x00}), []tcap.Tag{0x30, 0x05}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			if got, want := tcap.Equal(definite, c.other), c.diff == nil; got != want {
+				t.Errorf("Equal: got %v, want %v", got, want)
+			}
+			verify.Values(t, "Diff", tcap.Diff(definite, c.other), c.diff)
+		})
+	}
+
+	d, err := tcap.DiffTCAP(
+		tcap.NewBeginInvoke(0x11111111, 1, 45, nil),
+		tcap.NewBeginInvoke(0x11111111, 1, 46, nil),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "DiffTCAP", d, []tcap.Tag{0x62, 0x6c, 0xa1, 0x02})
+}
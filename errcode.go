@@ -0,0 +1,253 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrInvalidErrorCode is returned when the Component is not a ReturnError or its Error Code is malformed.
+var ErrInvalidErrorCode = errors.New("tcap: invalid error code")
+
+// ErrorCode is the Error Code in ReturnError, which is either local (INTEGER) or global (OBJECT IDENTIFIER).
+type ErrorCode struct {
+	// Local is the local Error Code, used if Global is nil.
+	Local int64
+
+	// Global is the global Error Code, or nil if local.
+	Global OID
+}
+
+// LocalErrorCode returns the local ErrorCode of v, e.g., LocalErrorCode(MAPSystemFailure).
+func LocalErrorCode(v int64) ErrorCode {
+	return ErrorCode{Local: v}
+}
+
+// GlobalErrorCode returns the global ErrorCode of the OID.
+func GlobalErrorCode(o OID) ErrorCode {
+	return ErrorCode{Global: o}
+}
+
+// IsGlobal reports whether the ErrorCode is global.
+func (e ErrorCode) IsGlobal() bool {
+	return e.Global != nil
+}
+
+// String returns the ErrorCode in decimal or in dotted notation if global.
+func (e ErrorCode) String() string {
+	if e.IsGlobal() {
+		return e.Global.String()
+	}
+	return fmt.Sprintf("%d", e.Local)
+}
+
+// Name returns the name of the local ErrorCode registered for the application, e.g., "systemFailure"
+// for MAPSystemFailure of "map", or the ErrorCode in string if not registered.
+func (e ErrorCode) Name(application string) string {
+	if !e.IsGlobal() {
+		if info, ok := LookupError(application, e.Local); ok {
+			return info.Name
+		}
+	}
+	return e.String()
+}
+
+// ie returns the ErrorCode as an IE.
+func (e ErrorCode) ie() (*IE, error) {
+	if e.IsGlobal() {
+		return NewObjectIdentifier(NewUniversalPrimitiveTag(6), e.Global)
+	}
+	return NewInteger(NewUniversalPrimitiveTag(2), e.Local), nil
+}
+
+// NewReturnErrorWithCode returns a new single ReturnError Component of the local or global ErrorCode.
+func NewReturnErrorWithCode(invID int, code ErrorCode, param []byte) (*Component, error) {
+	i, err := code.ie()
+	if err != nil {
+		return nil, err
+	}
+	c := NewReturnError(invID, 0, true, param)
+	c.ErrorCode = i
+	c.SetLength()
+	return c, nil
+}
+
+// ErrCode returns the ErrorCode of the ReturnError.
+func (c *Component) ErrCode() (ErrorCode, error) {
+	ec := c.ErrorCode
+	if c.Type.Code() != ReturnError || ec == nil {
+		return ErrorCode{}, ErrInvalidErrorCode
+	}
+	switch ec.Tag {
+	case NewUniversalPrimitiveTag(2):
+		v, err := ec.Int64()
+		if err != nil {
+			return ErrorCode{}, err
+		}
+		return LocalErrorCode(v), nil
+	case NewUniversalPrimitiveTag(6):
+		o, err := ec.OID()
+		if err != nil {
+			return ErrorCode{}, err
+		}
+		return GlobalErrorCode(o), nil
+	}
+	return ErrorCode{}, ErrInvalidErrorCode
+}
+
+// ErrorInfo describes a local Error Code of an application.
+type ErrorInfo struct {
+	Name string
+
+	// Parameter is the ASN.1 type of the parameter expected, or empty if the error has none.
+	Parameter string
+}
+
+// MAP Error Code definitions (3GPP TS 29.002).
+const (
+	MAPUnknownSubscriber         int64 = 1
+	MAPUnidentifiedSubscriber    int64 = 5
+	MAPAbsentSubscriberSM        int64 = 6
+	MAPRoamingNotAllowed         int64 = 8
+	MAPIllegalSubscriber         int64 = 9
+	MAPTeleserviceNotProvisioned int64 = 11
+	MAPIllegalEquipment          int64 = 12
+	MAPCallBarred                int64 = 13
+	MAPFacilityNotSupported      int64 = 21
+	MAPAbsentSubscriber          int64 = 27
+	MAPSubscriberBusyForMTSMS    int64 = 31
+	MAPSMDeliveryFailure         int64 = 32
+	MAPMessageWaitingListFull    int64 = 33
+	MAPSystemFailure             int64 = 34
+	MAPDataMissing               int64 = 35
+	MAPUnexpectedDataValue       int64 = 36
+	MAPResourceLimitation        int64 = 51
+	MAPUnknownAlphabet           int64 = 71
+	MAPUSSDBusy                  int64 = 72
+)
+
+// CAP Error Code definitions (3GPP TS 29.078).
+const (
+	CAPCanceled                    int64 = 0
+	CAPCancelFailed                int64 = 1
+	CAPETCFailed                   int64 = 3
+	CAPImproperCallerResponse      int64 = 4
+	CAPMissingCustomerRecord       int64 = 6
+	CAPMissingParameter            int64 = 7
+	CAPParameterOutOfRange         int64 = 8
+	CAPRequestedInfoError          int64 = 10
+	CAPSystemFailure               int64 = 11
+	CAPTaskRefused                 int64 = 12
+	CAPUnavailableResource         int64 = 13
+	CAPUnexpectedComponentSequence int64 = 14
+	CAPUnexpectedDataValue         int64 = 15
+	CAPUnexpectedParameter         int64 = 16
+	CAPUnknownLegID                int64 = 17
+	CAPUnknownPDPID                int64 = 50
+	CAPUnknownCSID                 int64 = 51
+)
+
+var (
+	errorMu    sync.RWMutex
+	errorInfos = map[string]map[int64]ErrorInfo{
+		"map": {
+			MAPUnknownSubscriber:         {"unknownSubscriber", "UnknownSubscriberParam"},
+			3:                            {"unknownMSC", ""},
+			MAPUnidentifiedSubscriber:    {"unidentifiedSubscriber", "UnidentifiedSubParam"},
+			MAPAbsentSubscriberSM:        {"absentSubscriberSM", "AbsentSubscriberSM-Param"},
+			7:                            {"unknownEquipment", ""},
+			MAPRoamingNotAllowed:         {"roamingNotAllowed", "RoamingNotAllowedParam"},
+			MAPIllegalSubscriber:         {"illegalSubscriber", "IllegalSubscriberParam"},
+			10:                           {"bearerServiceNotProvisioned", "BearerServNotProvParam"},
+			MAPTeleserviceNotProvisioned: {"teleserviceNotProvisioned", "TeleservNotProvParam"},
+			MAPIllegalEquipment:          {"illegalEquipment", "IllegalEquipmentParam"},
+			MAPCallBarred:                {"callBarred", "CallBarredParam"},
+			14:                           {"forwardingViolation", "ForwardingViolationParam"},
+			15:                           {"cug-Reject", "Cug-RejectParam"},
+			16:                           {"illegalSS-Operation", "IllegalSS-OperationParam"},
+			17:                           {"ss-ErrorStatus", "SS-Status"},
+			18:                           {"ss-NotAvailable", "SS-NotAvailableParam"},
+			19:                           {"ss-SubscriptionViolation", "SS-SubscriptionViolationParam"},
+			20:                           {"ss-Incompatibility", "SS-IncompatibilityCause"},
+			MAPFacilityNotSupported:      {"facilityNotSupported", "FacilityNotSupParam"},
+			MAPAbsentSubscriber:          {"absentSubscriber", "AbsentSubscriberParam"},
+			28:                           {"incompatibleTerminal", "IncompatibleTerminalParam"},
+			29:                           {"shortTermDenial", "ShortTermDenialParam"},
+			30:                           {"longTermDenial", "LongTermDenialParam"},
+			MAPSubscriberBusyForMTSMS:    {"subscriberBusyForMT-SMS", "SubBusyForMT-SMS-Param"},
+			MAPSMDeliveryFailure:         {"sm-DeliveryFailure", "SM-DeliveryFailureCause"},
+			MAPMessageWaitingListFull:    {"messageWaitingListFull", "MessageWaitListFullParam"},
+			MAPSystemFailure:             {"systemFailure", "SystemFailureParam"},
+			MAPDataMissing:               {"dataMissing", "DataMissingParam"},
+			MAPUnexpectedDataValue:       {"unexpectedDataValue", "UnexpectedDataParam"},
+			39:                           {"noRoamingNumberAvailable", "NoRoamingNbParam"},
+			44:                           {"numberChanged", "NumberChangedParam"},
+			45:                           {"busySubscriber", "BusySubscriberParam"},
+			46:                           {"noSubscriberReply", "NoSubscriberReplyParam"},
+			47:                           {"forwardingFailed", "ForwardingFailedParam"},
+			48:                           {"or-NotAllowed", "OR-NotAllowedParam"},
+			49:                           {"ati-NotAllowed", "ATI-NotAllowedParam"},
+			MAPResourceLimitation:        {"resourceLimitation", "ResourceLimitationParam"},
+			52:                           {"unauthorizedRequestingNetwork", "UnauthorizedRequestingNetwork-Param"},
+			53:                           {"unauthorizedLCSClient", "UnauthorizedLCSClient-Param"},
+			54:                           {"positionMethodFailure", "PositionMethodFailure-Param"},
+			58:                           {"unknownOrUnreachableLCSClient", "UnknownOrUnreachableLCSClient-Param"},
+			59:                           {"mm-EventNotSupported", "MM-EventNotSupported-Param"},
+			60:                           {"atsi-NotAllowed", "ATSI-NotAllowedParam"},
+			61:                           {"atm-NotAllowed", "ATM-NotAllowedParam"},
+			62:                           {"informationNotAvailable", "InformationNotAvailableParam"},
+			MAPUnknownAlphabet:           {"unknownAlphabet", ""},
+			MAPUSSDBusy:                  {"ussd-Busy", ""},
+		},
+		"cap": {
+			CAPCanceled:                    {"canceled", ""},
+			CAPCancelFailed:                {"cancelFailed", "CancelFailedPARAM"},
+			CAPETCFailed:                   {"eTCFailed", ""},
+			CAPImproperCallerResponse:      {"improperCallerResponse", ""},
+			CAPMissingCustomerRecord:       {"missingCustomerRecord", ""},
+			CAPMissingParameter:            {"missingParameter", ""},
+			CAPParameterOutOfRange:         {"parameterOutOfRange", ""},
+			CAPRequestedInfoError:          {"requestedInfoError", "RequestedInfoErrorParameter"},
+			CAPSystemFailure:               {"systemFailure", "UnavailableNetworkResource"},
+			CAPTaskRefused:                 {"taskRefused", "TaskRefusedParameter"},
+			CAPUnavailableResource:         {"unavailableResource", ""},
+			CAPUnexpectedComponentSequence: {"unexpectedComponentSequence", ""},
+			CAPUnexpectedDataValue:         {"unexpectedDataValue", ""},
+			CAPUnexpectedParameter:         {"unexpectedParameter", ""},
+			CAPUnknownLegID:                {"unknownLegID", ""},
+			CAPUnknownPDPID:                {"unknownPDPID", ""},
+			CAPUnknownCSID:                 {"unknownCSID", ""},
+		},
+	}
+)
+
+// RegisterErrors registers the local Error Codes of the application, e.g., "map", "cap" or a
+// vendor specific one.
+//
+// The ones given are merged into the ones already registered for the application.
+func RegisterErrors(application string, infos map[int64]ErrorInfo) {
+	errorMu.Lock()
+	defer errorMu.Unlock()
+
+	m, ok := errorInfos[application]
+	if !ok {
+		m = map[int64]ErrorInfo{}
+		errorInfos[application] = m
+	}
+	for code, info := range infos {
+		m[code] = info
+	}
+}
+
+// LookupError returns the ErrorInfo registered for the local Error Code of the application.
+func LookupError(application string, code int64) (ErrorInfo, bool) {
+	errorMu.RLock()
+	defer errorMu.RUnlock()
+
+	info, ok := errorInfos[application][code]
+	return info, ok
+}
@@ -4,7 +4,10 @@
 
 package tcap
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // InvalidCodeError indicates that Code in TCAP message is invalid.
 type InvalidCodeError struct {
@@ -15,3 +18,116 @@ type InvalidCodeError struct {
 func (e *InvalidCodeError) Error() string {
 	return fmt.Sprintf("tcap: got invalid code: %d", e.Code)
 }
+
+// InvalidTagError indicates that Tag in TCAP message is not the one expected.
+type InvalidTagError struct {
+	Tag Tag
+}
+
+// Error returns error message with violating content.
+func (e *InvalidTagError) Error() string {
+	return fmt.Sprintf("tcap: got invalid tag: %s", tagString(e.Tag))
+}
+
+// InvalidDigitError indicates that a digit string contains a digit not allowed.
+type InvalidDigitError struct {
+	Digit rune
+}
+
+// Error returns error message with violating content.
+func (e *InvalidDigitError) Error() string {
+	return fmt.Sprintf("tcap: got invalid digit: %q", e.Digit)
+}
+
+// InvalidCharacterError indicates that a string contains a character not in the alphabet.
+type InvalidCharacterError struct {
+	Char rune
+}
+
+// Error returns error message with violating content.
+func (e *InvalidCharacterError) Error() string {
+	return fmt.Sprintf("tcap: got character not in the alphabet: %q", e.Char)
+}
+
+// ValidationError indicates that a TCAP message violates the structural rules, with all the violations found.
+type ValidationError struct {
+	Violations []Violation
+}
+
+// Error returns error message with violating content.
+func (e *ValidationError) Error() string {
+	s := make([]string, len(e.Violations))
+	for n, v := range e.Violations {
+		s[n] = v.String()
+	}
+	return fmt.Sprintf("tcap: invalid message: %s", strings.Join(s, "; "))
+}
+
+// DialogueAnomalyError indicates that a Dialogue Portion deviates from Q.773, with all the anomalies found.
+type DialogueAnomalyError struct {
+	Anomalies []Violation
+}
+
+// Error returns error message with violating content.
+func (e *DialogueAnomalyError) Error() string {
+	s := make([]string, len(e.Anomalies))
+	for n, v := range e.Anomalies {
+		s[n] = v.String()
+	}
+	return fmt.Sprintf("tcap: off-spec dialogue portion: %s", strings.Join(s, "; "))
+}
+
+// TooLongError indicates that the length of an element or a message exceeds the limit.
+type TooLongError struct {
+	Field  string
+	Length int
+	Max    int
+}
+
+// Error returns error message with violating content.
+func (e *TooLongError) Error() string {
+	return fmt.Sprintf("tcap: %s length %d exceeds limit %d", e.Field, e.Length, e.Max)
+}
+
+// ParseError indicates where in the byte sequence parsing failed.
+type ParseError struct {
+	// Offset is the position of the element that failed to be parsed,
+	// from the beginning of the byte sequence given.
+	Offset int
+
+	// Tag is the tag of the element that failed to be parsed, which is zero if not available.
+	Tag Tag
+
+	// Path is the tags of the parents of the element, from the outermost one.
+	Path []Tag
+
+	// Err is the error that occurred.
+	Err error
+}
+
+// Error returns error message with violating content.
+func (e *ParseError) Error() string {
+	path := make([]string, len(e.Path))
+	for n, tag := range e.Path {
+		path[n] = tagString(tag)
+	}
+	return fmt.Sprintf("tcap: failed to parse %s at offset %d in [%s]: %v",
+		tagString(e.Tag), e.Offset, strings.Join(path, "/"), e.Err,
+	)
+}
+
+// Unwrap returns the error that occurred.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// TransitionError indicates that an event is not allowed in the state of the transaction.
+type TransitionError struct {
+	State TransactionState
+	Event string
+}
+
+// Error returns error message with violating content.
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("tcap: %s not allowed in %s state", e.Event, e.State)
+}
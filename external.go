@@ -0,0 +1,340 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import "errors"
+
+// Errors returned when handling EXTERNAL.
+var (
+	ErrNoExternal      = errors.New("tcap: no EXTERNAL with the OID in user information")
+	ErrInvalidExternal = errors.New("tcap: invalid EXTERNAL")
+)
+
+// ExternalEncoding is the choice of encoding in EXTERNAL.
+type ExternalEncoding uint8
+
+// ExternalEncoding definitions.
+const (
+	SingleASN1Type ExternalEncoding = iota
+	OctetAligned
+	Arbitrary
+)
+
+// External is an EXTERNAL in the user information of DialoguePDU, e.g., MAP-DialoguePDU.
+type External struct {
+	// DirectReference is the OID of the abstract syntax, or nil if absent.
+	DirectReference OID
+
+	// IndirectReference is the presentation context identifier, or nil if absent.
+	IndirectReference *int64
+
+	// DataValueDescriptor is the ObjectDescriptor, or empty if absent.
+	DataValueDescriptor string
+
+	// Encoding is the choice of Data. Data is the already-encoded value for SingleASN1Type, the octets
+	// for OctetAligned, and the contents octets of BIT STRING for Arbitrary, which can be decoded
+	// with BitString.UnmarshalBinary.
+	Encoding ExternalEncoding
+	Data     []byte
+}
+
+// ParseExternal decodes the byte sequence as an EXTERNAL.
+func ParseExternal(b []byte) (*External, error) {
+	e := &External{}
+	if err := e.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// MarshalBinary returns the byte sequence generated from an External.
+func (e *External) MarshalBinary() ([]byte, error) {
+	i, err := e.ie()
+	if err != nil {
+		return nil, err
+	}
+	return encodeTLV(i), nil
+}
+
+// UnmarshalBinary sets the values retrieved from byte sequence in an External.
+func (e *External) UnmarshalBinary(b []byte) error {
+	i, err := ParseIE(b)
+	if err != nil {
+		return err
+	}
+	return e.fromIE(i)
+}
+
+// ie returns the External as an IE with the children.
+func (e *External) ie() (*IE, error) {
+	var ref *IE
+	if e.DirectReference != nil {
+		var err error
+		if ref, err = NewObjectIdentifier(NewUniversalPrimitiveTag(6), e.DirectReference); err != nil {
+			return nil, err
+		}
+	}
+	return e.encode(ref)
+}
+
+// encode returns the External as an IE with the children, with the already-encoded direct-reference
+// given instead of DirectReference, which is omitted if nil.
+func (e *External) encode(ref *IE) (*IE, error) {
+	var children []*IE
+	if ref != nil {
+		children = append(children, ref)
+	}
+	if e.IndirectReference != nil {
+		children = append(children, NewInteger(NewUniversalPrimitiveTag(2), *e.IndirectReference))
+	}
+	if e.DataValueDescriptor != "" {
+		children = append(children, NewIE(NewUniversalPrimitiveTag(7), []byte(e.DataValueDescriptor)))
+	}
+
+	switch e.Encoding {
+	case SingleASN1Type:
+		children = append(children, NewIE(NewContextSpecificConstructorTag(0), e.Data))
+	case OctetAligned:
+		children = append(children, NewIE(NewContextSpecificPrimitiveTag(1), e.Data))
+	case Arbitrary:
+		children = append(children, NewIE(NewContextSpecificPrimitiveTag(2), e.Data))
+	default:
+		return nil, ErrInvalidExternal
+	}
+
+	var value []byte
+	for _, c := range children {
+		value = append(value, encodeTLV(c)...)
+	}
+	ext := NewIE(NewUniversalConstructorTag(8), value)
+	ext.IE = children
+	return ext, nil
+}
+
+// fromIE sets the values retrieved from the EXTERNAL as an IE in an External.
+func (e *External) fromIE(ext *IE) error {
+	if ext.Tag != NewUniversalConstructorTag(8) {
+		return ErrInvalidExternal
+	}
+	ies, err := splitIEs(ext.Value)
+	if err != nil {
+		return err
+	}
+
+	*e = External{}
+	for n, i := range ies {
+		switch i.Tag {
+		case NewUniversalPrimitiveTag(6):
+			if e.DirectReference, err = i.OID(); err != nil {
+				return err
+			}
+			continue
+		case NewUniversalPrimitiveTag(2):
+			v, err := i.Int64()
+			if err != nil {
+				return err
+			}
+			e.IndirectReference = &v
+			continue
+		case NewUniversalPrimitiveTag(7):
+			e.DataValueDescriptor = string(i.Value)
+			continue
+		case NewContextSpecificConstructorTag(0):
+			e.Encoding = SingleASN1Type
+		case NewContextSpecificPrimitiveTag(1):
+			e.Encoding = OctetAligned
+		case NewContextSpecificPrimitiveTag(2):
+			e.Encoding = Arbitrary
+		default:
+			return ErrInvalidExternal
+		}
+
+		// the encoding must be the last one.
+		if n != len(ies)-1 {
+			return ErrInvalidExternal
+		}
+		e.Data = i.Value
+		return nil
+	}
+	return ErrInvalidExternal
+}
+
+// External returns the value of IE decoded as an EXTERNAL.
+func (i *IE) External() (*External, error) {
+	e := &External{}
+	if err := e.fromIE(i); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// MAPDialogueAS is the encoded OID of map-DialogueAS (0.4.0.0.1.1.1.1), used as
+// the direct-reference of MAP-DialoguePDU in user information.
+var MAPDialogueAS = []byte{0x04, 0x00, 0x00, 0x01, 0x01, 0x01, 0x01}
+
+// NewSingleASN1External creates a new EXTERNAL with the OID as direct-reference and the
+// already-encoded value (e.g., MAP-OpenInfo) in single-ASN1-type encoding.
+func NewSingleASN1External(oid, value []byte) *IE {
+	return newExternal(oid, SingleASN1Type, value)
+}
+
+// SingleASN1ExternalValue returns the value in single-ASN1-type encoding of the EXTERNAL
+// if its direct-reference is the OID.
+func SingleASN1ExternalValue(ext *IE, oid []byte) ([]byte, bool) {
+	e, ok := externalWithOID(ext, oid)
+	if !ok || e.Encoding != SingleASN1Type {
+		return nil, false
+	}
+	return e.Data, true
+}
+
+// SetUserInformation sets the user information of DialoguePDU to the EXTERNALs given,
+// or removes it if none is given.
+//
+// The lengths of DialoguePDU are updated, but the ones of the parents are not.
+func (d *DialoguePDU) SetUserInformation(externals ...*IE) {
+	if len(externals) == 0 {
+		d.UserInformation = nil
+		d.SetLength()
+		return
+	}
+
+	var value []byte
+	for _, ext := range externals {
+		value = append(value, encodeTLV(ext)...)
+	}
+	d.UserInformation = NewIE(NewContextSpecificConstructorTag(30), value)
+	d.SetLength()
+}
+
+// SetExternals sets the user information of DialoguePDU to the Externals given,
+// or removes it if none is given.
+//
+// The lengths of DialoguePDU are updated, but the ones of the parents are not.
+func (d *DialoguePDU) SetExternals(externals ...*External) error {
+	ies := make([]*IE, len(externals))
+	for n, e := range externals {
+		i, err := e.ie()
+		if err != nil {
+			return err
+		}
+		ies[n] = i
+	}
+	d.SetUserInformation(ies...)
+	return nil
+}
+
+// AppendExternals appends the Externals given to the user information of DialoguePDU,
+// after the ones already in it, e.g., to attach a vendor payload after MAP-OpenInfo.
+//
+// The lengths of DialoguePDU are updated, but the ones of the parents are not.
+func (d *DialoguePDU) AppendExternals(externals ...*External) error {
+	if len(externals) == 0 {
+		return nil
+	}
+	ies, err := userInfoExternals(d)
+	if err != nil {
+		return err
+	}
+	for _, e := range externals {
+		i, err := e.ie()
+		if err != nil {
+			return err
+		}
+		ies = append(ies, i)
+	}
+	d.SetUserInformation(ies...)
+	return nil
+}
+
+// NewUserInformation creates a new user information containing the Externals given, which
+// can be passed as userinfo to NewAARQ, NewAARE, NewABRT and NewAUDT.
+func NewUserInformation(externals ...*External) (*IE, error) {
+	d := &DialoguePDU{}
+	if err := d.SetExternals(externals...); err != nil {
+		return nil, err
+	}
+	if d.UserInformation == nil {
+		return NewIE(NewContextSpecificConstructorTag(30), nil), nil
+	}
+	return d.UserInformation, nil
+}
+
+// Externals returns the Externals in the user information of DialoguePDU, if any.
+func (d *DialoguePDU) Externals() ([]*External, error) {
+	ies, err := userInfoExternals(d)
+	if err != nil {
+		return nil, err
+	}
+
+	externals := make([]*External, len(ies))
+	for n, i := range ies {
+		if externals[n], err = i.External(); err != nil {
+			return nil, err
+		}
+	}
+	return externals, nil
+}
+
+// SetSingleASN1UserInfo sets the user information of DialoguePDU to a single EXTERNAL
+// containing the already-encoded value with the OID.
+//
+// The lengths of DialoguePDU are updated, but the ones of the parents are not.
+func (d *DialoguePDU) SetSingleASN1UserInfo(oid, value []byte) {
+	d.SetUserInformation(NewSingleASN1External(oid, value))
+}
+
+// SingleASN1UserInfo returns the value in single-ASN1-type encoding of the EXTERNAL
+// with the OID in the user information of DialoguePDU.
+func (d *DialoguePDU) SingleASN1UserInfo(oid []byte) ([]byte, error) {
+	externals, err := userInfoExternals(d)
+	if err != nil {
+		return nil, err
+	}
+	for _, ext := range externals {
+		if v, ok := SingleASN1ExternalValue(ext, oid); ok {
+			return v, nil
+		}
+	}
+	return nil, ErrNoExternal
+}
+
+// newExternal creates a new EXTERNAL with the encoded OID as direct-reference and the data
+// in the encoding given.
+func newExternal(oid []byte, encoding ExternalEncoding, data []byte) *IE {
+	// the error is only for the encodings not defined.
+	ext, _ := (&External{Encoding: encoding, Data: data}).encode(NewIE(NewUniversalPrimitiveTag(6), oid))
+	return ext
+}
+
+// externalWithOID returns the EXTERNAL decoded as an External if its direct-reference is the encoded OID.
+func externalWithOID(ext *IE, oid []byte) (*External, bool) {
+	var want OID
+	if err := want.UnmarshalBinary(oid); err != nil {
+		return nil, false
+	}
+	e, err := ext.External()
+	if err != nil || !e.DirectReference.Equal(want) {
+		return nil, false
+	}
+	return e, true
+}
+
+// userInfoExternals returns the EXTERNALs in the user information of DialoguePDU.
+func userInfoExternals(pdu *DialoguePDU) ([]*IE, error) {
+	if pdu.UserInformation == nil || len(pdu.UserInformation.Value) == 0 {
+		return nil, nil
+	}
+	return ParseMultiIEs(pdu.UserInformation.Value)
+}
+
+// encodeTLV returns the encoding of the IE with the length of its value.
+func encodeTLV(i *IE) []byte {
+	l := MarshalAsn1ElementLength(len(i.Value))
+	b := make([]byte, i.Tag.MarshalLen(), i.Tag.MarshalLen()+len(l)+len(i.Value))
+	_, _ = i.Tag.MarshalTo(b)
+	b = append(b, l...)
+	return append(b, i.Value...)
+}
@@ -0,0 +1,176 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestSingleASN1UserInfo(t *testing.T) {
+	// MAP-OpenInfo with destinationReference only
+	openInfo := []byte{0xa0, 0x06, 0x80, 0x04, 0x91, 0x21, 0x43, 0xf5}
+
+	m := tcap.NewBeginInvokeWithDialogue(0x11111111, tcap.DialogueAsID, tcap.NetworkUnstructuredSsContext, 2, 0, 59, nil)
+	m.Dialogue.DialoguePDU.SetSingleASN1UserInfo(tcap.MAPDialogueAS, openInfo)
+	m.SetLength()
+
+	b, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := tcap.Parse(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verify.Values(t, "user information", parsed.Dialogue.DialoguePDU.UserInformation.Value, []byte{
+		0x28, 0x13, // EXTERNAL
+		0x06, 0x07, 0x04, 0x00, 0x00, 0x01, 0x01, 0x01, 0x01, // direct-reference
+		0xa0, 0x08, // single-ASN1-type
+		0xa0, 0x06, 0x80, 0x04, 0x91, 0x21, 0x43, 0xf5,
+	})
+
+	got, err := parsed.Dialogue.DialoguePDU.SingleASN1UserInfo(tcap.MAPDialogueAS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "extracted", got, openInfo)
+
+	if _, err := parsed.Dialogue.DialoguePDU.SingleASN1UserInfo([]byte{0x2b, 0x06}); !errors.Is(err, tcap.ErrNoExternal) {
+		t.Errorf("other OID: got %v, want %v", err, tcap.ErrNoExternal)
+	}
+}
+
+func TestSingleASN1ExternalAsExternal(t *testing.T) {
+	openInfo := []byte{0xa0, 0x06, 0x80, 0x04, 0x91, 0x21, 0x43, 0xf5}
+	ext := tcap.NewSingleASN1External(tcap.MAPDialogueAS, openInfo)
+
+	e, err := ext.External()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "External", e, &tcap.External{
+		DirectReference: tcap.OID{0, 4, 0, 0, 1, 1, 1, 1},
+		Encoding:        tcap.SingleASN1Type,
+		Data:            openInfo,
+	})
+
+	b, err := e.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := ext.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "encoded", b, want)
+
+	// an EXTERNAL built with External is found by the OID in encoded form.
+	pdu := tcap.NewAARQ(1, tcap.NetworkUnstructuredSsContext, 2)
+	if err := pdu.SetExternals(e); err != nil {
+		t.Fatal(err)
+	}
+	got, err := pdu.SingleASN1UserInfo(tcap.MAPDialogueAS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "extracted", got, openInfo)
+}
+
+func TestExternal(t *testing.T) {
+	ref := int64(3)
+	externals := []*tcap.External{
+		{
+			DirectReference: tcap.OID{0, 4, 0, 0, 1, 1, 1, 1},
+			Encoding:        tcap.SingleASN1Type,
+			Data:            []byte{0xa0, 0x06, 0x80, 0x04, 0x91, 0x21, 0x43, 0xf5},
+		},
+		{
+			IndirectReference:   &ref,
+			DataValueDescriptor: "x",
+			Encoding:            tcap.OctetAligned,
+			Data:                []byte{0x01, 0x02},
+		},
+	}
+
+	m := tcap.NewBeginInvokeWithDialogue(0x11111111, tcap.DialogueAsID, tcap.NetworkUnstructuredSsContext, 2, 0, 59, nil)
+	if err := m.Dialogue.DialoguePDU.SetExternals(externals...); err != nil {
+		t.Fatal(err)
+	}
+	m.SetLength()
+
+	b, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := tcap.Parse(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := parsed.Dialogue.DialoguePDU.Externals()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "externals", got, externals)
+
+	encoded, err := externals[0].MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "encoded", encoded, []byte{
+		0x28, 0x13,
+		0x06, 0x07, 0x04, 0x00, 0x00, 0x01, 0x01, 0x01, 0x01,
+		0xa0, 0x08, 0xa0, 0x06, 0x80, 0x04, 0x91, 0x21, 0x43, 0xf5,
+	})
+
+	// the encoding is missing.
+	if _, err := tcap.ParseExternal([]byte{0x28, 0x03, 0x02, 0x01, 0x03}); !errors.Is(err, tcap.ErrInvalidExternal) {
+		t.Errorf("got %v, want %v", err, tcap.ErrInvalidExternal)
+	}
+}
+
+func TestAppendExternals(t *testing.T) {
+	open := &tcap.External{
+		DirectReference: tcap.OID{0, 4, 0, 0, 1, 1, 1, 1},
+		Encoding:        tcap.SingleASN1Type,
+		Data:            []byte{0xa0, 0x00},
+	}
+	vendor := &tcap.External{
+		DirectReference: tcap.OID{1, 3, 6, 1, 4, 1, 99999},
+		Encoding:        tcap.OctetAligned,
+		Data:            []byte{0xde, 0xad},
+	}
+
+	userinfo, err := tcap.NewUserInformation(open)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pdu := range []*tcap.DialoguePDU{
+		tcap.NewAARQ(1, tcap.ShortMsgMTRelayContext, 3, userinfo),
+		tcap.NewAARE(1, tcap.ShortMsgMTRelayContext, 3, tcap.Accepted, tcap.DialogueServiceUser, tcap.Null, userinfo),
+		tcap.NewABRT(uint8(tcap.AbortDialogueServiceUser), userinfo),
+	} {
+		if err := pdu.AppendExternals(vendor); err != nil {
+			t.Fatal(err)
+		}
+		b, err := pdu.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed, err := tcap.ParseDialoguePDU(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := parsed.Externals()
+		if err != nil {
+			t.Fatal(err)
+		}
+		verify.Values(t, parsed.DialogueType(), got, []*tcap.External{open, vendor})
+	}
+}
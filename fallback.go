@@ -0,0 +1,65 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import "errors"
+
+// ErrNoFallback is returned when the dialogue cannot fall back to a lower version of MAP.
+var ErrNoFallback = errors.New("tcap: no lower application context version to fall back to")
+
+// FallbackBegin rebuilds the pending Begin with the next-lower version of the MAP application context,
+// when the received message rejects the dialogue because the application context is not supported
+// (see IsACNNotSupported), in the procedure of 3GPP TS 29.002 clause 15.
+//
+// The version is the one proposed in the AARE if it is lower than the pending one, or the pending one
+// minus one otherwise. The Begin of version 1 has no Dialogue Portion. The Begin rebuilt is a copy of
+// the pending one with the new Originating Transaction ID, as the rejected transaction is terminated.
+//
+// It returns ErrNoFallback if the received message does not reject the application context, or the
+// pending one is already version 1.
+func FallbackBegin(pending, received *TCAP, otid uint32) (*TCAP, error) {
+	altCtx, altVer, ok := received.IsACNNotSupported()
+	if !ok || pending.Dialogue == nil || pending.Dialogue.DialoguePDU == nil {
+		return nil, ErrNoFallback
+	}
+	ac, err := pending.Dialogue.DialoguePDU.ApplicationContext()
+	if err != nil {
+		return nil, err
+	}
+	ctx, ver, ok := ac.Context()
+	if !ok || ver <= 1 {
+		return nil, ErrNoFallback
+	}
+
+	next := ver - 1
+	if altCtx == ctx && altVer >= 1 && altVer < ver {
+		next = altVer
+	}
+
+	t := pending.Clone()
+	WithOTID(otid)(t)
+	if next == 1 {
+		t.Dialogue = nil
+	} else if err := t.Dialogue.DialoguePDU.SetApplicationContext(ac.WithVersion(next)); err != nil {
+		return nil, err
+	}
+	t.SetLength()
+	return t, nil
+}
+
+// Fallback rebuilds the pending Begin with FallbackBegin and calls resend with it, and returns the
+// one sent, so that it can be kept as the pending one for the next fallback.
+//
+// It returns ErrNoFallback without calling resend if the dialogue cannot fall back.
+func Fallback(pending, received *TCAP, otid uint32, resend func(*TCAP) error) (*TCAP, error) {
+	t, err := FallbackBegin(pending, received, otid)
+	if err != nil {
+		return nil, err
+	}
+	if err := resend(t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
@@ -0,0 +1,63 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestFallback(t *testing.T) {
+	pending := tcap.NewBeginInvokeWithDialogue(0x11111111, tcap.DialogueAsID, tcap.ShortMsgMTRelayContext, 3, 0, 44, []byte{0x30, 0x00})
+
+	var sent []*tcap.TCAP
+	resend := func(m *tcap.TCAP) error {
+		sent = append(sent, m)
+		return nil
+	}
+
+	// the peer proposes version 2.
+	next, err := tcap.Fallback(pending, tcap.NewACNNotSupported(0x11111111, tcap.ShortMsgMTRelayContext, 2, false), 0x22222222, resend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := next.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := tcap.Parse(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "OTID", parsed.OTID(), uint32(0x22222222))
+	verify.Values(t, "context", parsed.AppContextNameWithVersion(), "shortMsgMTRelayContext-v2")
+	verify.Values(t, "OpCode", parsed.OpCode(), []uint8{44})
+	verify.Values(t, "pending untouched", pending.Dialogue.DialoguePDU.ContextVersion(), "3")
+
+	// version 1 has no Dialogue Portion.
+	last, err := tcap.Fallback(next, tcap.NewACNNotSupported(0x22222222, tcap.ShortMsgMTRelayContext, 2, true), 0x33333333, resend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last.Dialogue != nil {
+		t.Errorf("version 1: got Dialogue %v", last.Dialogue)
+	}
+	if _, err := last.MarshalBinary(); err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "sent", len(sent), 2)
+
+	if _, err := tcap.Fallback(last, tcap.NewACNNotSupported(0x33333333, tcap.ShortMsgMTRelayContext, 1, true), 1, resend); !errors.Is(err, tcap.ErrNoFallback) {
+		t.Errorf("version 1: got %v, want %v", err, tcap.ErrNoFallback)
+	}
+	accepted := tcap.NewEndReturnResultWithDialogue(0x11111111, tcap.DialogueAsID, tcap.ShortMsgMTRelayContext, 3, 0, 44, true, nil)
+	if _, err := tcap.FallbackBegin(pending, accepted, 1); !errors.Is(err, tcap.ErrNoFallback) {
+		t.Errorf("accepted: got %v, want %v", err, tcap.ErrNoFallback)
+	}
+	verify.Values(t, "sent", len(sent), 2)
+}
@@ -0,0 +1,332 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+/*
+Package firewall provides a rules engine to filter the MAP messages carried over TCAP,
+based on the categories defined in GSMA FS.11.
+
+The Engine does not send or receive anything by itself. The caller passes the decoded
+TCAP with the origin of the message (typically the Calling Party GT), and sends the
+response built by the Engine if needed.
+*/
+package firewall
+
+import (
+	"github.com/en-vee/go-tcap"
+)
+
+// Category is a MAP message category defined in GSMA FS.11.
+type Category int
+
+// Category definitions.
+const (
+	// CategoryNone is for the operations not classified.
+	CategoryNone Category = iota
+	// Category1 is for the operations that should only be received from the home network.
+	Category1
+	// Category2 is for the operations that should only be received from the home network of the subscriber.
+	Category2
+	// Category3 is for the operations that can be received from other networks, but only when
+	// the origin is plausible for the current location of the subscriber.
+	Category3
+)
+
+// String returns the name of Category.
+func (c Category) String() string {
+	switch c {
+	case Category1:
+		return "Category1"
+	case Category2:
+		return "Category2"
+	case Category3:
+		return "Category3"
+	}
+	return "CategoryNone"
+}
+
+// Action is the action taken for the message that matches a Rule.
+type Action int
+
+// Action definitions.
+const (
+	// Allow lets the message pass.
+	Allow Action = iota
+	// Drop silently discards the message.
+	Drop
+	// ReturnError answers every Invoke in the message with ReturnError.
+	ReturnError
+	// Abort aborts the dialogue.
+	Abort
+)
+
+// String returns the name of Action.
+func (a Action) String() string {
+	switch a {
+	case Allow:
+		return "Allow"
+	case Drop:
+		return "Drop"
+	case ReturnError:
+		return "ReturnError"
+	case Abort:
+		return "Abort"
+	}
+	return ""
+}
+
+// DefaultCategories is the classification of MAP local Operation Codes derived from GSMA FS.11.
+//
+// Operators often need to adjust it to their own policy, which can be done by setting
+// Engine.Categories instead of modifying this.
+var DefaultCategories = map[uint8]Category{
+	// Category 1
+	10: Category1, // registerSS
+	11: Category1, // eraseSS
+	12: Category1, // activateSS
+	13: Category1, // deactivateSS
+	14: Category1, // interrogateSS
+	17: Category1, // registerPassword
+	22: Category1, // sendRoutingInfo
+	24: Category1, // sendRoutingInfoForGprs
+	58: Category1, // sendIMSI
+	62: Category1, // anyTimeSubscriptionInterrogation
+	65: Category1, // anyTimeModification
+	71: Category1, // anyTimeInterrogation
+	85: Category1, // sendRoutingInfoForLCS
+
+	// Category 2
+	3:  Category2, // cancelLocation
+	4:  Category2, // provideRoamingNumber
+	7:  Category2, // insertSubscriberData
+	8:  Category2, // deleteSubscriberData
+	37: Category2, // reset
+	45: Category2, // sendRoutingInfoForSM
+	60: Category2, // unstructuredSS-Request
+	61: Category2, // unstructuredSS-Notify
+	70: Category2, // provideSubscriberInfo
+	83: Category2, // provideSubscriberLocation
+
+	// Category 3
+	2:  Category3, // updateLocation
+	23: Category3, // updateGprsLocation
+	55: Category3, // sendIdentification
+	56: Category3, // sendAuthenticationInfo
+	67: Category3, // purgeMS
+}
+
+// Rule is a condition and the Action to be taken when the message matches it.
+//
+// All the conditions that are set must be satisfied for the Rule to match.
+// The conditions left empty are not evaluated.
+type Rule struct {
+	// Name is used to identify the Rule matched.
+	Name string
+
+	// Categories matches if any of the Invokes in the message is in one of the Categories.
+	Categories []Category
+
+	// OpCodes matches if any of the Invokes in the message has one of the Operation Codes.
+	OpCodes []uint8
+
+	// ACNs matches if the Application Context Name is a MAP one of the contexts in any version
+	// (e.g., tcap.AnyTimeInfoEnquiryContext). CAP and other non-MAP contexts never match.
+	ACNs []uint8
+
+	// Foreign matches only if the origin is not in the home network, determined by Engine.IsHome.
+	Foreign bool
+
+	// Origin matches if it returns true for the origin of the message.
+	Origin func(origin string) bool
+
+	// Action is taken when the message matches the Rule.
+	Action Action
+
+	// ErrorCode is the MAP Error Code used in ReturnError Action (e.g., 34 for systemFailure).
+	ErrorCode uint8
+}
+
+// Verdict is the result of the evaluation by Engine.
+type Verdict struct {
+	// Rule is the Rule matched, or nil if no Rule matched.
+	Rule *Rule
+
+	// Category is the highest Category of the Invokes in the message.
+	Category Category
+
+	// Action is the Action to be taken.
+	Action Action
+}
+
+// Engine evaluates the Rules in order and returns the Verdict for the first Rule matched.
+type Engine struct {
+	// Rules are evaluated in order.
+	Rules []*Rule
+
+	// Categories overrides DefaultCategories if not nil.
+	Categories map[uint8]Category
+
+	// IsHome reports whether the origin is in the home network.
+	// If nil, every origin is treated as foreign.
+	IsHome func(origin string) bool
+
+	// Default is the Action taken when no Rule matches.
+	Default Action
+}
+
+// Classify returns the highest Category of the Invokes in the TCAP.
+func (e *Engine) Classify(t *tcap.TCAP) Category {
+	cat := CategoryNone
+	for _, op := range invokedOpCodes(t) {
+		if c := e.category(op); c > cat {
+			cat = c
+		}
+	}
+	return cat
+}
+
+func (e *Engine) category(op uint8) Category {
+	if e.Categories != nil {
+		return e.Categories[op]
+	}
+	return DefaultCategories[op]
+}
+
+// Evaluate evaluates the Rules for the TCAP received from the origin.
+func (e *Engine) Evaluate(t *tcap.TCAP, origin string) *Verdict {
+	v := &Verdict{
+		Category: e.Classify(t),
+		Action:   e.Default,
+	}
+
+	for _, r := range e.Rules {
+		if e.matches(r, t, origin) {
+			v.Rule = r
+			v.Action = r.Action
+			break
+		}
+	}
+	return v
+}
+
+func (e *Engine) matches(r *Rule, t *tcap.TCAP, origin string) bool {
+	ops := invokedOpCodes(t)
+
+	if len(r.Categories) > 0 {
+		found := false
+		for _, op := range ops {
+			if contains(r.Categories, e.category(op)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(r.OpCodes) > 0 {
+		found := false
+		for _, op := range ops {
+			if contains(r.OpCodes, op) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(r.ACNs) > 0 {
+		d := t.Dialogue
+		if d == nil || d.DialoguePDU == nil {
+			return false
+		}
+		ac, err := d.DialoguePDU.ApplicationContext()
+		if err != nil {
+			return false
+		}
+		ctx, _, ok := ac.Context()
+		if !ok || !contains(r.ACNs, ctx) {
+			return false
+		}
+	}
+
+	if r.Foreign && e.IsHome != nil && e.IsHome(origin) {
+		return false
+	}
+
+	if r.Origin != nil && !r.Origin(origin) {
+		return false
+	}
+
+	return true
+}
+
+// Response builds the TCAP to be sent back for the request according to the Verdict.
+//
+// It returns nil for Allow and Drop, End with ReturnError for each Invoke for ReturnError,
+// and Abort with ABRT from the dialogue service user for Abort. The End accepts the dialogue
+// with AARE if the request has AARQ.
+func (v *Verdict) Response(req *tcap.TCAP) *tcap.TCAP {
+	dtid := req.OTID()
+	switch v.Action {
+	case ReturnError:
+		var errCode uint8
+		if v.Rule != nil {
+			errCode = v.Rule.ErrorCode
+		}
+
+		var comps []*tcap.Component
+		if c := req.Components; c != nil {
+			for _, comp := range c.Component {
+				if comp.Type.Code() == tcap.Invoke {
+					comps = append(comps, tcap.NewReturnError(int(comp.InvID()), int(errCode), true, nil))
+				}
+			}
+		}
+
+		t := &tcap.TCAP{
+			Transaction: tcap.NewEnd(dtid, []byte{}),
+			Dialogue:    tcap.AcceptDialogue(req),
+			Components:  tcap.NewComponents(comps...),
+		}
+		t.SetLength()
+		return t
+	case Abort:
+		return tcap.NewUserAbort(dtid)
+	}
+	return nil
+}
+
+// invokedOpCodes returns the local Operation Codes of the Invokes in the TCAP.
+// Global ones and the ones out of the range of MAP are not classified.
+func invokedOpCodes(t *tcap.TCAP) []uint8 {
+	c := t.Components
+	if c == nil {
+		return nil
+	}
+
+	var ops []uint8
+	for _, comp := range c.Component {
+		if comp.Type.Code() != tcap.Invoke {
+			continue
+		}
+		code, err := comp.Operation()
+		if err != nil || code.IsGlobal() || code.Local < 0 || code.Local > 0xff {
+			continue
+		}
+		ops = append(ops, uint8(code.Local))
+	}
+	return ops
+}
+
+func contains[T comparable](s []T, v T) bool {
+	for _, x := range s {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
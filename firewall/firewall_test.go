@@ -0,0 +1,147 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package firewall_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/en-vee/go-tcap/firewall"
+)
+
+func TestEngine(t *testing.T) {
+	e := &firewall.Engine{
+		Rules: []*firewall.Rule{
+			{
+				Name:       "cat1-foreign",
+				Categories: []firewall.Category{firewall.Category1},
+				Foreign:    true,
+				Action:     firewall.ReturnError,
+				ErrorCode:  21, // facilityNotSupported
+			},
+			{
+				Name: "cl-foreign",
+				ACNs: []uint8{tcap.LocationCancellationContext, uint8(tcap.CAPv2GsmSSFToGsmSCF[6])}, // CAP v2 must not match
+
+				Foreign: true,
+				Action:  firewall.Abort,
+			},
+		},
+		IsHome: func(origin string) bool {
+			return strings.HasPrefix(origin, "8190")
+		},
+		Default: firewall.Allow,
+	}
+
+	ati := tcap.NewBeginInvokeWithDialogue(0x11111111, tcap.DialogueAsID, tcap.AnyTimeInfoEnquiryContext, 3, 0, 71, nil)
+	cl := tcap.NewBeginInvokeWithDialogue(0x22222222, tcap.DialogueAsID, tcap.LocationCancellationContext, 3, 0, 3, nil)
+	srism := tcap.NewBeginInvokeWithDialogue(0x33333333, tcap.DialogueAsID, tcap.ShortMsgGatewayContext, 3, 0, 45, nil)
+
+	// The last arc of the OID is at the same offset as the MAP context, but it is not a MAP one.
+	short := tcap.NewBeginInvokeWithDialogue(0x44444444, tcap.DialogueAsID, tcap.LocationCancellationContext, 3, 0, 99, nil)
+	if err := short.Dialogue.DialoguePDU.SetApplicationContext(tcap.ApplicationContext{0, 4, 0, 0, 1, 0, uint64(tcap.LocationCancellationContext)}); err != nil {
+		t.Fatal(err)
+	}
+	short.SetLength()
+
+	// CAP shares the MAP prefix, and its context must not be taken as a MAP one.
+	capv2 := tcap.NewBeginInvokeWithDialogue(0x55555555, tcap.DialogueAsID, tcap.LocationCancellationContext, 3, 0, 99, nil)
+	if err := capv2.Dialogue.DialoguePDU.SetApplicationContext(tcap.CAPv2GsmSSFToGsmSCF); err != nil {
+		t.Fatal(err)
+	}
+	capv2.SetLength()
+
+	// The first octet of the OID is the same as anyTimeInterrogation, but it is not a local one.
+	global, err := tcap.NewInvokeWithCode(0, -1, tcap.GlobalOperationCode(tcap.OID{1, 31}), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	globalATI := tcap.NewBeginMessage(tcap.WithOTID(0x66666666), tcap.WithComponents(global))
+
+	cases := []struct {
+		description string
+		msg         *tcap.TCAP
+		origin      string
+		category    firewall.Category
+		action      firewall.Action
+	}{
+		{"ATI from home", ati, "81901234", firewall.Category1, firewall.Allow},
+		{"ATI from foreign", ati, "4477001234", firewall.Category1, firewall.ReturnError},
+		{"CL from foreign", cl, "4477001234", firewall.Category2, firewall.Abort},
+		{"SRI-SM from foreign", srism, "4477001234", firewall.Category2, firewall.Allow},
+		{"Short ACN from foreign", short, "4477001234", firewall.CategoryNone, firewall.Allow},
+		{"CAP from foreign", capv2, "4477001234", firewall.CategoryNone, firewall.Allow},
+		{"Global opcode from foreign", globalATI, "4477001234", firewall.CategoryNone, firewall.Allow},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			v := e.Evaluate(c.msg, c.origin)
+			if v.Category != c.category {
+				t.Errorf("category: got %s, want %s", v.Category, c.category)
+			}
+			if v.Action != c.action {
+				t.Errorf("action: got %s, want %s", v.Action, c.action)
+			}
+
+			resp := v.Response(c.msg)
+			switch c.action {
+			case firewall.Allow, firewall.Drop:
+				if resp != nil {
+					t.Errorf("response: got %v, want nil", resp)
+				}
+				return
+			}
+
+			b, err := resp.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			parsed, err := tcap.Parse(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := parsed.DTID(), c.msg.OTID(); got != want {
+				t.Errorf("DTID: got %x, want %x", got, want)
+			}
+
+			switch c.action {
+			case firewall.ReturnError:
+				if got, want := parsed.ComponentType()[0], "returnError"; got != want {
+					t.Errorf("component: got %s, want %s", got, want)
+				}
+				if c.msg.Dialogue == nil {
+					break
+				}
+				if parsed.Dialogue == nil || parsed.Dialogue.DialoguePDU == nil {
+					t.Fatal("dialogue: got none, want AARE")
+				}
+				pdu := parsed.Dialogue.DialoguePDU
+				if got, want := pdu.DialogueType(), "AARE"; got != want {
+					t.Errorf("dialogue: got %s, want %s", got, want)
+				}
+				if r, err := pdu.AssociateResult(); err != nil || r != tcap.AssociateResult(tcap.Accepted) {
+					t.Errorf("result: got %v, %v", r, err)
+				}
+				got, err := pdu.ApplicationContext()
+				if err != nil {
+					t.Fatal(err)
+				}
+				want, err := c.msg.Dialogue.DialoguePDU.ApplicationContext()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !got.Equal(want) {
+					t.Errorf("ACN: got %s, want %s", got, want)
+				}
+			case firewall.Abort:
+				if got, want := parsed.Dialogue.DialoguePDU.DialogueType(), "ABRT"; got != want {
+					t.Errorf("dialogue: got %s, want %s", got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package firewall
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Violation is the result of plausibility check by Tracker.
+type Violation int
+
+// Violation definitions.
+const (
+	// ViolationNone means the message is plausible.
+	ViolationNone Violation = iota
+	// ViolationVelocity means the subscriber moved to another network faster than possible.
+	ViolationVelocity
+	// ViolationLocation means the request came from a network the subscriber is not located in.
+	ViolationLocation
+)
+
+// String returns the name of Violation.
+func (v Violation) String() string {
+	switch v {
+	case ViolationVelocity:
+		return "ViolationVelocity"
+	case ViolationLocation:
+		return "ViolationLocation"
+	}
+	return "ViolationNone"
+}
+
+// MAP Operation Codes Tracker looks at.
+const (
+	opUpdateLocation         uint8 = 2
+	opSendRoutingInfo        uint8 = 22
+	opUpdateGprsLocation     uint8 = 23
+	opSendRoutingInfoForGprs uint8 = 24
+	opProvideSubscriberInfo  uint8 = 70
+	opAnyTimeInterrogation   uint8 = 71
+)
+
+// Tracker correlates the origins of UpdateLocation with the subsequent location-sensitive
+// requests (ATI, PSI and SRI) for the same subscriber, and reports the implausible ones.
+//
+// The subscriber (typically IMSI) must be given by the caller, as this package does not
+// decode MAP parameters. It is safe for concurrent use.
+//
+// The locations expired are evicted, so are the least recently updated ones beyond MaxSubscribers,
+// so that a flood of spoofed subscribers does not pile up.
+type Tracker struct {
+	mu          sync.Mutex
+	ttl         time.Duration
+	subscribers map[string]*location
+
+	// order holds the subscribers from the least recently updated.
+	order *list.List
+
+	// MaxSubscribers is the maximum number of the subscribers tracked. Zero means no limit.
+	MaxSubscribers int
+
+	// Network returns the network the origin belongs to, e.g., by the CC+NDC prefix of the GT.
+	// If nil, the origin itself is treated as the network.
+	Network func(origin string) string
+
+	// MinTravelTime returns the minimum time needed for a subscriber to move between the networks.
+	// If nil, any change of network is considered plausible.
+	MinTravelTime func(from, to string) time.Duration
+
+	// IsHome reports whether the origin is in the home network.
+	// The requests from the home network are always plausible.
+	IsHome func(origin string) bool
+}
+
+type location struct {
+	network string
+	updated time.Time
+	elem    *list.Element
+}
+
+// NewTracker creates a new Tracker that keeps the location of each subscriber for ttl.
+func NewTracker(ttl time.Duration) *Tracker {
+	return &Tracker{
+		ttl:         ttl,
+		subscribers: map[string]*location{},
+		order:       list.New(),
+	}
+}
+
+// Observe checks the message with opCode for the subscriber received from the origin.
+func (t *Tracker) Observe(subscriber, origin string, opCode uint8) Violation {
+	return t.ObserveAt(subscriber, origin, opCode, time.Now())
+}
+
+// ObserveAt is the same as Observe, but with the time the message is received.
+func (t *Tracker) ObserveAt(subscriber, origin string, opCode uint8, now time.Time) Violation {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	network := origin
+	if t.Network != nil {
+		network = t.Network(origin)
+	}
+
+	t.expire(now)
+	loc, ok := t.subscribers[subscriber]
+	if ok && now.Sub(loc.updated) > t.ttl {
+		t.remove(subscriber)
+		loc, ok = nil, false
+	}
+
+	switch opCode {
+	case opUpdateLocation, opUpdateGprsLocation:
+		v := ViolationNone
+		if ok && loc.network != network && t.MinTravelTime != nil {
+			if now.Sub(loc.updated) < t.MinTravelTime(loc.network, network) {
+				v = ViolationVelocity
+			}
+		}
+		t.update(subscriber, network, now)
+		return v
+	case opSendRoutingInfo, opSendRoutingInfoForGprs, opProvideSubscriberInfo, opAnyTimeInterrogation:
+		if t.IsHome != nil && t.IsHome(origin) {
+			return ViolationNone
+		}
+		if ok && loc.network != network {
+			return ViolationLocation
+		}
+	}
+	return ViolationNone
+}
+
+// Forget removes the location of the subscriber.
+func (t *Tracker) Forget(subscriber string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.remove(subscriber)
+}
+
+// Len returns the number of the subscribers tracked.
+func (t *Tracker) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return len(t.subscribers)
+}
+
+// update sets the location of the subscriber as the most recently updated one, and evicts
+// the least recently updated ones beyond MaxSubscribers.
+func (t *Tracker) update(subscriber, network string, now time.Time) {
+	if loc, ok := t.subscribers[subscriber]; ok {
+		loc.network, loc.updated = network, now
+		t.order.MoveToBack(loc.elem)
+	} else {
+		t.subscribers[subscriber] = &location{network: network, updated: now, elem: t.order.PushBack(subscriber)}
+	}
+
+	for t.MaxSubscribers > 0 && len(t.subscribers) > t.MaxSubscribers {
+		t.remove(t.order.Front().Value.(string))
+	}
+}
+
+// expire evicts the locations updated more than ttl ago, from the least recently updated.
+func (t *Tracker) expire(now time.Time) {
+	for e := t.order.Front(); e != nil; e = t.order.Front() {
+		subscriber := e.Value.(string)
+		if now.Sub(t.subscribers[subscriber].updated) <= t.ttl {
+			return
+		}
+		t.remove(subscriber)
+	}
+}
+
+// remove removes the location of the subscriber, if any.
+func (t *Tracker) remove(subscriber string) {
+	if loc, ok := t.subscribers[subscriber]; ok {
+		t.order.Remove(loc.elem)
+		delete(t.subscribers, subscriber)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package firewall_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/en-vee/go-tcap/firewall"
+)
+
+func TestTracker(t *testing.T) {
+	tr := firewall.NewTracker(24 * time.Hour)
+	tr.Network = func(origin string) string {
+		return origin[:4]
+	}
+	tr.MinTravelTime = func(from, to string) time.Duration {
+		return time.Hour
+	}
+	tr.IsHome = func(origin string) bool {
+		return strings.HasPrefix(origin, "8190")
+	}
+
+	const imsi = "440101234567890"
+	now := time.Unix(0, 0)
+
+	cases := []struct {
+		description string
+		origin      string
+		opCode      uint8
+		after       time.Duration
+		want        firewall.Violation
+	}{
+		{"first UL", "44770001", 2, 0, firewall.ViolationNone},
+		{"ATI from serving network", "44770002", 71, time.Minute, firewall.ViolationNone},
+		{"PSI from other network", "33610001", 70, time.Minute, firewall.ViolationLocation},
+		{"SRI from home", "81900001", 22, time.Minute, firewall.ViolationNone},
+		{"UL too fast", "33610001", 2, 10 * time.Minute, firewall.ViolationVelocity},
+		{"UL after enough time", "44770001", 23, 2 * time.Hour, firewall.ViolationNone},
+		{"expired", "12120001", 71, 48 * time.Hour, firewall.ViolationNone},
+	}
+
+	for _, c := range cases {
+		now = now.Add(c.after)
+		if got := tr.ObserveAt(imsi, c.origin, c.opCode, now); got != c.want {
+			t.Errorf("%s: got %s, want %s", c.description, got, c.want)
+		}
+	}
+}
+
+func TestTrackerEviction(t *testing.T) {
+	tr := firewall.NewTracker(time.Hour)
+	tr.MaxSubscribers = 10
+	now := time.Unix(0, 0)
+
+	// a flood of spoofed subscribers
+	for i := 0; i < 100; i++ {
+		tr.ObserveAt(fmt.Sprintf("4401012345%05d", i), "44770001", 2, now)
+	}
+	if got, want := tr.Len(), 10; got != want {
+		t.Errorf("capped: got %d subscribers, want %d", got, want)
+	}
+	// the most recent ones are kept
+	if got := tr.ObserveAt("440101234500099", "33610001", 71, now); got != firewall.ViolationLocation {
+		t.Errorf("recent: got %s, want %s", got, firewall.ViolationLocation)
+	}
+
+	tr.ObserveAt("440109999999999", "44770001", 2, now.Add(30*time.Minute))
+	tr.ObserveAt("440109999999999", "44770001", 71, now.Add(90*time.Minute))
+	if got, want := tr.Len(), 1; got != want {
+		t.Errorf("expired: got %d subscribers, want %d", got, want)
+	}
+}
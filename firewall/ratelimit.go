@@ -0,0 +1,173 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package firewall
+
+import (
+	"sync"
+	"time"
+
+	"github.com/en-vee/go-tcap"
+)
+
+// RateLimiter limits the rate of messages per origin with token buckets.
+//
+// The origin is any string that identifies the peer, typically the Calling Party GT
+// or the Point Code formatted in decimal. It is safe for concurrent use.
+//
+// The buckets refilled up to the burst are evicted, as they are the same as the new ones,
+// so that the spoofed origins do not pile up. Their counters are kept only in Total.
+type RateLimiter struct {
+	mu      sync.Mutex
+	rate    float64
+	burst   float64
+	buckets map[string]*bucket
+
+	// evicted is the counters of the buckets evicted, and swept is the time of the last sweep.
+	evicted Stats
+	swept   time.Time
+
+	// Overflow is the Action taken for the messages exceeding the rate, Drop or Abort.
+	Overflow Action
+}
+
+type bucket struct {
+	tokens  float64
+	last    time.Time
+	allowed uint64
+	limited uint64
+}
+
+// Stats is the counters of a RateLimiter.
+type Stats struct {
+	Allowed uint64
+	Limited uint64
+}
+
+// NewRateLimiter creates a new RateLimiter that allows rate messages per second
+// from each origin, with bursts up to burst messages.
+func NewRateLimiter(rate float64, burst int, overflow Action) *RateLimiter {
+	return &RateLimiter{
+		rate:     rate,
+		burst:    float64(burst),
+		buckets:  map[string]*bucket{},
+		Overflow: overflow,
+	}
+}
+
+// Check takes a token for the origin and returns the Action to be taken for the message.
+func (r *RateLimiter) Check(origin string) Action {
+	return r.CheckAt(origin, time.Now())
+}
+
+// CheckAt is the same as Check, but with the time the message is received.
+func (r *RateLimiter) CheckAt(origin string, now time.Time) Action {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sweep(now)
+
+	b, ok := r.buckets[origin]
+	if !ok {
+		b = &bucket{tokens: r.burst, last: now}
+		r.buckets[origin] = b
+	}
+
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens += elapsed * r.rate
+		if b.tokens > r.burst {
+			b.tokens = r.burst
+		}
+		b.last = now
+	}
+
+	if b.tokens < 1 {
+		b.limited++
+		return r.Overflow
+	}
+	b.tokens--
+	b.allowed++
+	return Allow
+}
+
+// fillTime returns the time a bucket takes to be refilled from empty to the burst.
+func (r *RateLimiter) fillTime() time.Duration {
+	if r.rate <= 0 {
+		return 0
+	}
+	return time.Duration(r.burst / r.rate * float64(time.Second))
+}
+
+// sweep evicts the buckets that have been refilled up to the burst, once in the fill time.
+func (r *RateLimiter) sweep(now time.Time) {
+	fill := r.fillTime()
+	if fill <= 0 || now.Sub(r.swept) < fill {
+		return
+	}
+	r.swept = now
+
+	for origin, b := range r.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*r.rate >= r.burst {
+			r.evicted.Allowed += b.allowed
+			r.evicted.Limited += b.limited
+			delete(r.buckets, origin)
+		}
+	}
+}
+
+// Len returns the number of the origins tracked.
+func (r *RateLimiter) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.buckets)
+}
+
+// Stats returns the counters for the origin, which are reset when the bucket is evicted.
+func (r *RateLimiter) Stats(origin string) Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b, ok := r.buckets[origin]
+	if !ok {
+		return Stats{}
+	}
+	return Stats{Allowed: b.allowed, Limited: b.limited}
+}
+
+// Total returns the counters summed up for all the origins.
+func (r *RateLimiter) Total() Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s := r.evicted
+	for _, b := range r.buckets {
+		s.Allowed += b.allowed
+		s.Limited += b.limited
+	}
+	return s
+}
+
+// Reset removes the bucket and counters of the origin.
+func (r *RateLimiter) Reset(origin string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.buckets, origin)
+}
+
+// Response builds the TCAP to be sent back for the request rejected by RateLimiter.
+//
+// It returns Abort with P-Abort Cause resourceLimitation if Overflow is Abort, otherwise nil.
+func (r *RateLimiter) Response(req *tcap.TCAP) *tcap.TCAP {
+	if r.Overflow != Abort {
+		return nil
+	}
+
+	t := &tcap.TCAP{
+		Transaction: tcap.NewAbort(req.OTID(), tcap.ResourceLimitation, []byte{}),
+	}
+	t.SetLength()
+	return t
+}
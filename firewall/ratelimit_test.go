@@ -0,0 +1,85 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package firewall_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/en-vee/go-tcap/firewall"
+)
+
+func TestRateLimiter(t *testing.T) {
+	r := firewall.NewRateLimiter(2, 2, firewall.Abort)
+	now := time.Unix(0, 0)
+
+	want := []firewall.Action{firewall.Allow, firewall.Allow, firewall.Abort}
+	for i, w := range want {
+		if got := r.CheckAt("4477001234", now); got != w {
+			t.Errorf("#%d: got %s, want %s", i, got, w)
+		}
+	}
+
+	// other origins have their own bucket
+	if got := r.CheckAt("81901234", now); got != firewall.Allow {
+		t.Errorf("other origin: got %s, want Allow", got)
+	}
+
+	// a token is refilled in 500ms
+	if got := r.CheckAt("4477001234", now.Add(500*time.Millisecond)); got != firewall.Allow {
+		t.Errorf("refilled: got %s, want Allow", got)
+	}
+
+	if got, want := r.Stats("4477001234"), (firewall.Stats{Allowed: 3, Limited: 1}); got != want {
+		t.Errorf("stats: got %+v, want %+v", got, want)
+	}
+	if got, want := r.Total(), (firewall.Stats{Allowed: 4, Limited: 1}); got != want {
+		t.Errorf("total: got %+v, want %+v", got, want)
+	}
+
+	req := tcap.NewBeginInvoke(0x11111111, 0, 71, nil)
+	b, err := r.Response(req).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := tcap.Parse(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := resp.Transaction.AbortCause(), "ResourceLimitation"; got != want {
+		t.Errorf("cause: got %s, want %s", got, want)
+	}
+	if got, want := resp.DTID(), uint32(0x11111111); got != want {
+		t.Errorf("DTID: got %x, want %x", got, want)
+	}
+}
+
+func TestRateLimiterEviction(t *testing.T) {
+	r := firewall.NewRateLimiter(2, 2, firewall.Drop)
+	now := time.Unix(0, 0)
+
+	// a flood of spoofed origins
+	for i := 0; i < 100; i++ {
+		r.CheckAt(fmt.Sprintf("44770%05d", i), now)
+	}
+	r.CheckAt("81901234", now)
+	r.CheckAt("81901234", now)
+	if got, want := r.Len(), 101; got != want {
+		t.Fatalf("before: got %d origins, want %d", got, want)
+	}
+
+	// the buckets are refilled in 1s, but the one still limited is kept.
+	r.CheckAt("81901234", now.Add(900*time.Millisecond))
+	r.CheckAt("81901234", now.Add(900*time.Millisecond))
+	r.CheckAt("4477099999", now.Add(1200*time.Millisecond))
+	if got, want := r.Len(), 2; got != want {
+		t.Errorf("after: got %d origins, want %d", got, want)
+	}
+	if got, want := r.Total(), (firewall.Stats{Allowed: 104, Limited: 1}); got != want {
+		t.Errorf("total: got %+v, want %+v", got, want)
+	}
+}
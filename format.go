@@ -0,0 +1,280 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// formattable is implemented by the types that support fmt.Formatter.
+type formattable interface {
+	MarshalBinary() ([]byte, error)
+	String() string
+	summary() string
+	writeTree(w io.Writer, depth int)
+}
+
+// format implements fmt.Formatter for the types in this package.
+//
+// The verbs are handled as follows:
+//
+//	%v   concise summary
+//	%+v  full decoded tree, one element per line
+//	%s   same as String()
+//	%x   raw bytes in hex (%X, %#x and other flags are honored)
+func format(f fmt.State, verb rune, x formattable) {
+	switch verb {
+	case 'v':
+		if f.Flag('+') {
+			var b strings.Builder
+			x.writeTree(&b, 0)
+			_, _ = io.WriteString(f, strings.TrimSuffix(b.String(), "\n"))
+			return
+		}
+		_, _ = io.WriteString(f, x.summary())
+	case 's':
+		_, _ = io.WriteString(f, x.String())
+	case 'x', 'X':
+		b, err := x.MarshalBinary()
+		if err != nil {
+			fmt.Fprintf(f, "%%!%c(%T=%v)", verb, x, err)
+			return
+		}
+		fmt.Fprintf(f, fmt.FormatString(f, verb), b)
+	default:
+		fmt.Fprintf(f, "%%!%c(%T)", verb, x)
+	}
+}
+
+// writeLine writes a single line of tree with indentation given as depth.
+func writeLine(w io.Writer, depth int, format string, v ...interface{}) {
+	fmt.Fprintf(w, "%s"+format+"\n", append([]interface{}{strings.Repeat("  ", depth)}, v...)...)
+}
+
+// writeFieldTree writes IE as a named field of its parent in the tree.
+func writeFieldTree(w io.Writer, depth int, name string, i *IE) {
+	if i == nil {
+		return
+	}
+	writeLine(w, depth, "%s:", name)
+	i.writeTree(w, depth+1)
+}
+
+// Format implements fmt.Formatter.
+//
+// %v prints a concise summary, %+v prints the full decoded tree and %x prints the raw bytes in hex.
+func (i *IE) Format(f fmt.State, verb rune) {
+	format(f, verb, i)
+}
+
+func (i *IE) summary() string {
+	if len(i.IE) == 0 {
+		return fmt.Sprintf("%s(%d):%x", tagString(i.Tag), i.Length, i.Value)
+	}
+
+	s := make([]string, len(i.IE))
+	for n, ie := range i.IE {
+		s[n] = ie.summary()
+	}
+	return fmt.Sprintf("%s(%d){%s}", tagString(i.Tag), i.Length, strings.Join(s, " "))
+}
+
+// tagString returns the Tag in hex as it is on the wire, followed by the tag number if it is
+// in high-tag-number form, e.g., "0x9f2d[45]".
+func tagString(t Tag) string {
+	if !t.isHigh() {
+		return fmt.Sprintf("%#x", uint8(t))
+	}
+	b := make([]byte, t.MarshalLen())
+	_, _ = t.MarshalTo(b)
+	return fmt.Sprintf("%#x[%d]", b, t.Code())
+}
+
+func (i *IE) writeTree(w io.Writer, depth int) {
+	if len(i.IE) == 0 {
+		writeLine(w, depth, "Tag: %s, Length: %d, Value: %x", tagString(i.Tag), i.Length, i.Value)
+		return
+	}
+
+	writeLine(w, depth, "Tag: %s, Length: %d", tagString(i.Tag), i.Length)
+	for _, ie := range i.IE {
+		ie.writeTree(w, depth+1)
+	}
+}
+
+// Format implements fmt.Formatter.
+//
+// %v prints a concise summary, %+v prints the full decoded tree and %x prints the raw bytes in hex.
+func (t *TCAP) Format(f fmt.State, verb rune) {
+	format(f, verb, t)
+}
+
+func (t *TCAP) summary() string {
+	var s []string
+	if p := t.Transaction; p != nil {
+		s = append(s, p.summary())
+	}
+	if p := t.Dialogue; p != nil {
+		s = append(s, p.summary())
+	}
+	if p := t.Components; p != nil {
+		s = append(s, p.summary())
+	}
+	return strings.Join(s, " ")
+}
+
+func (t *TCAP) writeTree(w io.Writer, depth int) {
+	writeLine(w, depth, "TCAP:")
+	if p := t.Transaction; p != nil {
+		p.writeTree(w, depth+1)
+	}
+	if p := t.Dialogue; p != nil {
+		p.writeTree(w, depth+1)
+	}
+	if p := t.Components; p != nil {
+		p.writeTree(w, depth+1)
+	}
+}
+
+// Format implements fmt.Formatter.
+//
+// %v prints a concise summary, %+v prints the full decoded tree and %x prints the raw bytes in hex.
+func (t *Transaction) Format(f fmt.State, verb rune) {
+	format(f, verb, t)
+}
+
+func (t *Transaction) summary() string {
+	var s []string
+	if otid := t.OTID(); otid != "" {
+		s = append(s, "OTID: "+otid)
+	}
+	if dtid := t.DTID(); dtid != "" {
+		s = append(s, "DTID: "+dtid)
+	}
+	if cause := t.AbortCause(); cause != "" {
+		s = append(s, "PAbortCause: "+cause)
+	}
+	return fmt.Sprintf("%s{%s}", t.MessageTypeString(), strings.Join(s, ", "))
+}
+
+func (t *Transaction) writeTree(w io.Writer, depth int) {
+	writeLine(w, depth, "Transaction: %s, Type: %#x, Length: %d", t.MessageTypeString(), t.Type, t.Length)
+	writeFieldTree(w, depth+1, "OrigTransactionID", t.OrigTransactionID)
+	writeFieldTree(w, depth+1, "DestTransactionID", t.DestTransactionID)
+	writeFieldTree(w, depth+1, "PAbortCause", t.PAbortCause)
+}
+
+// Format implements fmt.Formatter.
+//
+// %v prints a concise summary, %+v prints the full decoded tree and %x prints the raw bytes in hex.
+func (d *Dialogue) Format(f fmt.State, verb rune) {
+	format(f, verb, d)
+}
+
+func (d *Dialogue) summary() string {
+	if d.DialoguePDU == nil {
+		return "Dialogue{}"
+	}
+	if d.IsUnidialogue() {
+		return fmt.Sprintf("Dialogue{%s}", d.DialoguePDU.summaryAs("AUDT"))
+	}
+	return fmt.Sprintf("Dialogue{%s}", d.DialoguePDU.summary())
+}
+
+func (d *Dialogue) writeTree(w io.Writer, depth int) {
+	writeLine(w, depth, "Dialogue: Tag: %#x, Length: %d, ExternalTag: %#x, ExternalLength: %d", d.Tag, d.Length, d.ExternalTag, d.ExternalLength)
+	writeFieldTree(w, depth+1, "ObjectIdentifier", d.ObjectIdentifier)
+	if pdu := d.DialoguePDU; pdu != nil {
+		pdu.writeTree(w, depth+1)
+	}
+}
+
+// Format implements fmt.Formatter.
+//
+// %v prints a concise summary, %+v prints the full decoded tree and %x prints the raw bytes in hex.
+func (d *DialoguePDU) Format(f fmt.State, verb rune) {
+	format(f, verb, d)
+}
+
+func (d *DialoguePDU) summary() string {
+	return d.summaryAs(d.DialogueType())
+}
+
+// summaryAs returns the summary with the name of Dialogue Type given, e.g., AUDT for AARQ in
+// Unidialogue-As-Id.
+func (d *DialoguePDU) summaryAs(typ string) string {
+	if ctx := d.Context(); ctx != "" {
+		return fmt.Sprintf("%s(%s-v%s)", typ, ctx, d.ContextVersion())
+	}
+	return typ
+}
+
+func (d *DialoguePDU) writeTree(w io.Writer, depth int) {
+	writeLine(w, depth, "DialoguePDU: %s, Type: %#x, Length: %d", d.DialogueType(), d.Type, d.Length)
+	writeFieldTree(w, depth+1, "ProtocolVersion", d.ProtocolVersion)
+	writeFieldTree(w, depth+1, "ApplicationContextName", d.ApplicationContextName)
+	writeFieldTree(w, depth+1, "Result", d.Result)
+	writeFieldTree(w, depth+1, "ResultSourceDiagnostic", d.ResultSourceDiagnostic)
+	writeFieldTree(w, depth+1, "AbortSource", d.AbortSource)
+	writeFieldTree(w, depth+1, "UserInformation", d.UserInformation)
+}
+
+// Format implements fmt.Formatter.
+//
+// %v prints a concise summary, %+v prints the full decoded tree and %x prints the raw bytes in hex.
+func (c *Components) Format(f fmt.State, verb rune) {
+	format(f, verb, c)
+}
+
+func (c *Components) summary() string {
+	s := make([]string, len(c.Component))
+	for i, comp := range c.Component {
+		s[i] = comp.summary()
+	}
+	return "[" + strings.Join(s, " ") + "]"
+}
+
+func (c *Components) writeTree(w io.Writer, depth int) {
+	writeLine(w, depth, "Components: Tag: %#x, Length: %d", c.Tag, c.Length)
+	for _, comp := range c.Component {
+		comp.writeTree(w, depth+1)
+	}
+}
+
+// Format implements fmt.Formatter.
+//
+// %v prints a concise summary, %+v prints the full decoded tree and %x prints the raw bytes in hex.
+func (c *Component) Format(f fmt.State, verb rune) {
+	format(f, verb, c)
+}
+
+func (c *Component) summary() string {
+	s := []string{}
+	if c.InvokeID != nil && len(c.InvokeID.Value) > 0 {
+		s = append(s, fmt.Sprintf("invokeID: %d", c.InvID()))
+	}
+	if c.OperationCode != nil && len(c.OperationCode.Value) > 0 {
+		s = append(s, fmt.Sprintf("opCode: %d", c.OperationCode.Value[0]))
+	}
+	if c.ErrorCode != nil && len(c.ErrorCode.Value) > 0 {
+		s = append(s, fmt.Sprintf("errorCode: %d", c.ErrorCode.Value[0]))
+	}
+	if c.ProblemCode != nil && len(c.ProblemCode.Value) > 0 {
+		s = append(s, fmt.Sprintf("problemCode: %d/%d", c.ProblemCode.Tag.Code(), c.ProblemCode.Value[0]))
+	}
+	return fmt.Sprintf("%s(%s)", c.ComponentTypeString(), strings.Join(s, ", "))
+}
+
+func (c *Component) writeTree(w io.Writer, depth int) {
+	writeLine(w, depth, "Component: %s, Type: %#x, Length: %d", c.ComponentTypeString(), c.Type, c.Length)
+	writeFieldTree(w, depth+1, "InvokeID", c.InvokeID)
+	writeFieldTree(w, depth+1, "LinkedID", c.LinkedID)
+	writeFieldTree(w, depth+1, "OperationCode", c.OperationCode)
+	writeFieldTree(w, depth+1, "ErrorCode", c.ErrorCode)
+	writeFieldTree(w, depth+1, "ProblemCode", c.ProblemCode)
+	writeFieldTree(w, depth+1, "Parameter", c.Parameter)
+}
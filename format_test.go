@@ -0,0 +1,80 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+)
+
+func TestFormat(t *testing.T) {
+	m := tcap.NewBeginInvokeWithDialogue(
+		0x11111111, tcap.DialogueAsID, tcap.LocationCancellationContext, 3,
+		0, 3, []byte{0x04, 0x08, 0x00, 0x01, 0x01, 0x21, 0x43, 0x65, 0x87, 0xf9},
+	)
+
+	t.Run("v", func(t *testing.T) {
+		want := "Begin{OTID: 11111111} Dialogue{AARQ(locationCancellationContext-v3)} [invoke(invokeID: 0, opCode: 3)]"
+		if got := fmt.Sprintf("%v", m); got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	})
+
+	t.Run("+v", func(t *testing.T) {
+		got := fmt.Sprintf("%+v", m)
+		for _, want := range []string{
+			"TCAP:",
+			"  Transaction: Begin, Type: 0x62, Length: 60",
+			"    DialoguePDU: AARQ, Type: 0x60, Length: 15",
+			"          Tag: 0x4, Length: 8, Value: 00010121436587f9",
+		} {
+			if !strings.Contains(got, want+"\n") && !strings.HasSuffix(got, want) {
+				t.Errorf("%q not found in:\n%s", want, got)
+			}
+		}
+	})
+
+	t.Run("#x", func(t *testing.T) {
+		b, err := m.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := fmt.Sprintf("%#x", m), fmt.Sprintf("%#x", b); got != want {
+			t.Errorf("got %s, want %s", got, want)
+		}
+	})
+
+	t.Run("s", func(t *testing.T) {
+		if got, want := fmt.Sprintf("%s", m), m.String(); got != want {
+			t.Errorf("got %s, want %s", got, want)
+		}
+	})
+}
+
+func TestFormatHighTag(t *testing.T) {
+	// [APPLICATION 45] and [CONTEXT 128] in high-tag-number form.
+	b := []byte{0x7f, 0x2d, 0x05, 0x9f, 0x81, 0x00, 0x01, 0xaa}
+	i, err := tcap.ParseIERecursive(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := fmt.Sprintf("%v", i), "0x7f2d[45](5){0x9f8100[128](1):aa}"; got != want {
+		t.Errorf("v: got %q, want %q", got, want)
+	}
+
+	got := fmt.Sprintf("%+v", i)
+	for _, want := range []string{
+		"Tag: 0x7f2d[45], Length: 5",
+		"  Tag: 0x9f8100[128], Length: 1, Value: aa",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("%q not found in:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import (
+	"errors"
+	"io"
+	"unicode/utf16"
+)
+
+// ErrUnsupportedDCS is returned when the data coding scheme of a USSD string is not supported.
+var ErrUnsupportedDCS = errors.New("tcap: unsupported data coding scheme")
+
+// gsm7Escape is the escape to the extension table of the GSM 7-bit default alphabet.
+const gsm7Escape = 0x1b
+
+// gsm7Default is the GSM 7-bit default alphabet (3GPP TS 23.038 6.2.1), where the escape is never encoded as is.
+var gsm7Default = []rune("@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞ\x1bÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+	"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà")
+
+// gsm7Extension is the extension table of the GSM 7-bit default alphabet, used after the escape.
+var gsm7Extension = map[byte]rune{
+	0x0a: '\f',
+	0x14: '^',
+	0x28: '{',
+	0x29: '}',
+	0x2f: '\\',
+	0x3c: '[',
+	0x3d: '~',
+	0x3e: ']',
+	0x40: '|',
+	0x65: '€',
+}
+
+var gsm7Codes, gsm7ExtensionCodes = func() (map[rune]byte, map[rune]byte) {
+	codes := make(map[rune]byte, len(gsm7Default))
+	for n, r := range gsm7Default {
+		if n != gsm7Escape {
+			codes[r] = byte(n)
+		}
+	}
+	ext := make(map[rune]byte, len(gsm7Extension))
+	for c, r := range gsm7Extension {
+		ext[r] = c
+	}
+	return codes, ext
+}()
+
+// Pack7Bit encodes the string in the GSM 7-bit default alphabet, packing 8 characters into 7 octets.
+//
+// If the 7 bits in the last octet are spare, they are filled with CR as in USSD,
+// which is removed by Unpack7Bit.
+func Pack7Bit(s string) ([]byte, error) {
+	var septets []byte
+	for _, r := range s {
+		if c, ok := gsm7Codes[r]; ok {
+			septets = append(septets, c)
+			continue
+		}
+		if c, ok := gsm7ExtensionCodes[r]; ok {
+			septets = append(septets, gsm7Escape, c)
+			continue
+		}
+		return nil, &InvalidCharacterError{Char: r}
+	}
+	if len(septets)%8 == 7 {
+		septets = append(septets, '\r')
+	}
+
+	b := make([]byte, (len(septets)*7+7)/8)
+	for n, c := range septets {
+		bit := n * 7
+		b[bit/8] |= c << (bit % 8)
+		if bit%8 > 1 {
+			b[bit/8+1] |= c >> (8 - bit%8)
+		}
+	}
+	return b, nil
+}
+
+// Unpack7Bit decodes the octets packed in the GSM 7-bit default alphabet.
+//
+// The CR filling the last octet is removed.
+func Unpack7Bit(b []byte) string {
+	septets := make([]byte, len(b)*8/7)
+	for n := range septets {
+		bit := n * 7
+		c := b[bit/8] >> (bit % 8)
+		if bit%8 > 1 {
+			c |= b[bit/8+1] << (8 - bit%8)
+		}
+		septets[n] = c & 0x7f
+	}
+	if len(septets)%8 == 0 && len(septets) > 0 && septets[len(septets)-1] == '\r' {
+		septets = septets[:len(septets)-1]
+	}
+
+	var runes []rune
+	for n := 0; n < len(septets); n++ {
+		c := septets[n]
+		if c == gsm7Escape && n+1 < len(septets) {
+			n++
+			if r, ok := gsm7Extension[septets[n]]; ok {
+				runes = append(runes, r)
+				continue
+			}
+			// the character in the default alphabet is used if not in the extension table.
+			c = septets[n]
+		}
+		runes = append(runes, gsm7Default[c])
+	}
+	return string(runes)
+}
+
+// alphabet of the data coding scheme.
+const (
+	alphabetGSM7 = iota
+	alphabet8Bit
+	alphabetUCS2
+)
+
+// dcsAlphabet returns the alphabet of the CBS data coding scheme (3GPP TS 23.038 5), used for USSD.
+func dcsAlphabet(dcs uint8) (int, error) {
+	switch {
+	case dcs&0xf0 == 0x00, dcs&0xf0 == 0x20, dcs&0xf0 == 0x30, dcs == 0x10:
+		return alphabetGSM7, nil
+	case dcs == 0x11:
+		return alphabetUCS2, nil
+	case dcs&0xc0 == 0x40:
+		switch dcs & 0x0c {
+		case 0x00:
+			return alphabetGSM7, nil
+		case 0x04:
+			return alphabet8Bit, nil
+		case 0x08:
+			return alphabetUCS2, nil
+		}
+	case dcs&0xf0 == 0xf0:
+		if dcs&0x04 == 0 {
+			return alphabetGSM7, nil
+		}
+		return alphabet8Bit, nil
+	}
+	return 0, ErrUnsupportedDCS
+}
+
+// EncodeUSSDString encodes the string as a USSD-String in the alphabet of the data coding scheme,
+// e.g., 0x0f for the GSM 7-bit default alphabet with the language unspecified.
+//
+// The language indication of DCS 0x10 and 0x11 is not prepended, which should be in s if needed.
+func EncodeUSSDString(s string, dcs uint8) ([]byte, error) {
+	alphabet, err := dcsAlphabet(dcs)
+	if err != nil {
+		return nil, err
+	}
+
+	switch alphabet {
+	case alphabetGSM7:
+		return Pack7Bit(s)
+	case alphabetUCS2:
+		units := utf16.Encode([]rune(s))
+		b := make([]byte, 0, len(units)*2)
+		for _, u := range units {
+			b = append(b, byte(u>>8), byte(u))
+		}
+		return b, nil
+	}
+	return []byte(s), nil
+}
+
+// DecodeUSSDString decodes the USSD-String in the alphabet of the data coding scheme.
+//
+// The string in the 8-bit data is returned as is.
+func DecodeUSSDString(b []byte, dcs uint8) (string, error) {
+	alphabet, err := dcsAlphabet(dcs)
+	if err != nil {
+		return "", err
+	}
+
+	switch alphabet {
+	case alphabetGSM7:
+		return Unpack7Bit(b), nil
+	case alphabetUCS2:
+		if len(b)%2 != 0 {
+			return "", io.ErrUnexpectedEOF
+		}
+		units := make([]uint16, len(b)/2)
+		for n := range units {
+			units[n] = uint16(b[2*n])<<8 | uint16(b[2*n+1])
+		}
+		return string(utf16.Decode(units)), nil
+	}
+	return string(b), nil
+}
@@ -0,0 +1,63 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestPack7Bit(t *testing.T) {
+	cases := []struct {
+		s      string
+		packed []byte
+	}{
+		{"*100#", []byte{0xaa, 0x18, 0x0c, 0x36, 0x02}},
+		// the 7 spare bits are filled with CR.
+		{"1234567", []byte{0x31, 0xd9, 0x8c, 0x56, 0xb3, 0xdd, 0x1a}},
+		{"a€[b]", []byte{0xe1, 0x4d, 0x79, 0xc3, 0x13, 0x6f, 0x7c}},
+	}
+
+	for _, c := range cases {
+		b, err := tcap.Pack7Bit(c.s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		verify.Values(t, c.s, b, c.packed)
+		verify.Values(t, c.s, tcap.Unpack7Bit(b), c.s)
+	}
+
+	var charErr *tcap.InvalidCharacterError
+	if _, err := tcap.Pack7Bit("日本"); !errors.As(err, &charErr) {
+		t.Errorf("got %v, want InvalidCharacterError", err)
+	}
+}
+
+func TestUSSDString(t *testing.T) {
+	for _, dcs := range []uint8{0x0f, 0x48, 0x44} {
+		b, err := tcap.EncodeUSSDString("Balance: 10€", dcs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s, err := tcap.DecodeUSSDString(b, dcs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		verify.Values(t, "decoded", s, "Balance: 10€")
+	}
+
+	b, err := tcap.EncodeUSSDString("日本", 0x48)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "UCS2", b, []byte{0x65, 0xe5, 0x67, 0x2c})
+
+	if _, err := tcap.EncodeUSSDString("x", 0x80); !errors.Is(err, tcap.ErrUnsupportedDCS) {
+		t.Errorf("got %v, want %v", err, tcap.ErrUnsupportedDCS)
+	}
+}
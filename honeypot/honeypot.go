@@ -0,0 +1,130 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+/*
+Package honeypot provides a responder that accepts any dialogue and answers every Invoke
+with a configurable ReturnError or decoy data, for interconnect threat research.
+
+The Responder does not have any transport. The caller passes the TCAP payload of the
+received SCCP message, and sends the returned payload back to the Calling Party.
+*/
+package honeypot
+
+import (
+	"github.com/en-vee/go-tcap"
+)
+
+// SystemFailure is the MAP Error Code for systemFailure, used by default.
+const SystemFailure uint8 = 34
+
+// MaxDepth is the nesting level of IEs accepted by default, which is deep enough for MAP.
+const MaxDepth = 32
+
+// MaxMessageLength is the length of the message accepted by default, which is larger than
+// any TCAP message carried in an SCCP message, segmented or not.
+const MaxMessageLength = 4096
+
+// Responder answers every message received.
+type Responder struct {
+	// ErrorCode is the MAP Error Code returned for the Invokes without decoy data.
+	ErrorCode uint8
+
+	// Decoy returns the parameter of ReturnResult for the Invoke with the Operation Code.
+	// Both parameters are the contents of the Parameter, like the ones given to tcap.NewInvoke.
+	// If it is nil or returns false, ReturnError with ErrorCode is returned instead.
+	Decoy func(opCode uint8, param []byte) ([]byte, bool)
+
+	// Options is used to parse the messages received, which are untrusted.
+	Options tcap.ParseOptions
+
+	// Log is called with every message decoded, before the response is made.
+	// Printing it with %+v gives the full decoded tree.
+	Log func(req *tcap.TCAP)
+}
+
+// NewResponder creates a new Responder that returns systemFailure for everything,
+// limiting the nesting level of IEs to MaxDepth and the length of the message to MaxMessageLength.
+func NewResponder() *Responder {
+	return &Responder{
+		ErrorCode: SystemFailure,
+		Options:   tcap.ParseOptions{MaxDepth: MaxDepth, MaxMessageLength: MaxMessageLength},
+	}
+}
+
+// Respond parses the TCAP payload received and returns the payload to be sent back.
+//
+// It returns nil without error for the messages that do not need a response,
+// i.e., Unidirectional, End and Abort.
+func (r *Responder) Respond(b []byte) ([]byte, error) {
+	req, err := r.Options.ParseTCAP(b)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := r.Reply(req)
+	if resp == nil {
+		return nil, nil
+	}
+	return resp.MarshalBinary()
+}
+
+// Reply returns the TCAP to be sent back for the request decoded.
+//
+// Begin and Continue are answered with End, which accepts the dialogue when the request
+// has AARQ and contains a response for each Invoke. It returns nil for the other types.
+func (r *Responder) Reply(req *tcap.TCAP) *tcap.TCAP {
+	if r.Log != nil {
+		r.Log(req)
+	}
+
+	tx := req.Transaction
+	if tx == nil {
+		return nil
+	}
+	switch tx.Type.Code() {
+	case tcap.Begin, tcap.Continue:
+	default:
+		return nil
+	}
+
+	resp := &tcap.TCAP{
+		Transaction: tcap.NewEnd(req.OTID(), []byte{}),
+		Dialogue:    tcap.AcceptDialogue(req),
+	}
+
+	var comps []*tcap.Component
+	if c := req.Components; c != nil {
+		for _, comp := range c.Component {
+			if comp.Type.Code() != tcap.Invoke {
+				continue
+			}
+			comps = append(comps, r.answer(comp))
+		}
+	}
+	if len(comps) > 0 {
+		resp.Components = tcap.NewComponents(comps...)
+	}
+
+	resp.SetLength()
+	return resp
+}
+
+// answer returns the Component answering the Invoke.
+func (r *Responder) answer(inv *tcap.Component) *tcap.Component {
+	var opCode uint8
+	if op := inv.OperationCode; op != nil && len(op.Value) > 0 {
+		opCode = op.Value[0]
+	}
+
+	if r.Decoy != nil {
+		var param []byte
+		if p := inv.Parameter; p != nil {
+			param = p.Value
+		}
+		if res, ok := r.Decoy(opCode, param); ok {
+			return tcap.NewReturnResult(int(inv.InvID()), int(opCode), true, true, res)
+		}
+	}
+	return tcap.NewReturnError(int(inv.InvID()), int(r.ErrorCode), true, nil)
+}
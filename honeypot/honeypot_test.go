@@ -0,0 +1,117 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package honeypot_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/en-vee/go-tcap/honeypot"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestResponder(t *testing.T) {
+	var logged []*tcap.TCAP
+	r := honeypot.NewResponder()
+	r.Log = func(req *tcap.TCAP) {
+		logged = append(logged, req)
+	}
+	r.Decoy = func(opCode uint8, param []byte) ([]byte, bool) {
+		if opCode != 71 {
+			return nil, false
+		}
+		return []byte{0x80, 0x01, 0x00}, true
+	}
+
+	cases := []struct {
+		description string
+		req         *tcap.TCAP
+		dialogue    string
+		components  []string
+	}{
+		{
+			"ATI with decoy",
+			tcap.NewBeginInvokeWithDialogue(0x11111111, tcap.DialogueAsID, tcap.AnyTimeInfoEnquiryContext, 3, 1, 71, nil),
+			"AARE", []string{"returnResultLast"},
+		},
+		{
+			"PSI without dialogue",
+			tcap.NewBeginInvoke(0x22222222, 2, 70, nil),
+			"", []string{"returnError"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			b, err := c.req.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			out, err := r.Respond(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := tcap.Parse(out)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := resp.DTID(), c.req.OTID(); got != want {
+				t.Errorf("DTID: got %x, want %x", got, want)
+			}
+			var dialogue string
+			if d := resp.Dialogue; d != nil {
+				dialogue = d.DialoguePDU.DialogueType()
+			}
+			if dialogue != c.dialogue {
+				t.Errorf("dialogue: got %q, want %q", dialogue, c.dialogue)
+			}
+			verify.Values(t, "components", resp.ComponentType(), c.components)
+			verify.Values(t, "invokeID", resp.InvokeID(), c.req.InvokeID())
+			if c.components[0] == "returnResultLast" {
+				verify.Values(t, "parameter", resp.Components.Component[0].Parameter.Value, []byte{0x80, 0x01, 0x00})
+			}
+		})
+	}
+
+	end := tcap.NewEndReturnResult(0x11111111, 1, 71, true, nil)
+	b, err := end.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out, err := r.Respond(b); err != nil || out != nil {
+		t.Errorf("End: got %x, %v, want nil", out, err)
+	}
+
+	if got, want := len(logged), 3; got != want {
+		t.Errorf("logged: got %d, want %d", got, want)
+	}
+}
+
+func TestResponderMaxDepth(t *testing.T) {
+	param := []byte{0x02, 0x01, 0x05}
+	for n := 0; n < honeypot.MaxDepth; n++ {
+		param = append([]byte{0x30, 0x80}, append(param, 0x00, 0x00)...)
+	}
+	b, err := tcap.NewBeginInvoke(0x11111111, 0, 71, param).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out, err := honeypot.NewResponder().Respond(b); !errors.Is(err, tcap.ErrMaxDepthExceeded) {
+		t.Errorf("got %x, %v, want %v", out, err, tcap.ErrMaxDepthExceeded)
+	}
+}
+
+func TestResponderMaxMessageLength(t *testing.T) {
+	b, err := tcap.NewBeginInvoke(0x11111111, 0, 71, nil).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tooLong *tcap.TooLongError
+	if out, err := honeypot.NewResponder().Respond(append(b, make([]byte, honeypot.MaxMessageLength)...)); !errors.As(err, &tooLong) {
+		t.Errorf("got %x, %v, want TooLongError", out, err)
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidIdentity is returned when an IMSI, MSISDN or IMEI has the number of digits not allowed,
+// or an MSISDN is not an international E.164 number.
+var ErrInvalidIdentity = errors.New("tcap: invalid subscriber or equipment identity")
+
+// IMSI is the International Mobile Subscriber Identity in decimal digits, e.g., "440101234567890".
+type IMSI string
+
+// IMSI is encoded in 3 to 8 octets of TBCD (3GPP TS 29.002).
+const (
+	minIMSIDigits = 5
+	maxIMSIDigits = 15
+)
+
+// ParseIMSI decodes the byte sequence as an IMSI.
+func ParseIMSI(b []byte) (IMSI, error) {
+	var i IMSI
+	if err := i.UnmarshalBinary(b); err != nil {
+		return "", err
+	}
+	return i, nil
+}
+
+// Validate checks the number and the set of the digits.
+func (i IMSI) Validate() error {
+	return validateDigits(string(i), minIMSIDigits, maxIMSIDigits)
+}
+
+// MarshalBinary returns the byte sequence generated from an IMSI.
+func (i IMSI) MarshalBinary() ([]byte, error) {
+	if err := i.Validate(); err != nil {
+		return nil, err
+	}
+	return (&BCDString{Digits: string(i), Format: SwappedBCD}).MarshalBinary()
+}
+
+// UnmarshalBinary sets the IMSI decoded from the byte sequence.
+func (i *IMSI) UnmarshalBinary(b []byte) error {
+	s, err := ParseBCDString(b, SwappedBCD)
+	if err != nil {
+		return err
+	}
+	if err := IMSI(s.Digits).Validate(); err != nil {
+		return err
+	}
+	*i = IMSI(s.Digits)
+	return nil
+}
+
+// MCC returns the Mobile Country Code, i.e., the first 3 digits.
+func (i IMSI) MCC() string {
+	if len(i) < 3 {
+		return ""
+	}
+	return string(i[:3])
+}
+
+// String returns the digits of IMSI.
+func (i IMSI) String() string {
+	return string(i)
+}
+
+// MSISDN is the international E.164 number of a subscriber in decimal digits, without the leading '+'.
+//
+// It is encoded as an ISDN-AddressString of InternationalNumber and ISDNTelephony.
+type MSISDN string
+
+// maxMSISDNDigits is the maximum number of digits in E.164.
+const maxMSISDNDigits = 15
+
+// ParseMSISDN decodes the byte sequence as an MSISDN.
+func ParseMSISDN(b []byte) (MSISDN, error) {
+	var m MSISDN
+	if err := m.UnmarshalBinary(b); err != nil {
+		return "", err
+	}
+	return m, nil
+}
+
+// Validate checks the number and the set of the digits.
+func (m MSISDN) Validate() error {
+	return validateDigits(string(m), 1, maxMSISDNDigits)
+}
+
+// MarshalBinary returns the byte sequence generated from an MSISDN.
+func (m MSISDN) MarshalBinary() ([]byte, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return NewE164Address(string(m)).MarshalBinary()
+}
+
+// UnmarshalBinary sets the MSISDN decoded from the byte sequence.
+//
+// ErrInvalidIdentity is returned if it is not an international E.164 number, which should be
+// decoded with ParseAddressString instead.
+func (m *MSISDN) UnmarshalBinary(b []byte) error {
+	if len(b) > MaxISDNAddressLength {
+		return &TooLongError{Field: "ISDN-AddressString", Length: len(b), Max: MaxISDNAddressLength}
+	}
+	a, err := ParseAddressString(b)
+	if err != nil {
+		return err
+	}
+	if a.NatureOfAddress != InternationalNumber || a.NumberingPlan != ISDNTelephony {
+		return ErrInvalidIdentity
+	}
+	if err := MSISDN(a.Digits).Validate(); err != nil {
+		return err
+	}
+	*m = MSISDN(a.Digits)
+	return nil
+}
+
+// String returns the MSISDN with the leading '+'.
+func (m MSISDN) String() string {
+	return "+" + string(m)
+}
+
+// IMEI is the International Mobile Equipment Identity in 15 decimal digits, or IMEISV in 16 digits.
+//
+// The last digit of 15 is the check digit, or the spare digit sent as zero.
+type IMEI string
+
+// ParseIMEI decodes the byte sequence as an IMEI.
+func ParseIMEI(b []byte) (IMEI, error) {
+	var i IMEI
+	if err := i.UnmarshalBinary(b); err != nil {
+		return "", err
+	}
+	return i, nil
+}
+
+// Validate checks the number and the set of the digits.
+func (i IMEI) Validate() error {
+	return validateDigits(string(i), 15, 16)
+}
+
+// MarshalBinary returns the byte sequence generated from an IMEI, which is 8 octets in TBCD.
+func (i IMEI) MarshalBinary() ([]byte, error) {
+	if err := i.Validate(); err != nil {
+		return nil, err
+	}
+	return (&BCDString{Digits: string(i), Format: SwappedBCD}).MarshalBinary()
+}
+
+// UnmarshalBinary sets the IMEI decoded from the byte sequence.
+func (i *IMEI) UnmarshalBinary(b []byte) error {
+	s, err := ParseBCDString(b, SwappedBCD)
+	if err != nil {
+		return err
+	}
+	if err := IMEI(s.Digits).Validate(); err != nil {
+		return err
+	}
+	*i = IMEI(s.Digits)
+	return nil
+}
+
+// TAC returns the Type Allocation Code, i.e., the first 8 digits.
+func (i IMEI) TAC() string {
+	if len(i) < 8 {
+		return ""
+	}
+	return string(i[:8])
+}
+
+// String returns the IMEI formatted as TAC-SNR-CD, or TAC-SNR-SVN for IMEISV.
+func (i IMEI) String() string {
+	if len(i) < 15 {
+		return string(i)
+	}
+	return fmt.Sprintf("%s-%s-%s", i[:8], i[8:14], i[14:])
+}
+
+// validateDigits checks that s is the decimal digits of the length within min and max.
+func validateDigits(s string, min, max int) error {
+	for _, d := range s {
+		if d < '0' || d > '9' {
+			return &InvalidDigitError{Digit: d}
+		}
+	}
+	if len(s) < min || len(s) > max {
+		return ErrInvalidIdentity
+	}
+	return nil
+}
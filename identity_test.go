@@ -0,0 +1,73 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestIMSI(t *testing.T) {
+	b, err := tcap.IMSI("001010123456789").MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "encoded", b, []byte{0x00, 0x01, 0x01, 0x21, 0x43, 0x65, 0x87, 0xf9})
+
+	imsi, err := tcap.ParseIMSI(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "decoded", imsi, tcap.IMSI("001010123456789"))
+	verify.Values(t, "MCC", imsi.MCC(), "001")
+
+	if _, err := tcap.IMSI("0010101234567890").MarshalBinary(); !errors.Is(err, tcap.ErrInvalidIdentity) {
+		t.Errorf("too long: got %v, want %v", err, tcap.ErrInvalidIdentity)
+	}
+	var digitErr *tcap.InvalidDigitError
+	if err := tcap.IMSI("00101012345678a").Validate(); !errors.As(err, &digitErr) {
+		t.Errorf("invalid digit: got %v, want InvalidDigitError", err)
+	}
+}
+
+func TestMSISDN(t *testing.T) {
+	b, err := tcap.MSISDN("819011112222").MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "encoded", b, []byte{0x91, 0x18, 0x09, 0x11, 0x11, 0x22, 0x22})
+
+	m, err := tcap.ParseMSISDN(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "string", m.String(), "+819011112222")
+
+	if _, err := tcap.ParseMSISDN([]byte{0xa1, 0x90, 0x11, 0xf1}); !errors.Is(err, tcap.ErrInvalidIdentity) {
+		t.Errorf("national: got %v, want %v", err, tcap.ErrInvalidIdentity)
+	}
+}
+
+func TestIMEI(t *testing.T) {
+	b, err := tcap.IMEI("490154203237518").MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "encoded", b, []byte{0x94, 0x10, 0x45, 0x02, 0x23, 0x73, 0x15, 0xf8})
+
+	imei, err := tcap.ParseIMEI(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "string", imei.String(), "49015420-323751-8")
+	verify.Values(t, "TAC", imei.TAC(), "49015420")
+
+	if _, err := tcap.ParseIMEI([]byte{0x94, 0x10, 0x45}); !errors.Is(err, tcap.ErrInvalidIdentity) {
+		t.Errorf("too short: got %v, want %v", err, tcap.ErrInvalidIdentity)
+	}
+}
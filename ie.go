@@ -5,12 +5,25 @@
 package tcap
 
 import (
+	"errors"
 	"fmt"
 	"io"
 )
 
+// ErrTagOverflow is returned when the tag number in high-tag-number form does not fit in a Tag.
+var ErrTagOverflow = errors.New("tcap: tag number too large")
+
 // Tag is a Tag in TCAP IE
-type Tag uint8
+//
+// The lowest octet is the identifier octet. For tag numbers 31 and above, whose code in
+// the identifier octet is 0x1f (high-tag-number form), the number is held in the upper bits.
+type Tag uint32
+
+// highTagNumber is the tag code in the identifier octet indicating high-tag-number form.
+const highTagNumber = 0x1f
+
+// maxTagNumber is the largest tag number that fits in a Tag.
+const maxTagNumber = 1<<24 - 1
 
 // Class definitions.
 const (
@@ -27,7 +40,12 @@ const (
 )
 
 // NewTag creates a new Tag.
+//
+// The code 31 and above is encoded in high-tag-number form.
 func NewTag(cls, form, code int) Tag {
+	if code >= highTagNumber {
+		return Tag((cls<<6)|(form<<5)|highTagNumber) | Tag(code&maxTagNumber)<<8
+	}
 	return Tag((cls << 6) | (form << 5) | code)
 }
 
@@ -83,15 +101,85 @@ func (t Tag) Form() int {
 
 // Code returns the Code retieved from a Tag.
 func (t Tag) Code() int {
+	if t.isHigh() {
+		return int(t >> 8)
+	}
 	return int(t) & 0x1f
 }
 
+// MarshalLen returns the serial length of Tag.
+func (t Tag) MarshalLen() int {
+	if !t.isHigh() {
+		return 1
+	}
+	l := 2
+	for n := t >> 8; n > 0x7f; n >>= 7 {
+		l++
+	}
+	return l
+}
+
+// MarshalTo puts the byte sequence of Tag in the byte array given as b,
+// and returns the number of bytes written.
+func (t Tag) MarshalTo(b []byte) (int, error) {
+	l := t.MarshalLen()
+	if len(b) < l {
+		return 0, io.ErrShortBuffer
+	}
+
+	b[0] = uint8(t)
+	n := t >> 8
+	for i := l - 1; i > 0; i-- {
+		b[i] = uint8(n & 0x7f)
+		if i < l-1 {
+			b[i] |= 0x80
+		}
+		n >>= 7
+	}
+	return l, nil
+}
+
+// ParseTag parses the Tag at the beginning of b, and returns it with the number of bytes it occupies.
+func ParseTag(b []byte) (Tag, int, error) {
+	if len(b) == 0 {
+		return 0, 0, io.ErrUnexpectedEOF
+	}
+	if b[0]&0x1f != highTagNumber {
+		return Tag(b[0]), 1, nil
+	}
+
+	var n int
+	for i := 1; i < len(b); i++ {
+		n = n<<7 | int(b[i]&0x7f)
+		if n > maxTagNumber {
+			return 0, 0, ErrTagOverflow
+		}
+		if b[i]&0x80 == 0 {
+			return Tag(b[0]) | Tag(n)<<8, i + 1, nil
+		}
+	}
+	return 0, 0, io.ErrUnexpectedEOF
+}
+
+// isHigh reports whether the Tag is in high-tag-number form.
+func (t Tag) isHigh() bool {
+	return t&0x1f == highTagNumber
+}
+
 // IE is a General Structure of TCAP Information Elements.
 type IE struct {
 	Tag
 	Length int
 	Value  []byte
 	IE     []*IE
+
+	parent *IE
+
+	// raw and offset are the bytes and the position of the IE in the input, if recorded.
+	raw    []byte
+	offset int
+	// lazy parses the children on first access, if deferred.
+	lazy *parser
 }
 
 // NewIE creates a new IE.
@@ -114,50 +202,85 @@ func (i *IE) MarshalBinary() ([]byte, error) {
 	return b, nil
 }
 
+// AppendBinary appends the byte sequence generated from an IE instance to b.
+func (i *IE) AppendBinary(b []byte) ([]byte, error) {
+	return appendBinary(b, i)
+}
+
+// WriteTo writes the byte sequence generated from an IE instance to w.
+func (i *IE) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(w, i)
+}
+
 // MarshalTo puts the byte sequence in the byte array given as b.
 func (i *IE) MarshalTo(b []byte) error {
 	if len(b) < 2 {
 		return io.ErrUnexpectedEOF
 	}
 
-	// 1. Calculate the length header bytes (e.g., [0x32] or [0x81, 0xB1])
-	lenBytes := MarshalAsn1ElementLength(i.Length)
+	// 1. Get the Tag and Length Header bytes (e.g., [0x04, 0x32] or [0x04, 0x81, 0xB1])
+	header, trailer := i.header()
 
-	// 2. Ensure the provided buffer can fit Tag (1) + Length Header + Value
-	totalNeeded := 1 + len(lenBytes) + len(i.Value)
+	// 2. Ensure the provided buffer can fit Tag + Length Header + Value
+	totalNeeded := len(header) + len(i.Value) + len(trailer)
 	if len(b) < totalNeeded {
 		return io.ErrShortBuffer
 	}
 
-	// 3. Set the Tag
-	b[0] = uint8(i.Tag)
-
-	// 4. Copy the Length Header starting at index 1
-	copy(b[1:], lenBytes)
-	copy(b[2:i.MarshalLen()], i.Value)
+	// 3. Copy the Tag and Length Header, followed by the Value
+	copy(b, header)
+	copy(b[len(header):], i.Value)
+	copy(b[len(header)+len(i.Value):totalNeeded], trailer)
 	return nil
 }
 
-// ParseMultiIEs parses multiple (unspecified number of) IEs to []*IE at a time.
-func ParseMultiIEs(b []byte) ([]*IE, error) {
-	var ies []*IE
-	for {
-		if len(b) == 0 {
-			break
-		}
+// header returns the tag and length octets of the IE, and the octets following the Value.
+//
+// The original ones are reused if recorded with Raw in ParseOptions and the Tag and Length are
+// unchanged, so that the non-minimal and indefinite-length forms are reproduced.
+func (i *IE) header() (header, trailer []byte) {
+	if header, trailer, ok := i.originalHeader(); ok {
+		return header, trailer
+	}
+
+	header = make([]byte, i.Tag.MarshalLen())
+	_, _ = i.Tag.MarshalTo(header)
+	return append(header, MarshalAsn1ElementLength(i.Length)...), nil
+}
 
-		i, err := ParseIE(b)
-		if err != nil {
-			return nil, err
+// originalHeader returns the tag and length octets and the end-of-contents in the raw bytes, if still valid.
+func (i *IE) originalHeader() (header, trailer []byte, ok bool) {
+	if i.raw == nil {
+		return nil, nil, false
+	}
+	tag, tagLen, err := ParseTag(i.raw)
+	if err != nil || tag != i.Tag || len(i.raw) <= tagLen {
+		return nil, nil, false
+	}
+
+	if i.raw[tagLen] == 0x80 {
+		if len(i.raw) != tagLen+1+i.Length+2 {
+			return nil, nil, false
 		}
-		ies = append(ies, i)
-		b = b[i.MarshalLen():]
-		continue
+		return i.raw[:tagLen+1], i.raw[len(i.raw)-2:], true
+	}
+
+	l, lLength, err := UnmarshalAsn1ElementLength(i.raw[tagLen-1:])
+	if err != nil || l != i.Length || len(i.raw) != tagLen+lLength+l {
+		return nil, nil, false
 	}
-	return ies, nil
+	return i.raw[:tagLen+lLength], nil, true
+}
+
+// ParseMultiIEs parses multiple (unspecified number of) IEs to []*IE at a time.
+func ParseMultiIEs(b []byte) ([]*IE, error) {
+	return (&parser{}).parseIEs(b, 3)
 }
 
 // ParseIE parses given byte sequence as an IE.
+//
+// The Value of IE refers to b without copying, which must not be modified while the IE is in use.
+// Use ParseOptions with Copy if b is reused.
 func ParseIE(b []byte) (*IE, error) {
 	i := &IE{}
 	if err := i.UnmarshalBinary(b); err != nil {
@@ -167,22 +290,19 @@ func ParseIE(b []byte) (*IE, error) {
 }
 
 // UnmarshalBinary sets the values retrieved from byte sequence in an IE.
+//
+// The constructed IE in indefinite-length form is accepted, and its Value is the
+// contents without the end-of-contents octets.
 func (i *IE) UnmarshalBinary(b []byte) error {
 	l := len(b)
 	if l < 3 {
 		return io.ErrUnexpectedEOF
 	}
 
-	var err error
-	i.Tag = Tag(b[0])
-	lLength := 0
-	if i.Length, lLength, err = UnmarshalAsn1ElementLength(b); err != nil {
-		return err
+	_, err := (&parser{}).parse(i, b)
+	if err != nil {
+		return parseErrorAt(err, b, 0)
 	}
-	// if l < 2+int(i.Length) {
-	// 	return io.ErrUnexpectedEOF
-	// }
-	i.Value = b[1+lLength : 1+lLength+i.Length]
 	return nil
 }
 
@@ -194,35 +314,10 @@ func ParseAsBer(b []byte) ([]*IE, error) {
 }
 
 // ParseAsBER parses given byte sequence as multiple IEs.
+//
+// As in ParseIE, the Values of IEs refer to b without copying.
 func ParseAsBER(b []byte) ([]*IE, error) {
-	var ies []*IE
-	for {
-		if len(b) == 0 {
-			break
-		}
-
-		i, err := ParseIERecursive(b)
-		if err != nil {
-			return nil, err
-		}
-		ies = append(ies, i)
-
-		if len(i.IE) == 0 {
-			b = b[i.MarshalLen():]
-			continue
-		}
-
-		if i.IE[0].MarshalLen() < i.MarshalLen()-2 {
-			var l = 2
-			for _, ie := range i.IE {
-				l += ie.MarshalLen()
-			}
-			b = b[l:]
-			continue
-		}
-		b = b[i.MarshalLen():]
-	}
-	return ies, nil
+	return (&parser{recursive: true}).parseIEs(b, 2)
 }
 
 // ParseIERecursive parses given byte sequence as an IE.
@@ -235,38 +330,27 @@ func ParseIERecursive(b []byte) (*IE, error) {
 }
 
 // ParseRecursive sets the values retrieved from byte sequence in an IE.
+//
+// The constructed IEs in indefinite-length form are accepted at any depth, and
+// build the same tree as the ones in definite-length form.
 func (i *IE) ParseRecursive(b []byte) error {
 	l := len(b)
 	if l < 2 {
 		return io.ErrUnexpectedEOF
 	}
-	var err error
-	lLength := 0
-	i.Tag = Tag(b[0])
-	if i.Length, lLength, err = UnmarshalAsn1ElementLength(b); err != nil {
-		return err
-	}
-	// if int(i.Length)+2 > len(b) {
-	// 	return nil
-	// }
-	i.Value = b[1+lLength : 1+lLength+i.Length]
-
-	if i.Tag.Form() == 1 {
-		x, err := ParseAsBER(i.Value)
-		if err != nil {
-			return nil
-		}
-		i.IE = append(i.IE, x...)
-	}
 
+	_, err := (&parser{recursive: true}).parse(i, b)
+	if err != nil {
+		return parseErrorAt(err, b, 0)
+	}
 	return nil
 }
 
 // MarshalLen returns the serial length of IE.
 func (ie *IE) MarshalLen() int {
-	// 1 (Tag) + Length of Length Header + the value (c.Length)
-	lHeader := len(MarshalAsn1ElementLength(ie.Length))
-	return 1 + lHeader + ie.Length
+	// Tag + Length Header + the value (c.Length) + end-of-contents if any
+	header, trailer := ie.header()
+	return len(header) + ie.Length + len(trailer)
 }
 
 // SetLength sets the length in Length field.
@@ -276,7 +360,7 @@ func (i *IE) SetLength() {
 
 // String returns IE in human readable string.
 func (i *IE) String() string {
-	return fmt.Sprintf("{Tag: %#x, Length: %d, Value: %x, IE: %v}",
+	return fmt.Sprintf("{Tag: %#x, Length: %d, Value: %x, IE: %s}",
 		i.Tag,
 		i.Length,
 		i.Value,
@@ -0,0 +1,147 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestHighTagNumber(t *testing.T) {
+	cases := []struct {
+		description string
+		tag         tcap.Tag
+		serialized  []byte
+	}{
+		{"Short", tcap.NewContextSpecificPrimitiveTag(30), []byte{0x9e, 0x01, 0xff}},
+		{"31", tcap.NewContextSpecificPrimitiveTag(31), []byte{0x9f, 0x1f, 0x01, 0xff}},
+		{"TwoOctets", tcap.NewContextSpecificConstructorTag(200), []byte{0xbf, 0x81, 0x48, 0x01, 0xff}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			i := tcap.NewIE(c.tag, []byte{0xff})
+			b, err := i.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			verify.Values(t, "serialized", b, c.serialized)
+
+			parsed, err := tcap.ParseIE(c.serialized)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if parsed.Tag != c.tag {
+				t.Errorf("Tag: got %#x, want %#x", parsed.Tag, c.tag)
+			}
+			if got, want := parsed.Code(), c.tag.Code(); got != want {
+				t.Errorf("Code: got %d, want %d", got, want)
+			}
+			verify.Values(t, "Value", parsed.Value, []byte{0xff})
+		})
+	}
+
+	t.Run("Nested", func(t *testing.T) {
+		b := []byte{0x30, 0x08, 0xbf, 0x81, 0x48, 0x04, 0x9f, 0x20, 0x01, 0xaa}
+		ies, err := tcap.ParseAsBER(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		inner := ies[0].IE[0]
+		if got, want := inner.Code(), 200; got != want {
+			t.Errorf("Code: got %d, want %d", got, want)
+		}
+		if got, want := inner.IE[0].Code(), 32; got != want {
+			t.Errorf("Code: got %d, want %d", got, want)
+		}
+	})
+}
+
+func TestHighTagNumberComponent(t *testing.T) {
+	for _, tag := range []tcap.Tag{
+		tcap.NewContextSpecificConstructorTag(31),
+		tcap.NewContextSpecificConstructorTag(200),
+	} {
+		param, err := tcap.NewIE(tag, []byte{0x04, 0x01, 0xaa}).MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := tcap.NewAnyValue(param).Tag(); got != tag {
+			t.Errorf("AnyValue: got %#x, want %#x", got, tag)
+		}
+
+		res := tcap.NewReturnResult(1, 45, true, true, param)
+		res.ResultRetres.Tag = tag
+		res.SetLength()
+
+		for _, c := range []*tcap.Component{tcap.NewInvoke(1, -1, 45, true, param), res} {
+			b, err := c.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			verify.Values(t, "MarshalLen", c.MarshalLen(), len(b))
+
+			parsed, err := tcap.ParseComponent(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ies := parsed.Parameter.IE; len(ies) != 1 || ies[0].Tag != tag {
+				t.Errorf("%s: Parameter: got %v, want %#x", c.ComponentTypeString(), ies, tag)
+			}
+			if r := parsed.ResultRetres; r != nil && r.Tag != tag {
+				t.Errorf("ResultRetres: got %#x, want %#x", r.Tag, tag)
+			}
+			verify.Values(t, "Parameter", parsed.Parameter.Value, param)
+
+			again, err := parsed.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			verify.Values(t, "re-marshaled", again, b)
+		}
+	}
+}
+
+func TestIndefiniteLength(t *testing.T) {
+	definite := []byte{0x30, 0x08, 0xa1, 0x03, 0x02, 0x01, 0x05, 0x04, 0x01, 0xaa}
+	indefinite := []byte{0x30, 0x80, 0xa1, 0x80, 0x02, 0x01, 0x05, 0x00, 0x00, 0x04, 0x01, 0xaa, 0x00, 0x00, 0x02, 0x01, 0x01}
+
+	want, err := tcap.ParseAsBER(definite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := tcap.ParseAsBER(indefinite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d IEs, want 2", len(got))
+	}
+
+	var describe func(i *tcap.IE) []byte
+	describe = func(i *tcap.IE) []byte {
+		if len(i.IE) == 0 {
+			return append([]byte{uint8(i.Tag)}, i.Value...)
+		}
+		d := []byte{uint8(i.Tag)}
+		for _, c := range i.IE {
+			d = append(d, describe(c)...)
+		}
+		return d
+	}
+	verify.Values(t, "tree", describe(got[0]), describe(want[0]))
+
+	i, err := tcap.ParseIE(indefinite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "Value", i.Value, indefinite[2:12])
+
+	if _, err := tcap.ParseIE([]byte{0x30, 0x80, 0x02, 0x01, 0x05}); err == nil {
+		t.Error("missing end-of-contents: got no error")
+	}
+}
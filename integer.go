@@ -0,0 +1,101 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import (
+	"errors"
+	"math/big"
+)
+
+// Errors returned when decoding the value of IE.
+var (
+	ErrEmptyValue      = errors.New("tcap: value has no octets")
+	ErrIntegerOverflow = errors.New("tcap: integer does not fit in int64")
+)
+
+// NewInteger creates a new IE with v encoded as an INTEGER in two's complement
+// with the minimum number of octets.
+func NewInteger(tag Tag, v int64) *IE {
+	b := make([]byte, 8)
+	for n := 7; n >= 0; n-- {
+		b[n] = byte(v)
+		v >>= 8
+	}
+	return NewIE(tag, trimInteger(b))
+}
+
+// NewBigInteger creates a new IE with v encoded as an INTEGER in two's complement
+// with the minimum number of octets, for the values wider than 64 bits.
+func NewBigInteger(tag Tag, v *big.Int) *IE {
+	var b []byte
+	switch v.Sign() {
+	case 0:
+		b = []byte{0}
+	case 1:
+		b = v.Bytes()
+		if b[0]&0x80 != 0 {
+			b = append([]byte{0}, b...)
+		}
+	case -1:
+		// -v-1 inverted is the two's complement of v.
+		b = new(big.Int).Sub(new(big.Int).Neg(v), big.NewInt(1)).Bytes()
+		for n := range b {
+			b[n] = ^b[n]
+		}
+		if len(b) == 0 || b[0]&0x80 == 0 {
+			b = append([]byte{0xff}, b...)
+		}
+	}
+	return NewIE(tag, b)
+}
+
+// trimInteger removes the redundant leading octets of an INTEGER in two's complement.
+func trimInteger(b []byte) []byte {
+	for len(b) > 1 {
+		if (b[0] == 0x00 && b[1]&0x80 == 0) || (b[0] == 0xff && b[1]&0x80 != 0) {
+			b = b[1:]
+			continue
+		}
+		break
+	}
+	return b
+}
+
+// Int64 returns the value of IE decoded as an INTEGER in two's complement.
+//
+// ErrIntegerOverflow is returned if it does not fit in int64, use BigInt instead.
+func (i *IE) Int64() (int64, error) {
+	b := i.Value
+	if len(b) == 0 {
+		return 0, ErrEmptyValue
+	}
+	if b = trimInteger(b); len(b) > 8 {
+		return 0, ErrIntegerOverflow
+	}
+
+	var v int64
+	if b[0]&0x80 != 0 {
+		v = -1
+	}
+	for _, x := range b {
+		v = v<<8 | int64(x)
+	}
+	return v, nil
+}
+
+// BigInt returns the value of IE decoded as an INTEGER in two's complement.
+func (i *IE) BigInt() (*big.Int, error) {
+	b := i.Value
+	if len(b) == 0 {
+		return nil, ErrEmptyValue
+	}
+
+	v := new(big.Int).SetBytes(b)
+	if b[0]&0x80 != 0 {
+		// subtract 2^(8*len) to get the negative value.
+		v.Sub(v, new(big.Int).Lsh(big.NewInt(1), uint(8*len(b))))
+	}
+	return v, nil
+}
@@ -0,0 +1,67 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestInteger(t *testing.T) {
+	tag := tcap.NewUniversalPrimitiveTag(2)
+
+	cases := []struct {
+		v       int64
+		encoded []byte
+	}{
+		{0, []byte{0x00}},
+		{127, []byte{0x7f}},
+		{128, []byte{0x00, 0x80}},
+		{256, []byte{0x01, 0x00}},
+		{-1, []byte{0xff}},
+		{-128, []byte{0x80}},
+		{-129, []byte{0xff, 0x7f}},
+		{-9223372036854775808, []byte{0x80, 0, 0, 0, 0, 0, 0, 0}},
+	}
+
+	for _, c := range cases {
+		i := tcap.NewInteger(tag, c.v)
+		verify.Values(t, "encoded", i.Value, c.encoded)
+
+		got, err := i.Int64()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != c.v {
+			t.Errorf("Int64: got %d, want %d", got, c.v)
+		}
+
+		big1 := tcap.NewBigInteger(tag, big.NewInt(c.v))
+		verify.Values(t, "big encoded", big1.Value, c.encoded)
+		if b, err := big1.BigInt(); err != nil || b.Int64() != c.v {
+			t.Errorf("BigInt: got %v, %v, want %d", b, err, c.v)
+		}
+	}
+
+	// 2^64 and -(2^64)-1 need more than 8 octets
+	wide, _ := new(big.Int).SetString("18446744073709551616", 10)
+	for _, v := range []*big.Int{wide, new(big.Int).Sub(new(big.Int).Neg(wide), big.NewInt(1))} {
+		i := tcap.NewBigInteger(tag, v)
+		if got, err := i.BigInt(); err != nil || got.Cmp(v) != 0 {
+			t.Errorf("BigInt: got %v, %v, want %v", got, err, v)
+		}
+		if _, err := i.Int64(); !errors.Is(err, tcap.ErrIntegerOverflow) {
+			t.Errorf("Int64: got %v, want %v", err, tcap.ErrIntegerOverflow)
+		}
+	}
+
+	if _, err := tcap.NewIE(tag, nil).Int64(); !errors.Is(err, tcap.ErrEmptyValue) {
+		t.Errorf("empty: got %v, want %v", err, tcap.ErrEmptyValue)
+	}
+}
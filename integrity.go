@@ -0,0 +1,151 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors returned by AttachIntegrity and VerifyIntegrity.
+var (
+	ErrNoDialogue       = errors.New("tcap: no Dialogue Portion")
+	ErrNoIntegrityToken = errors.New("tcap: no integrity token in user information")
+)
+
+// IntegrityHook is implemented by the deployments that protect the TCAP messages with
+// integrity tokens (MAPsec-like or proprietary).
+//
+// The token is carried in the user information of DialoguePDU as an EXTERNAL with
+// the direct-reference given by ObjectIdentifier and the octet-aligned encoding.
+// The data protected is the whole Component Portion, i.e., the octets as received when
+// verifying a TCAP parsed, and the ones marshaled when signing.
+type IntegrityHook interface {
+	// ObjectIdentifier returns the encoded OBJECT IDENTIFIER that identifies the token.
+	ObjectIdentifier() []byte
+
+	// Sign returns the token for the data.
+	Sign(data []byte) ([]byte, error)
+
+	// Verify returns an error if the token is not valid for the data.
+	Verify(data, token []byte) error
+}
+
+// AttachIntegrity signs the Component Portion with the IntegrityHook and puts the token
+// in the user information of DialoguePDU, replacing the existing one with the same OID.
+//
+// The Components must not be modified after this, otherwise the token gets invalid.
+func (t *TCAP) AttachIntegrity(h IntegrityHook) error {
+	pdu, err := t.dialoguePDU()
+	if err != nil {
+		return err
+	}
+
+	data, err := t.protectedData(false)
+	if err != nil {
+		return err
+	}
+	token, err := h.Sign(data)
+	if err != nil {
+		return fmt.Errorf("tcap: failed to sign: %w", err)
+	}
+
+	externals, err := userInfoExternals(pdu)
+	if err != nil {
+		return err
+	}
+
+	oid := h.ObjectIdentifier()
+	var value []byte
+	for _, e := range externals {
+		if _, ok := externalToken(e, oid); ok {
+			continue
+		}
+		value = append(value, encodeTLV(e)...)
+	}
+	value = append(value, encodeTLV(newExternal(oid, OctetAligned, token))...)
+
+	pdu.UserInformation = NewIE(NewContextSpecificConstructorTag(30), value)
+	t.SetLength()
+	return nil
+}
+
+// VerifyIntegrity extracts the token from the user information of DialoguePDU and
+// verifies it against the Component Portion with the IntegrityHook.
+func (t *TCAP) VerifyIntegrity(h IntegrityHook) error {
+	pdu, err := t.dialoguePDU()
+	if err != nil {
+		return err
+	}
+
+	externals, err := userInfoExternals(pdu)
+	if err != nil {
+		return err
+	}
+
+	oid := h.ObjectIdentifier()
+	for _, e := range externals {
+		token, ok := externalToken(e, oid)
+		if !ok {
+			continue
+		}
+
+		data, err := t.protectedData(true)
+		if err != nil {
+			return err
+		}
+		if err := h.Verify(data, token); err != nil {
+			return fmt.Errorf("tcap: integrity check failed: %w", err)
+		}
+		return nil
+	}
+	return ErrNoIntegrityToken
+}
+
+func (t *TCAP) dialoguePDU() (*DialoguePDU, error) {
+	if t.Dialogue == nil || t.Dialogue.DialoguePDU == nil {
+		return nil, ErrNoDialogue
+	}
+	return t.Dialogue.DialoguePDU, nil
+}
+
+// protectedData returns the byte sequence protected by the integrity token. If received is true,
+// it is the Component Portion as received if the TCAP is parsed, which is kept in the Payload.
+func (t *TCAP) protectedData(received bool) ([]byte, error) {
+	if t.Components == nil {
+		return []byte{}, nil
+	}
+	if received {
+		if p := t.receivedComponentPortion(); p != nil {
+			return p, nil
+		}
+	}
+	return t.Components.MarshalBinary()
+}
+
+// receivedComponentPortion returns the Component Portion left in the Payload of the Dialogue,
+// or of the Transaction if no Dialogue Portion, or nil if not present, e.g., the TCAP is built.
+func (t *TCAP) receivedComponentPortion() []byte {
+	if t.Transaction == nil {
+		return nil
+	}
+	p := t.Transaction.Payload
+	if t.Dialogue != nil {
+		p = t.Dialogue.Payload
+	}
+	if tag, _, err := ParseTag(p); err != nil || tag != NewApplicationWideConstructorTag(12) {
+		return nil
+	}
+	return p
+}
+
+// externalToken returns the octet-aligned value in the EXTERNAL if its direct-reference is oid.
+func externalToken(ext *IE, oid []byte) ([]byte, bool) {
+	e, ok := externalWithOID(ext, oid)
+	if !ok || e.Encoding != OctetAligned {
+		return nil, false
+	}
+	return e.Data, true
+}
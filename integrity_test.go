@@ -0,0 +1,141 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+)
+
+type hmacHook struct {
+	key []byte
+}
+
+func (h *hmacHook) ObjectIdentifier() []byte {
+	return []byte{0x2b, 0x06, 0x01, 0x04, 0x01, 0x81, 0x9f, 0x63}
+}
+
+func (h *hmacHook) Sign(data []byte) ([]byte, error) {
+	m := hmac.New(sha256.New, h.key)
+	m.Write(data)
+	return m.Sum(nil), nil
+}
+
+func (h *hmacHook) Verify(data, token []byte) error {
+	expected, _ := h.Sign(data)
+	if !hmac.Equal(token, expected) {
+		return errors.New("token mismatch")
+	}
+	return nil
+}
+
+func TestIntegrity(t *testing.T) {
+	hook := &hmacHook{key: []byte("secret")}
+
+	m := tcap.NewBeginInvokeWithDialogue(0x11111111, tcap.DialogueAsID, tcap.AnyTimeInfoEnquiryContext, 3, 0, 71, []byte{0x30, 0x03, 0x80, 0x01, 0x00})
+	if err := m.AttachIntegrity(hook); err != nil {
+		t.Fatal(err)
+	}
+	// attaching again replaces the token
+	if err := m.AttachIntegrity(hook); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := tcap.Parse(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := parsed.VerifyIntegrity(hook); err != nil {
+		t.Errorf("valid token: %v", err)
+	}
+	if err := parsed.VerifyIntegrity(&hmacHook{key: []byte("other")}); err == nil {
+		t.Error("wrong key: got nil error")
+	}
+
+	tampered := append([]byte{}, b...)
+	tampered[len(tampered)-1] = 0x01
+	if parsed, err := tcap.Parse(tampered); err != nil {
+		t.Fatal(err)
+	} else if err := parsed.VerifyIntegrity(hook); err == nil {
+		t.Error("tampered: got nil error")
+	}
+
+	plain := tcap.NewBeginInvokeWithDialogue(0x11111111, tcap.DialogueAsID, tcap.AnyTimeInfoEnquiryContext, 3, 0, 71, nil)
+	if err := plain.VerifyIntegrity(hook); !errors.Is(err, tcap.ErrNoIntegrityToken) {
+		t.Errorf("no token: got %v, want %v", err, tcap.ErrNoIntegrityToken)
+	}
+	if err := tcap.NewBeginInvoke(1, 0, 71, nil).AttachIntegrity(hook); !errors.Is(err, tcap.ErrNoDialogue) {
+		t.Errorf("no dialogue: got %v, want %v", err, tcap.ErrNoDialogue)
+	}
+}
+
+// peerHook signs the Component Portion encoded by the peer instead of the data given.
+type peerHook struct {
+	*hmacHook
+	encoded []byte
+}
+
+func (h *peerHook) Sign([]byte) ([]byte, error) {
+	return h.hmacHook.Sign(h.encoded)
+}
+
+func TestIntegrityReceivedEncoding(t *testing.T) {
+	hook := &hmacHook{key: []byte("secret")}
+
+	cases := []struct {
+		description string
+		encode      func(contents []byte) []byte
+	}{
+		{
+			"Non-minimal length",
+			func(contents []byte) []byte {
+				return append([]byte{0x6c, 0x81, uint8(len(contents))}, contents...)
+			},
+		}, {
+			"Indefinite length",
+			func(contents []byte) []byte {
+				return append(append([]byte{0x6c, 0x80}, contents...), 0x00, 0x00)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			m := tcap.NewBeginInvokeWithDialogue(0x11111111, tcap.DialogueAsID, tcap.AnyTimeInfoEnquiryContext, 3, 0, 71, []byte{0x30, 0x03, 0x80, 0x01, 0x00})
+			minimal, err := m.Components.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			encoded := c.encode(minimal[2:])
+			if err := m.AttachIntegrity(&peerHook{hook, encoded}); err != nil {
+				t.Fatal(err)
+			}
+
+			// replace the Component Portion at the end with the one encoded by the peer.
+			b, err := m.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			b = append(b[:len(b)-len(minimal)], encoded...)
+			b[1] += uint8(len(encoded) - len(minimal))
+
+			parsed, err := tcap.Parse(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := parsed.VerifyIntegrity(hook); err != nil {
+				t.Errorf("valid token: %v", err)
+			}
+		})
+	}
+}
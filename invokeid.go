@@ -0,0 +1,148 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrNoInvokeID is returned when all the Invoke IDs are in use in the dialogue.
+var ErrNoInvokeID = errors.New("tcap: no invoke ID available")
+
+// Range of Invoke ID (Q.773).
+const (
+	MinInvokeID = -128
+	MaxInvokeID = 127
+)
+
+// InvokeIDAllocator allocates the Invoke IDs of a dialogue, each of which is not reused while
+// the operation is active, so that the peer never rejects the Invoke with duplicateInvokeID.
+//
+// It is safe for concurrent use.
+type InvokeIDAllocator struct {
+	mu     sync.Mutex
+	active map[int]struct{}
+	next   int
+}
+
+// NewInvokeIDAllocator creates a new InvokeIDAllocator allocating the Invoke IDs in sequence
+// from start, wrapping around from MaxInvokeID to MinInvokeID.
+func NewInvokeIDAllocator(start int) *InvokeIDAllocator {
+	a := &InvokeIDAllocator{active: make(map[int]struct{})}
+	a.next = wrapInvokeID(start)
+	return a
+}
+
+// wrapInvokeID returns id wrapped into the range of Invoke ID.
+func wrapInvokeID(id int) int {
+	return int(int8(id))
+}
+
+// Allocate returns a new Invoke ID not active, and marks it active until Release.
+//
+// ErrNoInvokeID is returned if all the Invoke IDs are active.
+func (a *InvokeIDAllocator) Allocate() (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for n := MinInvokeID; n <= MaxInvokeID; n++ {
+		id := a.next
+		a.next = wrapInvokeID(a.next + 1)
+		if _, ok := a.active[id]; !ok {
+			a.active[id] = struct{}{}
+			return id, nil
+		}
+	}
+	return 0, ErrNoInvokeID
+}
+
+// Reserve marks the Invoke ID active, e.g., the one chosen by the application, and reports
+// whether it was not active. It returns false if the Invoke ID is out of range.
+func (a *InvokeIDAllocator) Reserve(id int) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !validInvokeID(id) {
+		return false
+	}
+	if _, ok := a.active[id]; ok {
+		return false
+	}
+	a.active[id] = struct{}{}
+	return true
+}
+
+// Release marks the Invoke ID not active, which should be called when the operation is completed,
+// e.g., on the expiry of the invocation timer of the operations with no result, and reports whether
+// it was active. It returns false if the Invoke ID is out of range.
+func (a *InvokeIDAllocator) Release(id int) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.release(id)
+}
+
+// release does the actual work of Release with the lock held.
+func (a *InvokeIDAllocator) release(id int) bool {
+	if !validInvokeID(id) {
+		return false
+	}
+	if _, ok := a.active[id]; !ok {
+		return false
+	}
+	delete(a.active, id)
+	return true
+}
+
+// validInvokeID reports whether id is in the range of Invoke ID.
+func validInvokeID(id int) bool {
+	return id >= MinInvokeID && id <= MaxInvokeID
+}
+
+// ReleaseBy releases the Invoke ID answered by the Component received, i.e., ReturnResultLast,
+// ReturnError or Reject with the Invoke ID derivable, and reports whether it was active.
+// ReturnResultNotLast keeps the Invoke ID active.
+func (a *InvokeIDAllocator) ReleaseBy(c *Component) bool {
+	switch c.Type.Code() {
+	case ReturnResultLast, ReturnError:
+	case Reject:
+		if !c.IsInvokeIDDerivable() {
+			return false
+		}
+	default:
+		return false
+	}
+	id, err := c.invokeID()
+	if err != nil {
+		return false
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.release(id)
+}
+
+// InUse reports whether the Invoke ID is active. It returns false if the Invoke ID is out of range.
+func (a *InvokeIDAllocator) InUse(id int) bool {
+	if !validInvokeID(id) {
+		return false
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	_, ok := a.active[id]
+	return ok
+}
+
+// Len returns the number of the Invoke IDs active.
+func (a *InvokeIDAllocator) Len() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return len(a.active)
+}
@@ -0,0 +1,61 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestInvokeIDAllocator(t *testing.T) {
+	a := tcap.NewInvokeIDAllocator(126)
+	var ids []int
+	for n := 0; n < 3; n++ {
+		id, err := a.Allocate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	verify.Values(t, "wrapped", ids, []int{126, 127, -128})
+	if a.Reserve(127) {
+		t.Error("Reserve: reused the active one")
+	}
+	if a.Reserve(128) {
+		t.Error("Reserve: accepted out of range")
+	}
+
+	if a.ReleaseBy(tcap.NewReturnResult(127, 45, true, false, nil)) {
+		t.Error("ReleaseBy: released by ReturnResultNotLast")
+	}
+	if !a.ReleaseBy(tcap.NewReturnResult(127, 45, true, true, nil)) {
+		t.Error("ReleaseBy: not released by ReturnResultLast")
+	}
+	if !a.ReleaseBy(tcap.NewRejectWithProblem(-128, tcap.ProblemUnrecognizedOperation)) {
+		t.Error("ReleaseBy: not released by Reject")
+	}
+	verify.Values(t, "active", []bool{a.InUse(126), a.InUse(127), a.InUse(-128)}, []bool{true, false, false})
+
+	// the out of range ones are never active.
+	if a.Release(126 + 256) {
+		t.Error("Release: accepted out of range")
+	}
+	verify.Values(t, "out of range", []bool{a.InUse(126 + 256), a.InUse(126)}, []bool{false, true})
+	if !a.Release(126) || a.Release(126) {
+		t.Error("Release: not reported once")
+	}
+
+	for n := 0; n < 256; n++ {
+		if _, err := a.Allocate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := a.Allocate(); !errors.Is(err, tcap.ErrNoInvokeID) {
+		t.Errorf("exhausted: got %v, want %v", err, tcap.ErrNoInvokeID)
+	}
+}
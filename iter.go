@@ -0,0 +1,71 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import "iter"
+
+// Children returns an iterator over the children of the IE.
+//
+// If the children are deferred by Lazy of ParseOptions, they are parsed and stored first.
+// If the children are not parsed otherwise, they are decoded from the Value one at a time as iterated,
+// without being stored in the IE. The iteration stops at the first one that cannot be parsed.
+func (i *IE) Children() iter.Seq[*IE] {
+	return func(yield func(*IE) bool) {
+		if children := i.children(); len(children) > 0 {
+			for _, c := range children {
+				if !yield(c) {
+					return
+				}
+			}
+			return
+		}
+		if i.Tag.Form() != Constructor {
+			return
+		}
+
+		p := &parser{}
+		for b := i.Value; len(b) >= 2; {
+			c := &IE{}
+			n, err := p.parse(c, b)
+			if err != nil || !yield(c) {
+				return
+			}
+			b = b[n:]
+		}
+	}
+}
+
+// All returns an iterator over the Components.
+func (c *Components) All() iter.Seq[*Component] {
+	return func(yield func(*Component) bool) {
+		if c == nil {
+			return
+		}
+		for _, comp := range c.Component {
+			if !yield(comp) {
+				return
+			}
+		}
+	}
+}
+
+// Indexed returns an iterator over the Components with their positions, in wire order.
+func (c *Components) Indexed() iter.Seq2[int, *Component] {
+	return func(yield func(int, *Component) bool) {
+		if c == nil {
+			return
+		}
+		for n, comp := range c.Component {
+			if !yield(n, comp) {
+				return
+			}
+		}
+	}
+}
+
+// AllComponents returns an iterator over the Components in the TCAP message, if any.
+func (t *TCAP) AllComponents() iter.Seq[*Component] {
+	return t.Components.All()
+}
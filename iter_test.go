@@ -0,0 +1,57 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestChildren(t *testing.T) {
+	b := []byte{0x30, 0x08, 0xa1, 0x03, 0x02, 0x01, 0x05, 0x04, 0x01, 0xaa}
+	parsed, err := tcap.ParseAsBER(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unparsed, err := tcap.ParseIE(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, i := range []*tcap.IE{parsed[0], unparsed} {
+		var tags []tcap.Tag
+		for c := range i.Children() {
+			tags = append(tags, c.Tag)
+		}
+		verify.Values(t, "tags", tags, []tcap.Tag{0xa1, 0x04})
+	}
+	if unparsed.IE != nil {
+		t.Errorf("children stored: %v", unparsed.IE)
+	}
+
+	for range unparsed.Children() {
+		break
+	}
+}
+
+func TestAllComponents(t *testing.T) {
+	m := tcap.NewBeginInvoke(0x11111111, 1, 45, nil)
+	m.Components = tcap.NewComponents(
+		tcap.NewInvoke(1, -1, 45, true, nil),
+		tcap.NewInvoke(2, -1, 46, true, nil),
+	)
+
+	var ids []uint8
+	for c := range m.AllComponents() {
+		ids = append(ids, c.InvID())
+	}
+	verify.Values(t, "invoke IDs", ids, []uint8{1, 2})
+
+	for range (&tcap.TCAP{}).AllComponents() {
+		t.Error("iterated over nil Components")
+	}
+}
@@ -0,0 +1,416 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import (
+	"fmt"
+)
+
+// MAP-DialoguePDU alternatives.
+const (
+	mapOpen int = iota
+	mapAccept
+	mapClose
+	mapRefuse
+	mapUserAbort
+	mapProviderAbort
+)
+
+// MAPUserAbortChoice is the map-UserAbortChoice in MAP-UserAbortInfo.
+type MAPUserAbortChoice uint8
+
+// MAPUserAbortChoice definitions.
+const (
+	UserSpecificReason MAPUserAbortChoice = iota
+	UserResourceLimitation
+	ResourceUnavailable
+	ApplicationProcedureCancellation
+)
+
+// String returns the name of MAPUserAbortChoice.
+func (c MAPUserAbortChoice) String() string {
+	switch c {
+	case UserSpecificReason:
+		return "userSpecificReason"
+	case UserResourceLimitation:
+		return "userResourceLimitation"
+	case ResourceUnavailable:
+		return "resourceUnavailable"
+	case ApplicationProcedureCancellation:
+		return "applicationProcedureCancellation"
+	}
+	return fmt.Sprintf("%d", uint8(c))
+}
+
+// ResourceUnavailableReason definitions.
+const (
+	ShortTermResourceLimitation uint8 = iota
+	LongTermResourceLimitation
+)
+
+// ProcedureCancellationReason definitions.
+const (
+	HandoverCancellation uint8 = iota
+	RadioChannelRelease
+	NetworkPathRelease
+	CallRelease
+	AssociatedProcedureFailure
+	TandemDialogueRelease
+	RemoteOperationsFailure
+)
+
+// MAPRefuseReason is the reason in MAP-RefuseInfo.
+type MAPRefuseReason uint8
+
+// MAPRefuseReason definitions.
+const (
+	MAPRefuseNoReasonGiven MAPRefuseReason = iota
+	MAPRefuseInvalidDestinationReference
+	MAPRefuseInvalidOriginatingReference
+)
+
+// String returns the name of MAPRefuseReason.
+func (r MAPRefuseReason) String() string {
+	switch r {
+	case MAPRefuseNoReasonGiven:
+		return "noReasonGiven"
+	case MAPRefuseInvalidDestinationReference:
+		return "invalidDestinationReference"
+	case MAPRefuseInvalidOriginatingReference:
+		return "invalidOriginatingReference"
+	}
+	return fmt.Sprintf("%d", uint8(r))
+}
+
+// MAPProviderAbortReason is the map-ProviderAbortReason in MAP-ProviderAbortInfo.
+type MAPProviderAbortReason uint8
+
+// MAPProviderAbortReason definitions.
+const (
+	AbnormalDialogue MAPProviderAbortReason = iota
+	InvalidPDU
+)
+
+// String returns the name of MAPProviderAbortReason.
+func (r MAPProviderAbortReason) String() string {
+	switch r {
+	case AbnormalDialogue:
+		return "abnormalDialogue"
+	case InvalidPDU:
+		return "invalidPDU"
+	}
+	return fmt.Sprintf("%d", uint8(r))
+}
+
+// MAPOpenInfo is the decoded MAP-OpenInfo.
+type MAPOpenInfo struct {
+	// DestinationReference and OriginationReference are nil if not present.
+	DestinationReference *AddressString
+	OriginationReference *AddressString
+
+	// ExtensionContainer is the encoded ExtensionContainer, or nil if not present.
+	ExtensionContainer []byte
+}
+
+// MAPAcceptInfo is the decoded MAP-AcceptInfo.
+type MAPAcceptInfo struct {
+	// ExtensionContainer is the encoded ExtensionContainer, or nil if not present.
+	ExtensionContainer []byte
+}
+
+// MAPCloseInfo is the decoded MAP-CloseInfo.
+type MAPCloseInfo struct {
+	// ExtensionContainer is the encoded ExtensionContainer, or nil if not present.
+	ExtensionContainer []byte
+}
+
+// MAPUserAbortInfo is the decoded MAP-UserAbortInfo.
+type MAPUserAbortInfo struct {
+	Choice MAPUserAbortChoice
+
+	// Reason is the ResourceUnavailableReason or ProcedureCancellationReason.
+	// It is always zero for UserSpecificReason and UserResourceLimitation.
+	Reason uint8
+}
+
+// String returns MAPUserAbortInfo in human readable format.
+func (m *MAPUserAbortInfo) String() string {
+	switch m.Choice {
+	case ResourceUnavailable, ApplicationProcedureCancellation:
+		return fmt.Sprintf("%s(%d)", m.Choice, m.Reason)
+	}
+	return m.Choice.String()
+}
+
+// MAPRefuseInfo is the decoded MAP-RefuseInfo.
+type MAPRefuseInfo struct {
+	Reason MAPRefuseReason
+
+	// AlternativeApplicationContext is the encoded OID, or nil if not present.
+	AlternativeApplicationContext []byte
+}
+
+// MAPProviderAbortInfo is the decoded MAP-ProviderAbortInfo.
+type MAPProviderAbortInfo struct {
+	Reason MAPProviderAbortReason
+}
+
+// NewMAPOpenInfo creates a new EXTERNAL containing MAP-OpenInfo, to be put in the user information
+// of AARQ. The destinationReference and originationReference are omitted if nil.
+func NewMAPOpenInfo(dest, orig *AddressString) (*IE, error) {
+	var value []byte
+	for n, ref := range []*AddressString{dest, orig} {
+		if ref == nil {
+			continue
+		}
+		i, err := NewAddressString(NewContextSpecificPrimitiveTag(n), ref)
+		if err != nil {
+			return nil, err
+		}
+		value = append(value, encodeTLV(i)...)
+	}
+	return newMAPDialoguePDU(mapOpen, value), nil
+}
+
+// NewMAPAcceptInfo creates a new EXTERNAL containing MAP-AcceptInfo, to be put in
+// the user information of AARE accepting the MAP dialogue.
+func NewMAPAcceptInfo() *IE {
+	return newMAPDialoguePDU(mapAccept, nil)
+}
+
+// NewMAPCloseInfo creates a new EXTERNAL containing MAP-CloseInfo, to be put in
+// the user information of AARE in End.
+func NewMAPCloseInfo() *IE {
+	return newMAPDialoguePDU(mapClose, nil)
+}
+
+// NewMAPUserAbortInfo creates a new EXTERNAL containing MAP-UserAbortInfo, to be put in
+// the user information of ABRT.
+//
+// The reason is the ResourceUnavailableReason or ProcedureCancellationReason, and is
+// ignored for UserSpecificReason and UserResourceLimitation, which are NULL.
+func NewMAPUserAbortInfo(choice MAPUserAbortChoice, reason uint8) *IE {
+	value := []byte{}
+	switch choice {
+	case ResourceUnavailable, ApplicationProcedureCancellation:
+		value = []byte{reason}
+	}
+
+	c := NewIE(NewContextSpecificPrimitiveTag(int(choice)), value)
+	return newMAPDialoguePDU(mapUserAbort, encodeTLV(c))
+}
+
+// NewMAPRefuseInfo creates a new EXTERNAL containing MAP-RefuseInfo, to be put in
+// the user information of AARE.
+//
+// The altACN is the encoded OID of the alternative application context, and omitted if nil.
+func NewMAPRefuseInfo(reason MAPRefuseReason, altACN []byte) *IE {
+	value := encodeTLV(NewIE(NewUniversalPrimitiveTag(10), []byte{uint8(reason)}))
+	if altACN != nil {
+		value = append(value, encodeTLV(NewIE(NewUniversalPrimitiveTag(6), altACN))...)
+	}
+	return newMAPDialoguePDU(mapRefuse, value)
+}
+
+// NewMAPProviderAbortInfo creates a new EXTERNAL containing MAP-ProviderAbortInfo, to be put in
+// the user information of ABRT.
+func NewMAPProviderAbortInfo(reason MAPProviderAbortReason) *IE {
+	return newMAPDialoguePDU(mapProviderAbort, encodeTLV(NewIE(NewUniversalPrimitiveTag(10), []byte{uint8(reason)})))
+}
+
+// newMAPDialoguePDU creates a new EXTERNAL containing the alternative of MAP-DialoguePDU.
+func newMAPDialoguePDU(alt int, value []byte) *IE {
+	pdu := NewIE(NewContextSpecificConstructorTag(alt), value)
+	return NewSingleASN1External(MAPDialogueAS, encodeTLV(pdu))
+}
+
+// mapDialoguePDU returns the elements in the alternative of MAP-DialoguePDU in the user information.
+//
+// InvalidTagError is returned if the MAP-DialoguePDU is another alternative.
+func (d *DialoguePDU) mapDialoguePDU(alt int) ([]*IE, error) {
+	pdu, err := d.mapDialoguePDUIE()
+	if err != nil {
+		return nil, err
+	}
+	if pdu.Tag != NewContextSpecificConstructorTag(alt) {
+		return nil, &InvalidTagError{Tag: pdu.Tag}
+	}
+	if len(pdu.Value) == 0 {
+		return nil, nil
+	}
+	return splitIEs(pdu.Value)
+}
+
+// MAPDialogueType returns the name of the alternative of MAP-DialoguePDU in the user information of
+// DialoguePDU, e.g., "map-open", or empty if there is no MAP-DialoguePDU.
+func (d *DialoguePDU) MAPDialogueType() string {
+	pdu, err := d.mapDialoguePDUIE()
+	if err != nil || pdu.Tag.Class() != ContextSpecific {
+		return ""
+	}
+	switch pdu.Tag.Code() {
+	case mapOpen:
+		return "map-open"
+	case mapAccept:
+		return "map-accept"
+	case mapClose:
+		return "map-close"
+	case mapRefuse:
+		return "map-refuse"
+	case mapUserAbort:
+		return "map-userAbort"
+	case mapProviderAbort:
+		return "map-providerAbort"
+	}
+	return ""
+}
+
+// mapDialoguePDUIE returns the MAP-DialoguePDU in the user information, whose value can be empty,
+// e.g., MAP-AcceptInfo without extensionContainer.
+func (d *DialoguePDU) mapDialoguePDUIE() (*IE, error) {
+	v, err := d.SingleASN1UserInfo(MAPDialogueAS)
+	if err != nil {
+		return nil, err
+	}
+	ies, err := splitIEs(v)
+	if err != nil {
+		return nil, err
+	}
+	if len(ies) == 0 {
+		return nil, ErrEmptyValue
+	}
+	return ies[0], nil
+}
+
+// MAPOpenInfo returns the MAP-OpenInfo in the user information of DialoguePDU.
+func (d *DialoguePDU) MAPOpenInfo() (*MAPOpenInfo, error) {
+	ies, err := d.mapDialoguePDU(mapOpen)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &MAPOpenInfo{}
+	for _, i := range ies {
+		switch i.Tag {
+		case NewContextSpecificPrimitiveTag(0):
+			if m.DestinationReference, err = i.AddressString(); err != nil {
+				return nil, err
+			}
+		case NewContextSpecificPrimitiveTag(1):
+			if m.OriginationReference, err = i.AddressString(); err != nil {
+				return nil, err
+			}
+		case NewUniversalConstructorTag(16):
+			m.ExtensionContainer = encodeTLV(i)
+		}
+	}
+	return m, nil
+}
+
+// MAPAcceptInfo returns the MAP-AcceptInfo in the user information of DialoguePDU.
+func (d *DialoguePDU) MAPAcceptInfo() (*MAPAcceptInfo, error) {
+	ies, err := d.mapDialoguePDU(mapAccept)
+	if err != nil {
+		return nil, err
+	}
+	return &MAPAcceptInfo{ExtensionContainer: extensionContainer(ies)}, nil
+}
+
+// MAPCloseInfo returns the MAP-CloseInfo in the user information of DialoguePDU.
+func (d *DialoguePDU) MAPCloseInfo() (*MAPCloseInfo, error) {
+	ies, err := d.mapDialoguePDU(mapClose)
+	if err != nil {
+		return nil, err
+	}
+	return &MAPCloseInfo{ExtensionContainer: extensionContainer(ies)}, nil
+}
+
+// extensionContainer returns the encoded ExtensionContainer in the elements, or nil if not present.
+func extensionContainer(ies []*IE) []byte {
+	for _, i := range ies {
+		if i.Tag == NewUniversalConstructorTag(16) {
+			return encodeTLV(i)
+		}
+	}
+	return nil
+}
+
+// MAPUserAbortInfo returns the MAP-UserAbortInfo in the user information of DialoguePDU.
+func (d *DialoguePDU) MAPUserAbortInfo() (*MAPUserAbortInfo, error) {
+	ies, err := d.mapDialoguePDU(mapUserAbort)
+	if err != nil {
+		return nil, err
+	}
+	if len(ies) == 0 {
+		return nil, ErrEmptyValue
+	}
+
+	return mapUserAbortChoice.Decode(ies[0])
+}
+
+// mapUserAbortChoice is the map-UserAbortChoice in MAP-UserAbortInfo.
+var mapUserAbortChoice = NewChoice[*MAPUserAbortInfo]().
+	Register(NewContextSpecificPrimitiveTag(int(UserSpecificReason)), mapUserAbortNull(UserSpecificReason)).
+	Register(NewContextSpecificPrimitiveTag(int(UserResourceLimitation)), mapUserAbortNull(UserResourceLimitation)).
+	Register(NewContextSpecificPrimitiveTag(int(ResourceUnavailable)), mapUserAbortReason(ResourceUnavailable)).
+	Register(NewContextSpecificPrimitiveTag(int(ApplicationProcedureCancellation)), mapUserAbortReason(ApplicationProcedureCancellation))
+
+// mapUserAbortNull returns the decoder of the alternative of map-UserAbortChoice which is NULL.
+func mapUserAbortNull(choice MAPUserAbortChoice) func(*IE) (*MAPUserAbortInfo, error) {
+	return func(*IE) (*MAPUserAbortInfo, error) {
+		return &MAPUserAbortInfo{Choice: choice}, nil
+	}
+}
+
+// mapUserAbortReason returns the decoder of the alternative of map-UserAbortChoice which carries a reason.
+func mapUserAbortReason(choice MAPUserAbortChoice) func(*IE) (*MAPUserAbortInfo, error) {
+	return func(i *IE) (*MAPUserAbortInfo, error) {
+		if len(i.Value) == 0 {
+			return nil, ErrEmptyValue
+		}
+		return &MAPUserAbortInfo{Choice: choice, Reason: i.Value[len(i.Value)-1]}, nil
+	}
+}
+
+// MAPRefuseInfo returns the MAP-RefuseInfo in the user information of DialoguePDU.
+func (d *DialoguePDU) MAPRefuseInfo() (*MAPRefuseInfo, error) {
+	ies, err := d.mapDialoguePDU(mapRefuse)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &MAPRefuseInfo{}
+	found := false
+	for _, i := range ies {
+		switch i.Tag {
+		case NewUniversalPrimitiveTag(10):
+			if len(i.Value) == 0 {
+				return nil, ErrEmptyValue
+			}
+			m.Reason = MAPRefuseReason(i.Value[len(i.Value)-1])
+			found = true
+		case NewUniversalPrimitiveTag(6):
+			m.AlternativeApplicationContext = i.Value
+		}
+	}
+	if !found {
+		return nil, ErrEmptyValue
+	}
+	return m, nil
+}
+
+// MAPProviderAbortInfo returns the MAP-ProviderAbortInfo in the user information of DialoguePDU.
+func (d *DialoguePDU) MAPProviderAbortInfo() (*MAPProviderAbortInfo, error) {
+	ies, err := d.mapDialoguePDU(mapProviderAbort)
+	if err != nil {
+		return nil, err
+	}
+	for _, i := range ies {
+		if i.Tag == NewUniversalPrimitiveTag(10) && len(i.Value) > 0 {
+			return &MAPProviderAbortInfo{Reason: MAPProviderAbortReason(i.Value[len(i.Value)-1])}, nil
+		}
+	}
+	return nil, ErrEmptyValue
+}
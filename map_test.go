@@ -0,0 +1,193 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestUserAbort(t *testing.T) {
+	m := tcap.NewUserAbort(0x11111111, tcap.NewMAPUserAbortInfo(tcap.ApplicationProcedureCancellation, 2))
+
+	b, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "encoded", b, []byte{
+		0x67, 0x2e, // Abort
+		0x49, 0x04, 0x11, 0x11, 0x11, 0x11, // DTID
+		0x6b, 0x26, 0x28, 0x24, // Dialogue Portion
+		0x06, 0x07, 0x00, 0x11, 0x86, 0x05, 0x01, 0x01, 0x01,
+		0xa0, 0x19, 0x64, 0x17, // ABRT
+		0x80, 0x01, 0x00, // abort-source: dialogue-service-user
+		0xbe, 0x12, 0x28, 0x10, // user-information
+		0x06, 0x07, 0x04, 0x00, 0x00, 0x01, 0x01, 0x01, 0x01,
+		0xa0, 0x05, 0xa4, 0x03, 0x83, 0x01, 0x02, // map-userAbort: applicationProcedureCancellation
+	})
+
+	parsed, err := tcap.Parse(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := parsed.Dialogue.DialoguePDU.DialogueType(), "ABRT"; got != want {
+		t.Errorf("dialogue: got %s, want %s", got, want)
+	}
+	if got := parsed.Transaction.PAbortCause; got != nil {
+		t.Errorf("PAbortCause: got %v, want nil", got)
+	}
+}
+
+func TestMAPDialogueInfo(t *testing.T) {
+	abrt := tcap.NewUserAbort(1, tcap.NewMAPUserAbortInfo(tcap.ApplicationProcedureCancellation, tcap.CallRelease)).Dialogue.DialoguePDU
+	ua, err := abrt.MAPUserAbortInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ua.String(), "applicationProcedureCancellation(3)"; got != want {
+		t.Errorf("MAPUserAbortInfo: got %s, want %s", got, want)
+	}
+	if _, err := abrt.MAPRefuseInfo(); err == nil {
+		t.Error("MAPRefuseInfo from user abort: got nil error")
+	}
+
+	user := tcap.NewUserAbort(1, tcap.NewMAPUserAbortInfo(tcap.UserSpecificReason, 0)).Dialogue.DialoguePDU
+	if ua, err := user.MAPUserAbortInfo(); err != nil || ua.Choice != tcap.UserSpecificReason {
+		t.Errorf("userSpecificReason: got %v, %v", ua, err)
+	}
+
+	unknown := tcap.NewUserAbort(1, tcap.NewMAPUserAbortInfo(tcap.MAPUserAbortChoice(7), 0)).Dialogue.DialoguePDU
+	var tagErr *tcap.InvalidTagError
+	if _, err := unknown.MAPUserAbortInfo(); !errors.As(err, &tagErr) {
+		t.Errorf("unknown map-UserAbortChoice: got %v, want InvalidTagError", err)
+	}
+
+	altACN := []byte{0x04, 0x00, 0x00, 0x01, 0x00, 0x14, 0x02}
+	aare := tcap.NewAARE(1, tcap.ShortMsgMTRelayContext, 3, tcap.RejectPerm, tcap.DialogueServiceUser, tcap.Null)
+	aare.SetUserInformation(tcap.NewMAPRefuseInfo(tcap.MAPRefuseInvalidDestinationReference, altACN))
+	ri, err := aare.MAPRefuseInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ri.Reason.String(), "invalidDestinationReference"; got != want {
+		t.Errorf("MAPRefuseInfo: got %s, want %s", got, want)
+	}
+	verify.Values(t, "alternativeApplicationContext", ri.AlternativeApplicationContext, altACN)
+
+	pabrt := tcap.NewABRT(uint8(tcap.AbortDialogueServiceProvider))
+	pabrt.SetUserInformation(tcap.NewMAPProviderAbortInfo(tcap.InvalidPDU))
+	pa, err := pabrt.MAPProviderAbortInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := pa.Reason.String(), "invalidPDU"; got != want {
+		t.Errorf("MAPProviderAbortInfo: got %s, want %s", got, want)
+	}
+}
+
+func TestACNNotSupported(t *testing.T) {
+	for _, isEnd := range []bool{false, true} {
+		m := tcap.NewACNNotSupported(0x11111111, tcap.ShortMsgMTRelayContext, 2, isEnd)
+		b, err := m.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed, err := tcap.Parse(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ctx, ver, ok := parsed.IsACNNotSupported()
+		if !ok {
+			t.Fatalf("isEnd=%v: not recognized", isEnd)
+		}
+		if ctx != tcap.ShortMsgMTRelayContext || ver != 2 {
+			t.Errorf("isEnd=%v: got %d-v%d, want %d-v2", isEnd, ctx, ver, tcap.ShortMsgMTRelayContext)
+		}
+	}
+
+	accepted := tcap.NewEndReturnResultWithDialogue(0x11111111, tcap.DialogueAsID, tcap.ShortMsgMTRelayContext, 3, 0, 44, true, nil)
+	if _, _, ok := accepted.IsACNNotSupported(); ok {
+		t.Error("accepted: got true, want false")
+	}
+}
+
+func TestMAPOpen(t *testing.T) {
+	dest, orig := tcap.NewE164Address("819012345678"), tcap.NewE164Address("447700900123")
+	open, err := tcap.NewMAPOpenInfo(dest, orig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	aarq := tcap.NewAARQ(1, tcap.ShortMsgMTRelayContext, 3)
+	aarq.SetUserInformation(open)
+
+	m := tcap.NewBeginMessage(tcap.WithOTID(0x11111111), tcap.WithDialoguePDU(aarq))
+	b, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := tcap.Parse(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdu := parsed.Dialogue.DialoguePDU
+	verify.Values(t, "MAP dialogue type", pdu.MAPDialogueType(), "map-open")
+	oi, err := pdu.MAPOpenInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "MAP-OpenInfo", oi, &tcap.MAPOpenInfo{DestinationReference: dest, OriginationReference: orig})
+
+	// both references are optional.
+	empty, err := tcap.NewMAPOpenInfo(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	aarq.SetUserInformation(empty)
+	if oi, err := aarq.MAPOpenInfo(); err != nil || oi.DestinationReference != nil || oi.OriginationReference != nil {
+		t.Errorf("empty MAP-OpenInfo: got %v, %v", oi, err)
+	}
+
+	aare := tcap.NewAARE(1, tcap.ShortMsgMTRelayContext, 3, tcap.Accepted, tcap.DialogueServiceUser, tcap.Null)
+	aare.SetUserInformation(tcap.NewMAPAcceptInfo())
+	verify.Values(t, "accept type", aare.MAPDialogueType(), "map-accept")
+	if _, err := aare.MAPAcceptInfo(); err != nil {
+		t.Errorf("MAP-AcceptInfo: %v", err)
+	}
+	if _, err := aare.MAPCloseInfo(); err == nil {
+		t.Error("MAP-CloseInfo from map-accept: got nil error")
+	}
+
+	aare.SetUserInformation(tcap.NewMAPCloseInfo())
+	if _, err := aare.MAPCloseInfo(); err != nil {
+		t.Errorf("MAP-CloseInfo: %v", err)
+	}
+}
+
+func TestMAPDialogueElements(t *testing.T) {
+	aare := tcap.NewAARE(1, tcap.ShortMsgMTRelayContext, 3, tcap.Accepted, tcap.DialogueServiceUser, tcap.Null)
+
+	// map-accept with an element of high tag number before the extensionContainer.
+	aare.SetUserInformation(tcap.NewSingleASN1External(tcap.MAPDialogueAS, []byte{
+		0xa1, 0x09, 0x9f, 0x1f, 0x01, 0xaa, 0x30, 0x03, 0x04, 0x01, 0xbb,
+	}))
+	ai, err := aare.MAPAcceptInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "extensionContainer", ai.ExtensionContainer, []byte{0x30, 0x03, 0x04, 0x01, 0xbb})
+
+	// the extensionContainer is longer than map-accept.
+	aare.SetUserInformation(tcap.NewSingleASN1External(tcap.MAPDialogueAS, []byte{
+		0xa1, 0x05, 0x30, 0x05, 0x04, 0x01, 0xbb,
+	}))
+	if _, err := aare.MAPAcceptInfo(); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("truncated: got %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+}
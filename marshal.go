@@ -0,0 +1,111 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import (
+	"bytes"
+	"sort"
+)
+
+// MarshalOptions specifies how IEs and messages are encoded.
+//
+// The zero value encodes in the same way as MarshalBinary.
+type MarshalOptions struct {
+	// IndefiniteLength encodes the constructed elements in indefinite-length form,
+	// i.e., the length octet is 0x80 and the contents are followed by end-of-contents octets.
+	// The constructed elements whose contents cannot be parsed are left in definite-length form.
+	IndefiniteLength bool
+
+	// DER encodes in the canonical form of Distinguished Encoding Rules, so that the same
+	// message is encoded into the same bytes. The constructed elements are encoded in
+	// definite-length form, the elements in SET are sorted by tag, and BOOLEAN TRUE is 0xff.
+	// It takes precedence over IndefiniteLength.
+	DER bool
+}
+
+// MarshalWithOptions returns the byte sequence generated from a IE instance with the options.
+func (i *IE) MarshalWithOptions(o MarshalOptions) ([]byte, error) {
+	return o.appendIE(nil, i), nil
+}
+
+// MarshalWithOptions returns the byte sequence generated from a TCAP instance with the options.
+func (t *TCAP) MarshalWithOptions(o MarshalOptions) ([]byte, error) {
+	b, err := t.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	if o == (MarshalOptions{}) {
+		return b, nil
+	}
+
+	ies, err := splitIEs(b)
+	if err != nil {
+		return nil, err
+	}
+	var out []byte
+	for _, i := range ies {
+		out = o.appendIE(out, i)
+	}
+	return out, nil
+}
+
+// appendIE appends the encoding of the IE with the options to b.
+func (o MarshalOptions) appendIE(b []byte, i *IE) []byte {
+	if i.Tag.Form() != Constructor {
+		if o.DER && i.Tag == NewUniversalPrimitiveTag(1) && len(i.Value) == 1 && i.Value[0] != 0 {
+			return append(b, encodeTLV(NewIE(i.Tag, []byte{0xff}))...)
+		}
+		return append(b, encodeTLV(i)...)
+	}
+	if !o.IndefiniteLength && !o.DER {
+		return append(b, encodeTLV(i)...)
+	}
+
+	children, err := splitIEs(i.Value)
+	if err != nil {
+		return append(b, encodeTLV(i)...)
+	}
+
+	if o.DER {
+		if i.Tag == NewUniversalConstructorTag(17) {
+			sortByTag(children)
+		}
+		var value []byte
+		for _, c := range children {
+			value = o.appendIE(value, c)
+		}
+		return append(b, encodeTLV(NewIE(i.Tag, value))...)
+	}
+
+	tag := make([]byte, i.Tag.MarshalLen())
+	_, _ = i.Tag.MarshalTo(tag)
+	b = append(append(b, tag...), 0x80)
+	for _, c := range children {
+		b = o.appendIE(b, c)
+	}
+	return append(b, 0x00, 0x00)
+}
+
+// sortByTag sorts the elements in SET in the canonical order of tags, i.e., by class and then by number.
+// The ones with the same tag (SET OF) are sorted by their encodings.
+func sortByTag(ies []*IE) {
+	sort.SliceStable(ies, func(a, b int) bool {
+		x, y := ies[a].Tag, ies[b].Tag
+		if x.Class() != y.Class() {
+			return x.Class() < y.Class()
+		}
+		if x.Code() != y.Code() {
+			return x.Code() < y.Code()
+		}
+		return bytes.Compare(encodeTLV(ies[a]), encodeTLV(ies[b])) < 0
+	})
+}
+
+// splitIEs parses the byte sequence as multiple IEs without parsing their children.
+//
+// Unlike ParseMultiIEs, the IEs with empty value (e.g., NULL) are accepted.
+func splitIEs(b []byte) ([]*IE, error) {
+	return (&parser{}).parseIEs(b, 2)
+}
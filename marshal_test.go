@@ -0,0 +1,121 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestMarshalIndefiniteLength(t *testing.T) {
+	i := tcap.NewIE(tcap.NewUniversalConstructorTag(16), []byte{0xa1, 0x03, 0x02, 0x01, 0x05, 0x05, 0x00})
+	b, err := i.MarshalWithOptions(tcap.MarshalOptions{IndefiniteLength: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "IE", b, []byte{0x30, 0x80, 0xa1, 0x80, 0x02, 0x01, 0x05, 0x00, 0x00, 0x05, 0x00, 0x00, 0x00})
+
+	m := tcap.NewBeginInvoke(0x11111111, 1, 45, []byte{0x80, 0x01, 0xaa})
+	definite, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	indefinite, err := m.MarshalWithOptions(tcap.MarshalOptions{IndefiniteLength: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := indefinite[:2], []byte{0x62, 0x80}; !verify.Values(t, "header", got, want) {
+		t.Fail()
+	}
+
+	want, err := tcap.ParseAsBER(definite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := tcap.ParseAsBER(indefinite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "Tag", got[0].Tag, want[0].Tag)
+	verify.Values(t, "children", len(got[0].IE), len(want[0].IE))
+}
+
+func TestMarshalDER(t *testing.T) {
+	cases := []struct {
+		description string
+		serialized  []byte
+		der         []byte
+	}{
+		{
+			"Indefinite",
+			[]byte{0x30, 0x80, 0x02, 0x01, 0x05, 0x00, 0x00},
+			[]byte{0x30, 0x03, 0x02, 0x01, 0x05},
+		},
+		{
+			"LongFormLength",
+			[]byte{0x30, 0x81, 0x03, 0x02, 0x01, 0x05},
+			[]byte{0x30, 0x03, 0x02, 0x01, 0x05},
+		},
+		{
+			"Set",
+			[]byte{0x31, 0x0a, 0x82, 0x01, 0x02, 0xa1, 0x00, 0x80, 0x01, 0x01, 0x80, 0x00},
+			[]byte{0x31, 0x0a, 0x80, 0x00, 0x80, 0x01, 0x01, 0xa1, 0x00, 0x82, 0x01, 0x02},
+		},
+		{
+			"Boolean",
+			[]byte{0x30, 0x03, 0x01, 0x01, 0x01},
+			[]byte{0x30, 0x03, 0x01, 0x01, 0xff},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			i, err := tcap.ParseIE(c.serialized)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := i.MarshalWithOptions(tcap.MarshalOptions{DER: true, IndefiniteLength: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			verify.Values(t, "DER", b, c.der)
+		})
+	}
+}
+
+func TestAppendBinary(t *testing.T) {
+	m := tcap.NewBeginInvoke(0x11111111, 1, 45, []byte{0x80, 0x01, 0xaa})
+	want, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prefix := []byte{0xde, 0xad}
+	b, err := m.AppendBinary(append(make([]byte, 0, 4), prefix...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "AppendBinary", b, append(prefix, want...))
+
+	var buf bytes.Buffer
+	n, err := m.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(len(want)) {
+		t.Errorf("WriteTo: got %d bytes, want %d", n, len(want))
+	}
+	verify.Values(t, "WriteTo", buf.Bytes(), want)
+
+	i := tcap.NewIE(tcap.NewUniversalPrimitiveTag(4), []byte{0x01, 0x02})
+	b, err = i.AppendBinary(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "IE", b, []byte{0x04, 0x02, 0x01, 0x02})
+}
@@ -0,0 +1,106 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import "encoding/binary"
+
+// MessageOption configures a TCAP created by NewUnidirectionalMessage, NewBeginMessage,
+// NewContinueMessage, NewEndMessage or NewAbortMessage.
+type MessageOption func(*TCAP)
+
+// WithOTID sets the Originating Transaction ID.
+func WithOTID(otid uint32) MessageOption {
+	return func(t *TCAP) {
+		t.Transaction.OrigTransactionID = NewIE(NewApplicationWidePrimitiveTag(8), make([]byte, 4))
+		binary.BigEndian.PutUint32(t.Transaction.OrigTransactionID.Value, otid)
+	}
+}
+
+// WithDTID sets the Destination Transaction ID.
+func WithDTID(dtid uint32) MessageOption {
+	return func(t *TCAP) {
+		t.Transaction.DestTransactionID = NewIE(NewApplicationWidePrimitiveTag(9), make([]byte, 4))
+		binary.BigEndian.PutUint32(t.Transaction.DestTransactionID.Value, dtid)
+	}
+}
+
+// WithAbortCause sets the P-Abort Cause, which makes the Abort a TC-P-ABORT.
+func WithAbortCause(cause uint8) MessageOption {
+	return func(t *TCAP) {
+		t.Transaction.PAbortCause = NewIE(NewApplicationWidePrimitiveTag(10), []byte{cause})
+	}
+}
+
+// WithDialogue sets the Dialogue Portion.
+func WithDialogue(d *Dialogue) MessageOption {
+	return func(t *TCAP) {
+		t.Dialogue = d
+	}
+}
+
+// WithDialoguePDU sets the Dialogue Portion with the DialoguePDU (e.g., created by NewAARQ) as Dialogue-As-ID.
+func WithDialoguePDU(pdu *DialoguePDU) MessageOption {
+	return func(t *TCAP) {
+		t.Dialogue = NewDialogue(DialogueAsID, 1, pdu, []byte{})
+	}
+}
+
+// WithUnidialoguePDU sets the Dialogue Portion with the AUDT as Unidialogue-As-Id.
+func WithUnidialoguePDU(pdu *DialoguePDU) MessageOption {
+	return func(t *TCAP) {
+		t.Dialogue = NewDialogue(UnidialogueAsID, 1, pdu, []byte{})
+	}
+}
+
+// WithComponents appends the Components to the Component Portion.
+func WithComponents(comps ...*Component) MessageOption {
+	return func(t *TCAP) {
+		if t.Components != nil {
+			comps = append(append([]*Component{}, t.Components.Component...), comps...)
+		}
+		t.Components = NewComponents(comps...)
+	}
+}
+
+// NewUnidirectionalMessage creates a new TCAP of type Transaction=Unidirectional with the options,
+// e.g., NewUnidirectionalMessage(WithUnidialoguePDU(NewAUDT(1, ctx, ctxver)), WithComponents(invoke)).
+func NewUnidirectionalMessage(opts ...MessageOption) *TCAP {
+	return newMessage(NewUnidirectional([]byte{}), opts)
+}
+
+// NewBeginMessage creates a new TCAP of type Transaction=Begin with the options,
+// e.g., NewBeginMessage(WithOTID(otid), WithDialoguePDU(NewAARQ(1, ctx, ctxver)), WithComponents(invoke)).
+func NewBeginMessage(opts ...MessageOption) *TCAP {
+	return newMessage(NewBegin(0, []byte{}), opts)
+}
+
+// NewContinueMessage creates a new TCAP of type Transaction=Continue with the options.
+func NewContinueMessage(opts ...MessageOption) *TCAP {
+	return newMessage(NewContinue(0, 0, []byte{}), opts)
+}
+
+// NewEndMessage creates a new TCAP of type Transaction=End with the options.
+func NewEndMessage(opts ...MessageOption) *TCAP {
+	return newMessage(NewEnd(0, []byte{}), opts)
+}
+
+// NewAbortMessage creates a new TCAP of type Transaction=Abort with the options.
+// It is TC-U-ABORT unless WithAbortCause is given.
+func NewAbortMessage(opts ...MessageOption) *TCAP {
+	tx := NewAbort(0, 0, []byte{})
+	tx.PAbortCause = nil
+	return newMessage(tx, opts)
+}
+
+// newMessage creates a new TCAP with the Transaction Portion, and applies the options.
+func newMessage(tx *Transaction, opts []MessageOption) *TCAP {
+	t := &TCAP{Transaction: tx}
+	for _, opt := range opts {
+		opt(t)
+	}
+	t.SetLength()
+
+	return t
+}
@@ -0,0 +1,223 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestMessageOptions(t *testing.T) {
+	payload := []byte{0x04, 0x01, 0xaa}
+
+	cases := []struct {
+		description string
+		got, want   *tcap.TCAP
+	}{
+		{
+			"Begin",
+			tcap.NewBeginMessage(
+				tcap.WithOTID(0x11111111),
+				tcap.WithDialoguePDU(tcap.NewAARQ(1, tcap.NetworkUnstructuredSsContext, 2)),
+				tcap.WithComponents(tcap.NewInvoke(0, -1, 59, true, payload)),
+			),
+			tcap.NewBeginInvokeWithDialogue(0x11111111, tcap.DialogueAsID, tcap.NetworkUnstructuredSsContext, 2, 0, 59, payload),
+		},
+		{
+			"Continue",
+			tcap.NewContinueMessage(
+				tcap.WithOTID(0x22222222),
+				tcap.WithDTID(0x11111111),
+				tcap.WithComponents(tcap.NewInvoke(1, -1, 60, true, payload)),
+			),
+			tcap.NewContinueInvoke(0x22222222, 0x11111111, 1, 60, payload),
+		},
+		{
+			"End",
+			tcap.NewEndMessage(
+				tcap.WithDTID(0x11111111),
+				tcap.WithComponents(tcap.NewReturnResult(1, 59, true, true, payload)),
+			),
+			tcap.NewEndReturnResult(0x11111111, 1, 59, true, payload),
+		},
+		{
+			"U-Abort",
+			tcap.NewAbortMessage(
+				tcap.WithDTID(0x11111111),
+				tcap.WithDialoguePDU(tcap.NewABRT(uint8(tcap.AbortDialogueServiceUser))),
+			),
+			tcap.NewUserAbort(0x11111111),
+		},
+		{
+			"P-Abort",
+			tcap.NewAbortMessage(tcap.WithDTID(0x11111111), tcap.WithAbortCause(tcap.ResourceLimitation)),
+			&tcap.TCAP{Transaction: tcap.NewAbort(0x11111111, tcap.ResourceLimitation, []byte{})},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			got, err := c.got.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			c.want.SetLength()
+			want, err := c.want.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			verify.Values(t, "encoded", got, want)
+		})
+	}
+}
+
+func TestUnidirectional(t *testing.T) {
+	payload := []byte{0x04, 0x01, 0xaa}
+
+	m := tcap.NewUnidirectionalMessage(
+		tcap.WithUnidialoguePDU(tcap.NewAUDT(1, tcap.NetworkUnstructuredSsContext, 2)),
+		tcap.WithComponents(tcap.NewInvoke(0, -1, 60, true, payload)),
+	)
+	b, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := tcap.NewUnidirectionalInvoke(tcap.NetworkUnstructuredSsContext, 2, 0, 60, payload).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "encoded", b, want)
+	verify.Values(t, "tag", b[0], uint8(0x61))
+
+	parsed, err := tcap.Parse(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "type", parsed.Transaction.MessageTypeString(), "Unidirectional")
+	if !parsed.Dialogue.IsUnidialogue() {
+		t.Error("not Unidialogue-As-Id")
+	}
+	verify.Values(t, "AUDT", parsed.Dialogue.DialoguePDU.Type.Code(), tcap.AUDT)
+	verify.Values(t, "context", parsed.AppContextName(), "networkUnstructuredSsContext")
+	verify.Values(t, "opcode", parsed.OpCode(), []uint8{60})
+}
+
+func TestAUDT(t *testing.T) {
+	ext := &tcap.External{
+		DirectReference: tcap.OID{1, 3, 6, 1, 4, 1, 99999},
+		Encoding:        tcap.OctetAligned,
+		Data:            []byte{0x01},
+	}
+	audt, err := tcap.NewAUDTWithContext(tcap.MAPContext(tcap.IstAlertingContext, 3), ext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := tcap.NewUnidirectionalMessage(
+		tcap.WithUnidialoguePDU(audt),
+		tcap.WithComponents(tcap.NewInvoke(0, -1, 87, true, []byte{0x04, 0x01, 0xaa})),
+	)
+	b, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := tcap.Parse(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := parsed.AUDT()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ac, err := got.ApplicationContext()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "application context", ac.String(), "istAlertingContext-v3")
+	externals, err := got.Externals()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "externals", externals, []*tcap.External{ext})
+	verify.Values(t, "summary", fmt.Sprintf("%v", parsed.Dialogue), "Dialogue{AUDT(istAlertingContext-v3)}")
+
+	if _, err := tcap.NewBeginDialogue(1, tcap.IstAlertingContext, 3).AUDT(); !errors.Is(err, tcap.ErrNotUnidialogue) {
+		t.Errorf("Begin: got %v, want %v", err, tcap.ErrNotUnidialogue)
+	}
+}
+
+func TestPAbort(t *testing.T) {
+	b, err := tcap.NewAbortMessage(tcap.WithDTID(0x11111111), tcap.WithAbortCause(tcap.UnrecognizedTransactionID)).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := tcap.Parse(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cause, ok := parsed.PAbort()
+	if !ok {
+		t.Fatal("not P-Abort")
+	}
+	verify.Values(t, "cause", cause, tcap.PAbortCause(tcap.UnrecognizedTransactionID))
+	verify.Values(t, "name", cause.String(), "UnrecognizedTransactionID")
+
+	b, err = tcap.NewUserAbort(0x11111111).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed, err = tcap.Parse(b); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := parsed.PAbort(); ok {
+		t.Error("U-Abort: got P-Abort")
+	}
+}
+
+func TestComponentlessMessages(t *testing.T) {
+	cases := []struct {
+		description string
+		m           *tcap.TCAP
+		want        []byte
+	}{
+		{
+			"empty Continue",
+			tcap.NewEmptyContinue(0x22222222, 0x11111111),
+			[]byte{0x65, 0x0c, 0x48, 0x04, 0x22, 0x22, 0x22, 0x22, 0x49, 0x04, 0x11, 0x11, 0x11, 0x11},
+		},
+		{"Begin with Dialogue", tcap.NewBeginDialogue(0x11111111, tcap.NetworkUnstructuredSsContext, 2), nil},
+		{"Continue with Dialogue", tcap.NewContinueDialogue(0x22222222, 0x11111111, tcap.NetworkUnstructuredSsContext, 2), nil},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			b, err := c.m.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.want != nil {
+				verify.Values(t, "encoded", b, c.want)
+			}
+			if got, want := int(b[1]), len(b)-2; got != want {
+				t.Errorf("length: got %d, want %d", got, want)
+			}
+
+			parsed, err := tcap.Parse(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if parsed.Components != nil {
+				t.Errorf("got Components: %v", parsed.Components)
+			}
+			if (parsed.Dialogue != nil) != (c.m.Dialogue != nil) {
+				t.Errorf("Dialogue: got %v, want %v", parsed.Dialogue, c.m.Dialogue)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import "errors"
+
+// ErrNotAARQ is returned when the DialoguePDU given is not an AARQ.
+var ErrNotAARQ = errors.New("tcap: not an AARQ")
+
+// NegotiateAARE builds the AARE in response to the AARQ received, with the application contexts
+// supported by this side.
+//
+// If the AARQ does not propose version1 in protocol-version, the AARE is reject-permanent with
+// no-common-dialogue-portion of dialogue-service-provider. If the application context proposed is
+// supported, the AARE accepts it. Otherwise, the AARE is reject-permanent with
+// application-context-name-not-supported, and has the highest version of the same MAP context
+// supported that is lower than the one proposed, so that the peer can fall back to it, or the one
+// proposed if there is no such version.
+func NegotiateAARE(aarq *DialoguePDU, supported ...ApplicationContext) (aare *DialoguePDU, accepted bool, err error) {
+	if aarq == nil || aarq.Type.Code() != AARQ {
+		return nil, false, ErrNotAARQ
+	}
+	proposed, err := aarq.ApplicationContext()
+	if err != nil {
+		return nil, false, err
+	}
+
+	aare = NewAAREWithResult(1, 0, 0, AssociateResult(RejectPerm), SourceDiagnostic{DialogueServiceUser, ApplicationContextNameNotSupported})
+	if !supportsVersion1(aarq.ProtocolVersion) {
+		aare.SetResult(AssociateResult(RejectPerm), SourceDiagnostic{DialogueServiceProvider, NoCommonDialoguePortion})
+		return aare, false, aare.SetApplicationContext(proposed)
+	}
+
+	alt := proposed
+	ctx, ver, isMAP := proposed.Context()
+	var altVer uint8
+	for _, ac := range supported {
+		if ac.Equal(proposed) {
+			aare.SetResult(AssociateResult(Accepted), SourceDiagnostic{DialogueServiceUser, Null})
+			return aare, true, aare.SetApplicationContext(proposed)
+		}
+		if c, v, ok := ac.Context(); isMAP && ok && c == ctx && v < ver && v > altVer {
+			alt, altVer = ac, v
+		}
+	}
+	return aare, false, aare.SetApplicationContext(alt)
+}
+
+// AcceptDialogue returns the Dialogue Portion with the AARE accepting the application context
+// proposed in the AARQ of the request, or nil if the request does not have AARQ.
+//
+// It is used to answer a Begin with an End, so that the peer that proposed a dialogue
+// receives the components instead of treating the End as a protocol error.
+func AcceptDialogue(req *TCAP) *Dialogue {
+	d := req.Dialogue
+	if d == nil || d.DialoguePDU == nil || d.DialoguePDU.Type.Code() != AARQ {
+		return nil
+	}
+
+	aare := NewAARE(1, 0, 0, Accepted, DialogueServiceUser, Null)
+	if acn := d.DialoguePDU.ApplicationContextName; acn != nil {
+		aare.ApplicationContextName = NewIE(acn.Tag, append([]byte{}, acn.Value...))
+	}
+	aare.SetLength()
+	return NewDialogue(DialogueAsID, 1, aare, []byte{})
+}
+
+// supportsVersion1 reports whether the protocol-version has version1, which is the default if absent.
+func supportsVersion1(pv *IE) bool {
+	if pv == nil {
+		return true
+	}
+	s, err := pv.BitString()
+	if err != nil {
+		// some implementations omit the unused bits octet.
+		return len(pv.Value) > 0 && pv.Value[len(pv.Value)-1]&0x80 != 0
+	}
+	return s.At(0)
+}
@@ -0,0 +1,83 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestNegotiateAARE(t *testing.T) {
+	supported := []tcap.ApplicationContext{tcap.ShortMsgMTRelayV2, tcap.NetworkLocUpV1, tcap.NetworkLocUpV2}
+
+	tests := []struct {
+		name     string
+		aarq     *tcap.DialoguePDU
+		accepted bool
+		diag     tcap.SourceDiagnostic
+		ac       tcap.ApplicationContext
+	}{
+		{
+			name:     "accept",
+			aarq:     tcap.NewAARQ(1, tcap.ShortMsgMTRelayContext, 2),
+			accepted: true,
+			diag:     tcap.SourceDiagnostic{Source: tcap.DialogueServiceUser, Reason: tcap.Null},
+			ac:       tcap.ShortMsgMTRelayV2,
+		},
+		{
+			name: "fallback",
+			aarq: tcap.NewAARQ(1, tcap.NetworkLocUpContext, 3),
+			diag: tcap.SourceDiagnostic{Source: tcap.DialogueServiceUser, Reason: tcap.ApplicationContextNameNotSupported},
+			ac:   tcap.NetworkLocUpV2,
+		},
+		{
+			name: "unsupported",
+			aarq: tcap.NewAARQ(1, tcap.ShortMsgGatewayContext, 3),
+			diag: tcap.SourceDiagnostic{Source: tcap.DialogueServiceUser, Reason: tcap.ApplicationContextNameNotSupported},
+			ac:   tcap.ShortMsgGatewayV3,
+		},
+		{
+			name: "protocol version",
+			aarq: tcap.NewAARQ(0, tcap.ShortMsgMTRelayContext, 2),
+			diag: tcap.SourceDiagnostic{Source: tcap.DialogueServiceProvider, Reason: tcap.NoCommonDialoguePortion},
+			ac:   tcap.ShortMsgMTRelayV2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aare, accepted, err := tcap.NegotiateAARE(tt.aarq, supported...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := aare.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			parsed, err := tcap.ParseDialoguePDU(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			verify.Values(t, "accepted", accepted, tt.accepted)
+			diag, err := parsed.SourceDiagnostic()
+			if err != nil {
+				t.Fatal(err)
+			}
+			verify.Values(t, "diagnostic", diag, tt.diag)
+			ac, err := parsed.ApplicationContext()
+			if err != nil {
+				t.Fatal(err)
+			}
+			verify.Values(t, "application context", ac, tt.ac)
+		})
+	}
+
+	if _, _, err := tcap.NegotiateAARE(tcap.NewABRT(0)); !errors.Is(err, tcap.ErrNotAARQ) {
+		t.Errorf("ABRT: got %v, want %v", err, tcap.ErrNotAARQ)
+	}
+}
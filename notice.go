@@ -0,0 +1,113 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import "fmt"
+
+// SCCP Return Cause definitions (Q.713 3.12).
+const (
+	NoTranslationForAnAddressOfSuchNature uint8 = iota
+	NoTranslationForThisSpecificAddress
+	SubsystemCongestion
+	SubsystemFailure
+	UnequippedUser
+	MTPFailure
+	NetworkCongestion
+	Unqualified
+	ErrorInMessageTransport
+	ErrorInLocalProcessing
+	DestinationCannotPerformReassembly
+	SCCPFailure
+	HopCounterViolation
+	SegmentationNotSupported
+	SegmentationFailure
+)
+
+// Notice is a TC-NOTICE indication, made from the TCAP message returned by SCCP
+// in UDTS or XUDTS because it could not be delivered.
+type Notice struct {
+	// ReturnCause is the SCCP Return Cause in UDTS or XUDTS.
+	ReturnCause uint8
+
+	// TransactionID is the OTID of the returned message, which is the local Transaction ID.
+	// For End and Abort, which do not have OTID, it is the DTID, i.e., the one of the peer.
+	TransactionID uint32
+
+	// Returned is the TCAP message returned.
+	Returned *TCAP
+}
+
+// ParseNotice parses the payload of UDTS or XUDTS as the TCAP message sent by this side
+// and returns the Notice for the transaction.
+func ParseNotice(returnCause uint8, payload []byte) (*Notice, error) {
+	t, err := Parse(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	n := &Notice{
+		ReturnCause: returnCause,
+		Returned:    t,
+	}
+	if tx := t.Transaction; tx != nil {
+		switch {
+		case tx.OrigTransactionID != nil && len(tx.OrigTransactionID.Value) == 4:
+			n.TransactionID = t.OTID()
+		case tx.DestTransactionID != nil && len(tx.DestTransactionID.Value) == 4:
+			n.TransactionID = t.DTID()
+		}
+	}
+	return n, nil
+}
+
+// ReturnCauseString returns the name of ReturnCause.
+func (n *Notice) ReturnCauseString() string {
+	switch n.ReturnCause {
+	case NoTranslationForAnAddressOfSuchNature:
+		return "NoTranslationForAnAddressOfSuchNature"
+	case NoTranslationForThisSpecificAddress:
+		return "NoTranslationForThisSpecificAddress"
+	case SubsystemCongestion:
+		return "SubsystemCongestion"
+	case SubsystemFailure:
+		return "SubsystemFailure"
+	case UnequippedUser:
+		return "UnequippedUser"
+	case MTPFailure:
+		return "MTPFailure"
+	case NetworkCongestion:
+		return "NetworkCongestion"
+	case Unqualified:
+		return "Unqualified"
+	case ErrorInMessageTransport:
+		return "ErrorInMessageTransport"
+	case ErrorInLocalProcessing:
+		return "ErrorInLocalProcessing"
+	case DestinationCannotPerformReassembly:
+		return "DestinationCannotPerformReassembly"
+	case SCCPFailure:
+		return "SCCPFailure"
+	case HopCounterViolation:
+		return "HopCounterViolation"
+	case SegmentationNotSupported:
+		return "SegmentationNotSupported"
+	case SegmentationFailure:
+		return "SegmentationFailure"
+	}
+	return fmt.Sprintf("%d", n.ReturnCause)
+}
+
+// InvokeIDs returns the Invoke IDs of the Invokes in the returned message, which will never be answered.
+func (n *Notice) InvokeIDs() []uint8 {
+	var ids []uint8
+	if c := n.Returned.Components; c != nil {
+		for _, comp := range c.Component {
+			if comp.Type.Code() == Invoke {
+				ids = append(ids, comp.InvID())
+			}
+		}
+	}
+	return ids
+}
@@ -0,0 +1,45 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestParseNotice(t *testing.T) {
+	cases := []struct {
+		description string
+		sent        *tcap.TCAP
+		tid         uint32
+		invIDs      []uint8
+	}{
+		{"Begin", tcap.NewBeginInvoke(0x11111111, 1, 45, nil), 0x11111111, []uint8{1}},
+		{"Continue", tcap.NewContinueInvoke(0x22222222, 0x33333333, 2, 45, nil), 0x22222222, []uint8{2}},
+		{"End", tcap.NewEndReturnResult(0x44444444, 3, 45, true, nil), 0x44444444, nil},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			b, err := c.sent.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			n, err := tcap.ParseNotice(tcap.SubsystemFailure, b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n.TransactionID != c.tid {
+				t.Errorf("TransactionID: got %x, want %x", n.TransactionID, c.tid)
+			}
+			if got, want := n.ReturnCauseString(), "SubsystemFailure"; got != want {
+				t.Errorf("ReturnCause: got %s, want %s", got, want)
+			}
+			verify.Values(t, "InvokeIDs", n.InvokeIDs(), c.invIDs)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import "errors"
+
+// ErrInvalidSegment is returned when a segment of an OCTET STRING in constructed form is not an OCTET STRING.
+var ErrInvalidSegment = errors.New("tcap: invalid segment of constructed OCTET STRING")
+
+// OctetString returns the value of IE decoded as an OCTET STRING. If the IE is in constructed form,
+// the segments are concatenated, e.g., for the parameters with implicit tags fragmented by the peer.
+//
+// The value returned refers to the Value of IE if it is in primitive form.
+func (i *IE) OctetString() ([]byte, error) {
+	if i.Tag.Form() != Constructor {
+		return i.Value, nil
+	}
+	return joinSegments(i.Value, nil)
+}
+
+// joinSegments appends the contents of the segments of an OCTET STRING in constructed form to b.
+// The segments are OCTET STRINGs in primitive form, or in constructed form nested.
+func joinSegments(v, b []byte) ([]byte, error) {
+	segments, err := splitIEs(v)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range segments {
+		switch s.Tag {
+		case NewUniversalPrimitiveTag(4):
+			b = append(b, s.Value...)
+		case NewUniversalConstructorTag(4):
+			if b, err = joinSegments(s.Value, b); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, ErrInvalidSegment
+		}
+	}
+	return b, nil
+}
+
+// joinOctetString turns the OCTET STRING (UNIVERSAL 4) in constructed form into primitive form,
+// with the segments concatenated. The IE is left as is if the segments are not valid.
+func (i *IE) joinOctetString() {
+	if i.Tag != NewUniversalConstructorTag(4) {
+		return
+	}
+	v, err := joinSegments(i.Value, nil)
+	if err != nil {
+		return
+	}
+
+	i.Tag = NewUniversalPrimitiveTag(4)
+	i.Value = v
+	i.IE = nil
+	i.lazy = nil
+	i.SetLength()
+}
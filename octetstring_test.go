@@ -0,0 +1,46 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestJoinOctetStrings(t *testing.T) {
+	// SEQUENCE of OCTET STRING in constructed form, one in definite-length with a nested
+	// segment, and one in indefinite-length.
+	b := []byte{
+		0x30, 0x15,
+		0x24, 0x09, 0x04, 0x02, 0x01, 0x02, 0x24, 0x03, 0x04, 0x01, 0x03,
+		0x24, 0x80, 0x04, 0x01, 0x04, 0x04, 0x01, 0x05, 0x00, 0x00,
+	}
+
+	ies, err := tcap.ParseOptions{JoinOctetStrings: true}.ParseAsBER(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	children := ies[0].IE
+	if len(children) != 2 {
+		t.Fatalf("children: got %d, want 2", len(children))
+	}
+	verify.Values(t, "definite", children[0], tcap.NewIE(0x04, []byte{0x01, 0x02, 0x03}))
+	verify.Values(t, "indefinite", children[1], tcap.NewIE(0x04, []byte{0x04, 0x05}))
+
+	// implicitly tagged as [4] in constructed form.
+	implicit := tcap.NewIE(0xa4, []byte{0x04, 0x01, 0xaa, 0x04, 0x01, 0xbb})
+	v, err := implicit.OctetString()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "implicit", v, []byte{0xaa, 0xbb})
+
+	if _, err := tcap.NewIE(0xa4, []byte{0x02, 0x01, 0x05}).OctetString(); !errors.Is(err, tcap.ErrInvalidSegment) {
+		t.Errorf("got %v, want %v", err, tcap.ErrInvalidSegment)
+	}
+}
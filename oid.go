@@ -0,0 +1,157 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidOID is returned when an OBJECT IDENTIFIER is malformed, in dotted notation or in BER.
+var ErrInvalidOID = errors.New("tcap: invalid OBJECT IDENTIFIER")
+
+// OID is an OBJECT IDENTIFIER as the list of its arcs, e.g., application-context-names and the
+// direct-references of EXTERNAL in the Dialogue portion.
+type OID []uint64
+
+// ParseOID parses the OID in dotted notation, e.g., "0.4.0.0.1.0.21.3".
+func ParseOID(s string) (OID, error) {
+	parts := strings.Split(s, ".")
+	o := make(OID, len(parts))
+	for n, p := range parts {
+		v, err := strconv.ParseUint(p, 10, 64)
+		if err != nil {
+			return nil, ErrInvalidOID
+		}
+		o[n] = v
+	}
+	if !o.valid() {
+		return nil, ErrInvalidOID
+	}
+	return o, nil
+}
+
+// String returns the OID in dotted notation.
+func (o OID) String() string {
+	parts := make([]string, len(o))
+	for n, v := range o {
+		parts[n] = strconv.FormatUint(v, 10)
+	}
+	return strings.Join(parts, ".")
+}
+
+// Equal reports whether the OIDs have the same arcs.
+func (o OID) Equal(other OID) bool {
+	if len(o) != len(other) {
+		return false
+	}
+	for n := range o {
+		if o[n] != other[n] {
+			return false
+		}
+	}
+	return true
+}
+
+// valid reports whether the OID can be encoded, i.e., it has two arcs at least, the first one is
+// 0, 1 or 2, and the second one is less than 40 unless the first one is 2.
+func (o OID) valid() bool {
+	if len(o) < 2 || o[0] > 2 {
+		return false
+	}
+	if o[0] < 2 && o[1] >= 40 {
+		return false
+	}
+	return o[0] < 2 || o[1] <= ^uint64(0)-80
+}
+
+// MarshalBinary returns the contents octets of the OID in BER.
+func (o OID) MarshalBinary() ([]byte, error) {
+	if !o.valid() {
+		return nil, ErrInvalidOID
+	}
+
+	b := appendArc(nil, o[0]*40+o[1])
+	for _, v := range o[2:] {
+		b = appendArc(b, v)
+	}
+	return b, nil
+}
+
+// appendArc appends the arc in base 128, with the continuation bit set on all but the last octet.
+func appendArc(b []byte, v uint64) []byte {
+	var tmp [10]byte
+	n := len(tmp) - 1
+	tmp[n] = byte(v & 0x7f)
+	for v >>= 7; v > 0; v >>= 7 {
+		n--
+		tmp[n] = byte(v&0x7f) | 0x80
+	}
+	return append(b, tmp[n:]...)
+}
+
+// UnmarshalBinary sets the OID decoded from the contents octets in BER.
+func (o *OID) UnmarshalBinary(b []byte) error {
+	if len(b) == 0 {
+		return ErrInvalidOID
+	}
+
+	var arcs OID
+	for len(b) > 0 {
+		// the leading octet 0x80 is not the minimum encoding.
+		if b[0] == 0x80 {
+			return ErrInvalidOID
+		}
+
+		var v uint64
+		n := 0
+		for {
+			if n == len(b) || v>>57 != 0 {
+				return ErrInvalidOID
+			}
+			v = v<<7 | uint64(b[n]&0x7f)
+			n++
+			if b[n-1]&0x80 == 0 {
+				break
+			}
+		}
+		b = b[n:]
+
+		if len(arcs) == 0 {
+			switch {
+			case v < 40:
+				arcs = append(arcs, 0, v)
+			case v < 80:
+				arcs = append(arcs, 1, v-40)
+			default:
+				arcs = append(arcs, 2, v-80)
+			}
+			continue
+		}
+		arcs = append(arcs, v)
+	}
+
+	*o = arcs
+	return nil
+}
+
+// NewObjectIdentifier creates a new IE with the OID encoded as an OBJECT IDENTIFIER.
+func NewObjectIdentifier(tag Tag, o OID) (*IE, error) {
+	b, err := o.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return NewIE(tag, b), nil
+}
+
+// OID returns the value of IE decoded as an OBJECT IDENTIFIER.
+func (i *IE) OID() (OID, error) {
+	var o OID
+	if err := o.UnmarshalBinary(i.Value); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
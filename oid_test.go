@@ -0,0 +1,61 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestOID(t *testing.T) {
+	cases := []struct {
+		dotted  string
+		encoded []byte
+	}{
+		{"0.4.0.0.1.0.21.3", []byte{0x04, 0x00, 0x00, 0x01, 0x00, 0x15, 0x03}},
+		{"0.4.0.0.1.1.1.1", tcap.MAPDialogueAS},
+		{"1.2.840.113549", []byte{0x2a, 0x86, 0x48, 0x86, 0xf7, 0x0d}},
+		{"2.100.3", []byte{0x81, 0x34, 0x03}},
+	}
+
+	for _, c := range cases {
+		o, err := tcap.ParseOID(c.dotted)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := o.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		verify.Values(t, c.dotted, b, c.encoded)
+
+		i, err := tcap.NewObjectIdentifier(tcap.NewUniversalPrimitiveTag(6), o)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := i.OID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.String() != c.dotted || !got.Equal(o) {
+			t.Errorf("decoded: got %s, want %s", got, c.dotted)
+		}
+	}
+
+	for _, s := range []string{"", "0", "3.1", "0.40", "0.x.1"} {
+		if _, err := tcap.ParseOID(s); !errors.Is(err, tcap.ErrInvalidOID) {
+			t.Errorf("%q: got %v, want %v", s, err, tcap.ErrInvalidOID)
+		}
+	}
+	for _, b := range [][]byte{{}, {0x04, 0x80, 0x01}, {0x04, 0x81}} {
+		var o tcap.OID
+		if err := o.UnmarshalBinary(b); !errors.Is(err, tcap.ErrInvalidOID) {
+			t.Errorf("%x: got %v, want %v", b, err, tcap.ErrInvalidOID)
+		}
+	}
+}
@@ -0,0 +1,381 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrInvalidOperationCode is returned when the Component has no Operation Code or it is malformed.
+var ErrInvalidOperationCode = errors.New("tcap: invalid operation code")
+
+// ErrUnknownOperation is returned when no codec is registered for the Operation Code of the application.
+var ErrUnknownOperation = errors.New("tcap: unknown operation")
+
+// OperationCode is the Operation Code in Invoke and ReturnResult, which is either local (INTEGER)
+// or global (OBJECT IDENTIFIER).
+type OperationCode struct {
+	// Local is the local Operation Code, used if Global is nil.
+	Local int64
+
+	// Global is the global Operation Code, or nil if local.
+	Global OID
+}
+
+// LocalOperationCode returns the local OperationCode of v, e.g., LocalOperationCode(MAPOpMOForwardSM).
+func LocalOperationCode(v int64) OperationCode {
+	return OperationCode{Local: v}
+}
+
+// GlobalOperationCode returns the global OperationCode of the OID.
+func GlobalOperationCode(o OID) OperationCode {
+	return OperationCode{Global: o}
+}
+
+// IsGlobal reports whether the OperationCode is global.
+func (o OperationCode) IsGlobal() bool {
+	return o.Global != nil
+}
+
+// Equal reports whether the OperationCodes are the same.
+func (o OperationCode) Equal(other OperationCode) bool {
+	if o.IsGlobal() || other.IsGlobal() {
+		return o.Global.Equal(other.Global)
+	}
+	return o.Local == other.Local
+}
+
+// String returns the OperationCode in decimal or in dotted notation if global.
+func (o OperationCode) String() string {
+	if o.IsGlobal() {
+		return o.Global.String()
+	}
+	return fmt.Sprintf("%d", o.Local)
+}
+
+// Name returns the name of the OperationCode registered for the application, e.g., "mo-forwardSM"
+// for MAPOpMOForwardSM of "map", or the OperationCode in string if not registered.
+func (o OperationCode) Name(application string) string {
+	if info, ok := LookupOperation(application, o); ok && info.Name != "" {
+		return info.Name
+	}
+	return o.String()
+}
+
+// ie returns the OperationCode as an IE.
+func (o OperationCode) ie() (*IE, error) {
+	if o.IsGlobal() {
+		return NewObjectIdentifier(NewUniversalPrimitiveTag(6), o.Global)
+	}
+	return NewInteger(NewUniversalPrimitiveTag(2), o.Local), nil
+}
+
+// key returns the OperationCode as a key of the registry.
+func (o OperationCode) key() opKey {
+	if o.IsGlobal() {
+		return opKey{global: o.Global.String()}
+	}
+	return opKey{local: o.Local}
+}
+
+// opKey is the comparable form of OperationCode.
+type opKey struct {
+	local  int64
+	global string
+}
+
+// NewInvokeWithCode returns a new single Invoke Component of the local or global OperationCode.
+// The lkID is set in the same way as NewInvoke.
+func NewInvokeWithCode(invID, lkID int, code OperationCode, param []byte) (*Component, error) {
+	i, err := code.ie()
+	if err != nil {
+		return nil, err
+	}
+	c := NewInvoke(invID, lkID, 0, true, param)
+	c.OperationCode = i
+	c.SetLength()
+	return c, nil
+}
+
+// NewReturnResultWithCode returns a new single ReturnResultLast or ReturnResultNotLast Component
+// of the local or global OperationCode.
+func NewReturnResultWithCode(invID int, code OperationCode, isLast bool, param []byte) (*Component, error) {
+	i, err := code.ie()
+	if err != nil {
+		return nil, err
+	}
+	c := NewReturnResult(invID, 0, true, isLast, param)
+	c.OperationCode = i
+	c.SetLength()
+	return c, nil
+}
+
+// Operation returns the OperationCode of the Invoke or ReturnResult.
+func (c *Component) Operation() (OperationCode, error) {
+	switch c.Type.Code() {
+	case Invoke, ReturnResultLast, ReturnResultNotLast:
+	default:
+		return OperationCode{}, ErrInvalidOperationCode
+	}
+	if c.OperationCode == nil {
+		return OperationCode{}, ErrInvalidOperationCode
+	}
+	local, global, err := decodeOpCode(c.OperationCode)
+	if err != nil {
+		return OperationCode{}, ErrInvalidOperationCode
+	}
+	if global != nil {
+		return GlobalOperationCode(global), nil
+	}
+	return LocalOperationCode(local), nil
+}
+
+// Operation returns the OperationCode of the Invoke.
+func (i *InvokeComponent) Operation() OperationCode {
+	return OperationCode{Local: i.OpCode, Global: i.GlobalOpCode}
+}
+
+// Operation returns the OperationCode of the ReturnResult.
+func (r *ReturnResultComponent) Operation() OperationCode {
+	return OperationCode{Local: r.OpCode, Global: r.GlobalOpCode}
+}
+
+// OperationClass is the class of an operation (Q.771), which tells the outcomes reported.
+type OperationClass uint8
+
+// OperationClass definitions.
+const (
+	// OperationClass1 reports both success and failure.
+	OperationClass1 OperationClass = iota + 1
+
+	// OperationClass2 reports failure only.
+	OperationClass2
+
+	// OperationClass3 reports success only.
+	OperationClass3
+
+	// OperationClass4 reports neither success nor failure.
+	OperationClass4
+)
+
+// String returns OperationClass in the form of "class 1".
+func (c OperationClass) String() string {
+	return fmt.Sprintf("class %d", uint8(c))
+}
+
+// ParameterCodec decodes the Parameter of a Component into the value of the application,
+// typically a type generated for the schema.
+type ParameterCodec func(param *AnyValue) (any, error)
+
+// OperationInfo describes an OperationCode of an application.
+type OperationInfo struct {
+	Name  string
+	Class OperationClass
+
+	// Argument and Result decode the Parameters of the Invoke and the ReturnResult, or nil if unknown.
+	Argument ParameterCodec
+	Result   ParameterCodec
+}
+
+// MAP Operation Code definitions (3GPP TS 29.002).
+const (
+	MAPOpUpdateLocation                  int64 = 2
+	MAPOpCancelLocation                  int64 = 3
+	MAPOpProvideRoamingNumber            int64 = 4
+	MAPOpInsertSubscriberData            int64 = 7
+	MAPOpDeleteSubscriberData            int64 = 8
+	MAPOpSendRoutingInfo                 int64 = 22
+	MAPOpUpdateGprsLocation              int64 = 23
+	MAPOpSendRoutingInfoForGprs          int64 = 24
+	MAPOpReset                           int64 = 37
+	MAPOpCheckIMEI                       int64 = 43
+	MAPOpMTForwardSM                     int64 = 44
+	MAPOpSendRoutingInfoForSM            int64 = 45
+	MAPOpMOForwardSM                     int64 = 46
+	MAPOpReportSMDeliveryStatus          int64 = 47
+	MAPOpNoteSubscriberPresent           int64 = 48
+	MAPOpAlertServiceCentreWithoutResult int64 = 49
+	MAPOpSendIdentification              int64 = 55
+	MAPOpSendAuthenticationInfo          int64 = 56
+	MAPOpRestoreData                     int64 = 57
+	MAPOpSendIMSI                        int64 = 58
+	MAPOpProcessUnstructuredSSRequest    int64 = 59
+	MAPOpUnstructuredSSRequest           int64 = 60
+	MAPOpUnstructuredSSNotify            int64 = 61
+	MAPOpAlertServiceCentre              int64 = 64
+	MAPOpAnyTimeModification             int64 = 65
+	MAPOpReadyForSM                      int64 = 66
+	MAPOpPurgeMS                         int64 = 67
+	MAPOpProvideSubscriberInfo           int64 = 70
+	MAPOpAnyTimeInterrogation            int64 = 71
+	MAPOpProvideSubscriberLocation       int64 = 83
+	MAPOpSendRoutingInfoForLCS           int64 = 85
+	MAPOpSubscriberLocationReport        int64 = 86
+)
+
+// CAP Operation Code definitions (3GPP TS 29.078).
+const (
+	CAPOpInitialDP                       int64 = 0
+	CAPOpAssistRequestInstructions       int64 = 16
+	CAPOpEstablishTemporaryConnection    int64 = 17
+	CAPOpDisconnectForwardConnection     int64 = 18
+	CAPOpConnectToResource               int64 = 19
+	CAPOpConnect                         int64 = 20
+	CAPOpReleaseCall                     int64 = 22
+	CAPOpRequestReportBCSMEvent          int64 = 23
+	CAPOpEventReportBCSM                 int64 = 24
+	CAPOpContinue                        int64 = 31
+	CAPOpInitiateCallAttempt             int64 = 32
+	CAPOpResetTimer                      int64 = 33
+	CAPOpFurnishChargingInformation      int64 = 34
+	CAPOpApplyCharging                   int64 = 35
+	CAPOpApplyChargingReport             int64 = 36
+	CAPOpCallInformationReport           int64 = 44
+	CAPOpCallInformationRequest          int64 = 45
+	CAPOpSendChargingInformation         int64 = 46
+	CAPOpPlayAnnouncement                int64 = 47
+	CAPOpPromptAndCollectUserInformation int64 = 48
+	CAPOpSpecializedResourceReport       int64 = 49
+	CAPOpCancel                          int64 = 53
+	CAPOpActivityTest                    int64 = 55
+	CAPOpInitialDPSMS                    int64 = 60
+	CAPOpConnectSMS                      int64 = 62
+	CAPOpRequestReportSMSEvent           int64 = 63
+	CAPOpEventReportSMS                  int64 = 64
+	CAPOpContinueSMS                     int64 = 65
+	CAPOpReleaseSMS                      int64 = 66
+	CAPOpContinueWithArgument            int64 = 88
+)
+
+var (
+	operationMu    sync.RWMutex
+	operationInfos = map[string]map[opKey]OperationInfo{
+		"map": localOperations(map[int64]OperationInfo{
+			MAPOpUpdateLocation:                  {Name: "updateLocation", Class: OperationClass1},
+			MAPOpCancelLocation:                  {Name: "cancelLocation", Class: OperationClass1},
+			MAPOpProvideRoamingNumber:            {Name: "provideRoamingNumber", Class: OperationClass1},
+			MAPOpInsertSubscriberData:            {Name: "insertSubscriberData", Class: OperationClass1},
+			MAPOpDeleteSubscriberData:            {Name: "deleteSubscriberData", Class: OperationClass1},
+			MAPOpSendRoutingInfo:                 {Name: "sendRoutingInfo", Class: OperationClass1},
+			MAPOpUpdateGprsLocation:              {Name: "updateGprsLocation", Class: OperationClass1},
+			MAPOpSendRoutingInfoForGprs:          {Name: "sendRoutingInfoForGprs", Class: OperationClass1},
+			MAPOpReset:                           {Name: "reset", Class: OperationClass4},
+			MAPOpCheckIMEI:                       {Name: "checkIMEI", Class: OperationClass1},
+			MAPOpMTForwardSM:                     {Name: "mt-forwardSM", Class: OperationClass1},
+			MAPOpSendRoutingInfoForSM:            {Name: "sendRoutingInfoForSM", Class: OperationClass1},
+			MAPOpMOForwardSM:                     {Name: "mo-forwardSM", Class: OperationClass1},
+			MAPOpReportSMDeliveryStatus:          {Name: "reportSM-DeliveryStatus", Class: OperationClass1},
+			MAPOpNoteSubscriberPresent:           {Name: "noteSubscriberPresent", Class: OperationClass4},
+			MAPOpAlertServiceCentreWithoutResult: {Name: "alertServiceCentreWithoutResult", Class: OperationClass4},
+			MAPOpSendIdentification:              {Name: "sendIdentification", Class: OperationClass1},
+			MAPOpSendAuthenticationInfo:          {Name: "sendAuthenticationInfo", Class: OperationClass1},
+			MAPOpRestoreData:                     {Name: "restoreData", Class: OperationClass1},
+			MAPOpSendIMSI:                        {Name: "sendIMSI", Class: OperationClass1},
+			MAPOpProcessUnstructuredSSRequest:    {Name: "processUnstructuredSS-Request", Class: OperationClass1},
+			MAPOpUnstructuredSSRequest:           {Name: "unstructuredSS-Request", Class: OperationClass1},
+			MAPOpUnstructuredSSNotify:            {Name: "unstructuredSS-Notify", Class: OperationClass1},
+			MAPOpAlertServiceCentre:              {Name: "alertServiceCentre", Class: OperationClass1},
+			MAPOpAnyTimeModification:             {Name: "anyTimeModification", Class: OperationClass1},
+			MAPOpReadyForSM:                      {Name: "readyForSM", Class: OperationClass1},
+			MAPOpPurgeMS:                         {Name: "purgeMS", Class: OperationClass1},
+			MAPOpProvideSubscriberInfo:           {Name: "provideSubscriberInfo", Class: OperationClass1},
+			MAPOpAnyTimeInterrogation:            {Name: "anyTimeInterrogation", Class: OperationClass1},
+			MAPOpProvideSubscriberLocation:       {Name: "provideSubscriberLocation", Class: OperationClass1},
+			MAPOpSendRoutingInfoForLCS:           {Name: "sendRoutingInfoForLCS", Class: OperationClass1},
+			MAPOpSubscriberLocationReport:        {Name: "subscriberLocationReport", Class: OperationClass1},
+		}),
+		"cap": localOperations(map[int64]OperationInfo{
+			CAPOpInitialDP:                       {Name: "initialDP", Class: OperationClass2},
+			CAPOpAssistRequestInstructions:       {Name: "assistRequestInstructions", Class: OperationClass2},
+			CAPOpEstablishTemporaryConnection:    {Name: "establishTemporaryConnection", Class: OperationClass2},
+			CAPOpDisconnectForwardConnection:     {Name: "disconnectForwardConnection", Class: OperationClass2},
+			CAPOpConnectToResource:               {Name: "connectToResource", Class: OperationClass2},
+			CAPOpConnect:                         {Name: "connect", Class: OperationClass2},
+			CAPOpReleaseCall:                     {Name: "releaseCall", Class: OperationClass4},
+			CAPOpRequestReportBCSMEvent:          {Name: "requestReportBCSMEvent", Class: OperationClass2},
+			CAPOpEventReportBCSM:                 {Name: "eventReportBCSM", Class: OperationClass4},
+			CAPOpContinue:                        {Name: "continue", Class: OperationClass4},
+			CAPOpInitiateCallAttempt:             {Name: "initiateCallAttempt", Class: OperationClass1},
+			CAPOpResetTimer:                      {Name: "resetTimer", Class: OperationClass2},
+			CAPOpFurnishChargingInformation:      {Name: "furnishChargingInformation", Class: OperationClass2},
+			CAPOpApplyCharging:                   {Name: "applyCharging", Class: OperationClass2},
+			CAPOpApplyChargingReport:             {Name: "applyChargingReport", Class: OperationClass2},
+			CAPOpCallInformationReport:           {Name: "callInformationReport", Class: OperationClass4},
+			CAPOpCallInformationRequest:          {Name: "callInformationRequest", Class: OperationClass2},
+			CAPOpSendChargingInformation:         {Name: "sendChargingInformation", Class: OperationClass2},
+			CAPOpPlayAnnouncement:                {Name: "playAnnouncement", Class: OperationClass2},
+			CAPOpPromptAndCollectUserInformation: {Name: "promptAndCollectUserInformation", Class: OperationClass1},
+			CAPOpSpecializedResourceReport:       {Name: "specializedResourceReport", Class: OperationClass4},
+			CAPOpCancel:                          {Name: "cancel", Class: OperationClass2},
+			CAPOpActivityTest:                    {Name: "activityTest", Class: OperationClass3},
+			CAPOpInitialDPSMS:                    {Name: "initialDPSMS", Class: OperationClass2},
+			CAPOpConnectSMS:                      {Name: "connectSMS", Class: OperationClass2},
+			CAPOpRequestReportSMSEvent:           {Name: "requestReportSMSEvent", Class: OperationClass2},
+			CAPOpEventReportSMS:                  {Name: "eventReportSMS", Class: OperationClass4},
+			CAPOpContinueSMS:                     {Name: "continueSMS", Class: OperationClass4},
+			CAPOpReleaseSMS:                      {Name: "releaseSMS", Class: OperationClass4},
+			CAPOpContinueWithArgument:            {Name: "continueWithArgument", Class: OperationClass2},
+		}),
+	}
+)
+
+// localOperations returns the OperationInfos of the local Operation Codes keyed for the registry.
+func localOperations(infos map[int64]OperationInfo) map[opKey]OperationInfo {
+	m := make(map[opKey]OperationInfo, len(infos))
+	for code, info := range infos {
+		m[opKey{local: code}] = info
+	}
+	return m
+}
+
+// RegisterOperation registers the OperationCode of the application, e.g., "map", "cap" or a
+// vendor specific one, replacing the one already registered, if any.
+func RegisterOperation(application string, code OperationCode, info OperationInfo) {
+	operationMu.Lock()
+	defer operationMu.Unlock()
+
+	m, ok := operationInfos[application]
+	if !ok {
+		m = map[opKey]OperationInfo{}
+		operationInfos[application] = m
+	}
+	m[code.key()] = info
+}
+
+// LookupOperation returns the OperationInfo registered for the OperationCode of the application.
+func LookupOperation(application string, code OperationCode) (OperationInfo, bool) {
+	operationMu.RLock()
+	defer operationMu.RUnlock()
+
+	info, ok := operationInfos[application][code.key()]
+	return info, ok
+}
+
+// DecodeParameter decodes the Parameter of the Invoke or ReturnResult with the codec registered
+// for its OperationCode of the application, i.e., Argument for Invoke and Result for ReturnResult.
+//
+// It returns nil if the Parameter is not present, and ErrUnknownOperation if no codec is registered.
+func (c *Component) DecodeParameter(application string) (any, error) {
+	code, err := c.Operation()
+	if err != nil {
+		return nil, err
+	}
+	info, ok := LookupOperation(application, code)
+	codec := info.Result
+	if c.Type.Code() == Invoke {
+		codec = info.Argument
+	}
+	if !ok || codec == nil {
+		return nil, ErrUnknownOperation
+	}
+
+	param, err := c.AnyParameter()
+	if err != nil || param == nil {
+		return nil, err
+	}
+	return codec(param)
+}
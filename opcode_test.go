@@ -0,0 +1,221 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestOperationCode(t *testing.T) {
+	inv, err := tcap.NewInvokeWithCode(1, -1, tcap.LocalOperationCode(tcap.MAPOpMOForwardSM), []byte{0x04, 0x01, 0xaa})
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, err := inv.Operation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "name", code.Name("map"), "mo-forwardSM")
+	if info, ok := tcap.LookupOperation("cap", tcap.LocalOperationCode(tcap.CAPOpActivityTest)); !ok || info.Class != tcap.OperationClass3 {
+		t.Errorf("activityTest: got %v, %v", info.Class, ok)
+	}
+
+	global := tcap.GlobalOperationCode(tcap.OID{1, 2, 826, 0, 1249, 1})
+	res, err := tcap.NewReturnResultWithCode(1, global, true, []byte{0x04, 0x01, 0xbb})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code, err := res.Operation(); err != nil || !code.Equal(global) {
+		t.Errorf("global: got %v, %v", code, err)
+	}
+	if _, err := res.DecodeParameter("vendor"); !errors.Is(err, tcap.ErrUnknownOperation) {
+		t.Errorf("unregistered: got %v, want %v", err, tcap.ErrUnknownOperation)
+	}
+
+	tcap.RegisterOperation("vendor", global, tcap.OperationInfo{
+		Name: "vendorQuery", Class: tcap.OperationClass1,
+		Result: func(param *tcap.AnyValue) (any, error) {
+			return param.Bytes(), nil
+		},
+	})
+	v, err := res.DecodeParameter("vendor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "decoded", v, []byte{0x30, 0x03, 0x04, 0x01, 0xbb})
+	verify.Values(t, "global name", global.Name("vendor"), "vendorQuery")
+}
+
+func TestOperationCodeEncoding(t *testing.T) {
+	cases := []struct {
+		description string
+		code        tcap.OperationCode
+		serialized  []byte
+		str         string
+	}{
+		{
+			"Local",
+			tcap.LocalOperationCode(tcap.MAPOpMOForwardSM),
+			[]byte{0x02, 0x01, 0x2e},
+			"46",
+		}, {
+			"Local/multi-octet",
+			tcap.LocalOperationCode(300),
+			[]byte{0x02, 0x02, 0x01, 0x2c},
+			"300",
+		}, {
+			"Local/negative",
+			tcap.LocalOperationCode(-1),
+			[]byte{0x02, 0x01, 0xff},
+			"-1",
+		}, {
+			"Global",
+			tcap.GlobalOperationCode(tcap.OID{1, 2, 826, 0, 1249, 1}),
+			[]byte{0x06, 0x07, 0x2a, 0x86, 0x3a, 0x00, 0x89, 0x61, 0x01},
+			"1.2.826.0.1249.1",
+		}, {
+			"Global/single arc",
+			tcap.GlobalOperationCode(tcap.OID{0, 4}),
+			[]byte{0x06, 0x01, 0x04},
+			"0.4",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			verify.Values(t, "string", c.code.String(), c.str)
+
+			for _, build := range []func() (*tcap.Component, error){
+				func() (*tcap.Component, error) {
+					return tcap.NewInvokeWithCode(1, -1, c.code, []byte{0x04, 0x01, 0xaa})
+				},
+				func() (*tcap.Component, error) {
+					return tcap.NewReturnResultWithCode(1, c.code, false, []byte{0x04, 0x01, 0xaa})
+				},
+			} {
+				comp, err := build()
+				if err != nil {
+					t.Fatal(err)
+				}
+				b, err := comp.OperationCode.MarshalBinary()
+				if err != nil {
+					t.Fatal(err)
+				}
+				verify.Values(t, "serialized", b, c.serialized)
+
+				b, err = comp.MarshalBinary()
+				if err != nil {
+					t.Fatal(err)
+				}
+				parsed, err := tcap.ParseComponent(b)
+				if err != nil {
+					t.Fatal(err)
+				}
+				code, err := parsed.Operation()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !code.Equal(c.code) {
+					t.Errorf("decoded: got %v, want %v", code, c.code)
+				}
+				verify.Values(t, "global", code.IsGlobal(), c.code.IsGlobal())
+			}
+		})
+	}
+}
+
+func TestOperationCodeInvalid(t *testing.T) {
+	if _, err := tcap.NewReturnError(1, 1, true, nil).Operation(); !errors.Is(err, tcap.ErrInvalidOperationCode) {
+		t.Errorf("ReturnError: got %v, want %v", err, tcap.ErrInvalidOperationCode)
+	}
+
+	c := tcap.NewInvoke(1, -1, 0, true, nil)
+	c.OperationCode = nil
+	if _, err := c.Operation(); !errors.Is(err, tcap.ErrInvalidOperationCode) {
+		t.Errorf("no OperationCode: got %v, want %v", err, tcap.ErrInvalidOperationCode)
+	}
+	c.OperationCode = tcap.NewIE(tcap.NewUniversalPrimitiveTag(2), nil)
+	if _, err := c.Operation(); !errors.Is(err, tcap.ErrInvalidOperationCode) {
+		t.Errorf("empty OperationCode: got %v, want %v", err, tcap.ErrInvalidOperationCode)
+	}
+	c.OperationCode = tcap.NewIE(tcap.NewUniversalPrimitiveTag(4), []byte{0x01})
+	if _, err := c.Operation(); !errors.Is(err, tcap.ErrInvalidOperationCode) {
+		t.Errorf("OCTET STRING OperationCode: got %v, want %v", err, tcap.ErrInvalidOperationCode)
+	}
+}
+
+func TestOperationRegistryUnknown(t *testing.T) {
+	cases := []struct {
+		description string
+		application string
+		code        tcap.OperationCode
+	}{
+		{"Local/unregistered code", "map", tcap.LocalOperationCode(9999)},
+		{"Local/unregistered application", "unknown", tcap.LocalOperationCode(tcap.MAPOpMOForwardSM)},
+		{"Local/other application", "cap", tcap.LocalOperationCode(tcap.MAPOpMOForwardSM + 1000)},
+		{"Global/unregistered code", "map", tcap.GlobalOperationCode(tcap.OID{1, 2, 826, 0, 1249, 99})},
+		{"Global/local code of same value", "map", tcap.GlobalOperationCode(tcap.OID{2, uint64(tcap.MAPOpMOForwardSM)})},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			if info, ok := tcap.LookupOperation(c.application, c.code); ok {
+				t.Errorf("lookup: got %+v, want not found", info)
+			}
+			verify.Values(t, "name", c.code.Name(c.application), c.code.String())
+
+			comp, err := tcap.NewInvokeWithCode(1, -1, c.code, []byte{0x04, 0x01, 0xaa})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := comp.DecodeParameter(c.application); !errors.Is(err, tcap.ErrUnknownOperation) {
+				t.Errorf("decode: got %v, want %v", err, tcap.ErrUnknownOperation)
+			}
+		})
+	}
+}
+
+func TestOperationRegistryDuplicate(t *testing.T) {
+	local := tcap.LocalOperationCode(7)
+	global := tcap.GlobalOperationCode(tcap.OID{1, 2, 826, 0, 1249, 7})
+
+	for _, code := range []tcap.OperationCode{local, global} {
+		tcap.RegisterOperation("test-duplicate", code, tcap.OperationInfo{
+			Name: "first", Class: tcap.OperationClass1,
+			Argument: func(param *tcap.AnyValue) (any, error) {
+				return "first", nil
+			},
+		})
+		tcap.RegisterOperation("test-duplicate", code, tcap.OperationInfo{
+			Name: "second", Class: tcap.OperationClass4,
+		})
+
+		info, ok := tcap.LookupOperation("test-duplicate", code)
+		if !ok {
+			t.Fatalf("%v: not found", code)
+		}
+		verify.Values(t, "name", info.Name, "second")
+		verify.Values(t, "class", info.Class, tcap.OperationClass4)
+		verify.Values(t, "code name", code.Name("test-duplicate"), "second")
+
+		// The codec of the first registration must not survive the replacement.
+		comp, err := tcap.NewInvokeWithCode(1, -1, code, []byte{0x04, 0x01, 0xaa})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := comp.DecodeParameter("test-duplicate"); !errors.Is(err, tcap.ErrUnknownOperation) {
+			t.Errorf("%v: got %v, want %v", code, err, tcap.ErrUnknownOperation)
+		}
+	}
+
+	// Replacing the global code must not touch the local one and vice versa.
+	tcap.RegisterOperation("test-duplicate", local, tcap.OperationInfo{Name: "third"})
+	verify.Values(t, "local name", local.Name("test-duplicate"), "third")
+	verify.Values(t, "global name", global.Name("test-duplicate"), "second")
+}
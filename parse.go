@@ -0,0 +1,437 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import (
+	"bytes"
+	"errors"
+	"io"
+)
+
+// ErrMaxDepthExceeded is returned when IEs are nested deeper than MaxDepth of ParseOptions.
+var ErrMaxDepthExceeded = errors.New("tcap: IEs nested too deep")
+
+// ParseOptions specifies how IEs are parsed.
+//
+// The zero value parses in the same way as ParseIE, ParseMultiIEs and ParseAsBER.
+type ParseOptions struct {
+	// Copy makes the IEs parsed own their Value, instead of referring to the byte sequence given,
+	// so that it can be reused or returned to a pool after parsing.
+	Copy bool
+
+	// MaxDepth is the maximum nesting level of IEs, where the outermost one is 1.
+	// Zero means no limit, which should not be used for untrusted input.
+	MaxDepth int
+
+	// MaxElementLength is the maximum length of the value of an IE, and MaxMessageLength
+	// is the maximum length of the whole byte sequence. Zero means no limit.
+	MaxElementLength int
+	MaxMessageLength int
+
+	// Parents links each IE parsed to its parent, so that Parent, Root and Path can be used
+	// to walk upward from any IE in the tree.
+	Parents bool
+
+	// Allocator provides the IEs to parse into. The IEs are allocated normally if nil.
+	Allocator Allocator
+
+	// Lazy defers parsing the children of each constructed IE in ParseAsBER until they are
+	// first accessed with Children, FindAll, FindFirst or Walk, or parsed with DecodeChildren,
+	// e.g., to route messages by the transaction ID without decoding the Components.
+	// The children in indefinite-length form are parsed anyway, to find the end of the contents.
+	Lazy bool
+
+	// JoinOctetStrings turns the OCTET STRINGs (UNIVERSAL 4) in constructed form into primitive form,
+	// with the segments concatenated into a single Value, so that the parameters fragmented by
+	// the peer are seen as contiguous. The ones with implicit tags can be joined with OctetString.
+	JoinOctetStrings bool
+
+	// Raw records the original bytes and the position of each IE parsed, which can be retrieved
+	// with Raw and Offset, e.g., to point back into the payload or to re-emit it byte-exactly.
+	//
+	// The IEs recorded are marshaled with the original forms of their tags and lengths, e.g., long form
+	// of a short length, non-minimal and indefinite-length ones, as long as the Tag and Length are
+	// unchanged. Together with the widths of the Values kept (e.g., Transaction IDs shorter than 4
+	// octets), the tree is re-encoded byte-identical except for the IEs rewritten and their ancestors.
+	Raw bool
+
+	// StrictDialogue makes ParseTCAP reject the Dialogue Portion deviating from Q.773 (see Anomalies
+	// of Dialogue) with DialogueAnomalyError. Otherwise such a Dialogue Portion is accepted as long as
+	// it can be decoded, and the anomalies can be retrieved with Anomalies.
+	StrictDialogue bool
+}
+
+// ParseTCAP parses given byte sequence as a TCAP with the options.
+//
+// The limits (MaxDepth, MaxElementLength and MaxMessageLength) and StrictDialogue in the options apply,
+// and the TCAP is parsed in the same way as Parse otherwise.
+func (o ParseOptions) ParseTCAP(b []byte) (*TCAP, error) {
+	if err := o.checkLimits(b); err != nil {
+		return nil, err
+	}
+	t, err := Parse(b)
+	if err != nil {
+		return nil, err
+	}
+	if o.StrictDialogue && t.Dialogue != nil {
+		if v := t.Dialogue.Anomalies(); len(v) > 0 {
+			return nil, &DialogueAnomalyError{Anomalies: v}
+		}
+	}
+	return t, nil
+}
+
+// ParseIE parses given byte sequence as an IE with the options.
+func (o ParseOptions) ParseIE(b []byte) (*IE, error) {
+	if len(b) < 3 {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	if err := o.checkLength(0, len(b)); err != nil {
+		return nil, err
+	}
+
+	in := o.input(b)
+	p := &parser{opts: o, input: in}
+	i := p.newIE()
+	if _, err := p.parse(i, in); err != nil {
+		return nil, parseErrorAt(err, b, 0)
+	}
+	return i, nil
+}
+
+// ParseMultiIEs parses multiple (unspecified number of) IEs to []*IE at a time with the options.
+func (o ParseOptions) ParseMultiIEs(b []byte) ([]*IE, error) {
+	if err := o.checkLength(0, len(b)); err != nil {
+		return nil, err
+	}
+	in := o.input(b)
+	return (&parser{opts: o, input: in}).parseIEs(in, 3)
+}
+
+// ParseAsBER parses given byte sequence as multiple IEs with the options.
+func (o ParseOptions) ParseAsBER(b []byte) ([]*IE, error) {
+	if err := o.checkLength(0, len(b)); err != nil {
+		return nil, err
+	}
+	in := o.input(b)
+	return (&parser{opts: o, input: in, recursive: true}).parseIEs(in, 2)
+}
+
+// checkLength checks the length of the value of an IE and the one of the whole message against the limits.
+func (o ParseOptions) checkLength(element, message int) error {
+	if o.MaxElementLength > 0 && element > o.MaxElementLength {
+		return &TooLongError{Field: "element", Length: element, Max: o.MaxElementLength}
+	}
+	if o.MaxMessageLength > 0 && message > o.MaxMessageLength {
+		return &TooLongError{Field: "message", Length: message, Max: o.MaxMessageLength}
+	}
+	return nil
+}
+
+// checkLimits parses the whole byte sequence with the limits in the options, and returns the error
+// if any of them is exceeded, so that the TCAP decoded afterwards is within the limits.
+// The other errors are left to the decoding.
+func (o ParseOptions) checkLimits(b []byte) error {
+	limits := ParseOptions{MaxDepth: o.MaxDepth, MaxElementLength: o.MaxElementLength, MaxMessageLength: o.MaxMessageLength}
+	if limits == (ParseOptions{}) {
+		return nil
+	}
+	if err := limits.checkLength(0, len(b)); err != nil {
+		return err
+	}
+	p := &parser{opts: limits, input: b, recursive: true}
+	if _, err := p.parseIEs(b, 2); isLimitError(err) {
+		return err
+	}
+	return nil
+}
+
+// isLimitError reports whether the error is caused by the limits in ParseOptions.
+func isLimitError(err error) bool {
+	var tooLong *TooLongError
+	return errors.Is(err, ErrMaxDepthExceeded) || errors.As(err, &tooLong)
+}
+
+// input returns the byte sequence to be parsed, which is copied if Copy is set.
+func (o ParseOptions) input(b []byte) []byte {
+	if o.Copy {
+		return bytes.Clone(b)
+	}
+	return b
+}
+
+// definiteForm returns the IEs in b re-encoded with the constructed ones in indefinite-length form
+// turned into definite-length form, and reports whether it differs from b. The offsetMap returned
+// maps the positions in the one re-encoded back to the ones in b.
+//
+// The constructed IEs whose contents cannot be parsed are left as they are.
+func definiteForm(b []byte) ([]byte, offsetMap, bool) {
+	out, m := definiteIEs(b)
+	if bytes.Equal(out, b) {
+		return b, nil, false
+	}
+	return out, m, true
+}
+
+// definiteIEs does the actual work of definiteForm.
+func definiteIEs(b []byte) ([]byte, offsetMap) {
+	p := &parser{opts: ParseOptions{Raw: true}, input: b}
+	ies, err := p.parseIEs(b, 2)
+	if err != nil {
+		return b, offsetMap{0: 0, len(b): len(b)}
+	}
+
+	var out []byte
+	m := offsetMap{0: 0}
+	for _, i := range ies {
+		m[len(out)] = i.offset
+		v, sub := i.Value, offsetMap(nil)
+		if i.Tag.Form() == Constructor {
+			v, sub = definiteIEs(v)
+		}
+		e := encodeTLV(NewIE(i.Tag, v))
+		start := len(out) + len(e) - len(v)
+		m[start] = offsetOf(b, i.Value)
+		for o, orig := range sub {
+			m[start+o] = offsetOf(b, i.Value) + orig
+		}
+		out = append(out, e...)
+	}
+	// the end of the innermost IE is kept, as it is the one in error if any.
+	if _, ok := m[len(out)]; !ok {
+		m[len(out)] = len(b)
+	}
+	return out, m
+}
+
+// offsetMap maps the positions of the IEs re-encoded by definiteForm to the ones in the byte sequence given.
+type offsetMap map[int]int
+
+// original returns the position in the byte sequence given for the offset in the one re-encoded,
+// counted from the nearest IE that precedes it.
+func (m offsetMap) original(offset int) int {
+	nearest := -1
+	for o := range m {
+		if o <= offset && o > nearest {
+			nearest = o
+		}
+	}
+	if nearest < 0 {
+		return offset
+	}
+	return m[nearest] + offset - nearest
+}
+
+// originalOffset maps the Offset of err, if it is a ParseError for the byte sequence re-encoded
+// by definiteForm, back to the byte sequence given.
+func (m offsetMap) originalOffset(err error) error {
+	var pe *ParseError
+	if m != nil && errors.As(err, &pe) {
+		pe.Offset = m.original(pe.Offset)
+	}
+	return err
+}
+
+// parseErrorAt returns err as a ParseError for the element at offset in b, within the parents given.
+//
+// If err is a ParseError for the element inside the one at offset, its Offset and Path are
+// adjusted instead, so that the error is reported where it actually occurred.
+func parseErrorAt(err error, b []byte, offset int, parents ...Tag) error {
+	var pe *ParseError
+	if errors.As(err, &pe) {
+		pe.Offset += offset
+		pe.Path = append(append([]Tag{}, parents...), pe.Path...)
+		return pe
+	}
+
+	pe = &ParseError{Offset: offset, Path: append([]Tag{}, parents...), Err: err}
+	if offset < len(b) {
+		pe.Tag, _, _ = ParseTag(b[offset:])
+	}
+	return pe
+}
+
+// offsetOf returns the position of sub in b, which must be sliced from b.
+func offsetOf(b, sub []byte) int {
+	return cap(b) - cap(sub)
+}
+
+// parser holds the state while parsing IEs.
+type parser struct {
+	opts ParseOptions
+
+	// input is the whole byte sequence given, which the offsets recorded are relative to.
+	input []byte
+
+	// recursive makes the children of constructed IEs parsed.
+	recursive bool
+
+	// depth is the nesting level of the IE being parsed.
+	depth int
+}
+
+// newIE returns an empty IE from the Allocator, if any.
+func (p *parser) newIE() *IE {
+	if p.opts.Allocator != nil {
+		return p.opts.Allocator.NewIE()
+	}
+	return &IE{}
+}
+
+// parseIEs parses given byte sequence as multiple IEs, each of which is at least min bytes.
+func (p *parser) parseIEs(b []byte, min int) ([]*IE, error) {
+	var ies []*IE
+	for offset := 0; offset < len(b); {
+		if len(b)-offset < min {
+			return nil, parseErrorAt(io.ErrUnexpectedEOF, b, offset)
+		}
+
+		i := p.newIE()
+		n, err := p.parse(i, b[offset:])
+		if err != nil {
+			return nil, parseErrorAt(err, b, offset)
+		}
+		ies = append(ies, i)
+		offset += n
+	}
+	return ies, nil
+}
+
+// parse sets the values retrieved from byte sequence in an IE, and returns
+// the number of bytes consumed.
+func (p *parser) parse(i *IE, b []byte) (int, error) {
+	n, err := p.parseElement(i, b)
+	if err == nil && p.opts.JoinOctetStrings {
+		i.joinOctetString()
+	}
+	if err == nil && p.opts.Raw {
+		i.raw = b[:n]
+		i.offset = offsetOf(p.input, b)
+	}
+	return n, err
+}
+
+// parseElement does the actual work of parse.
+func (p *parser) parseElement(i *IE, b []byte) (int, error) {
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.opts.MaxDepth > 0 && p.depth > p.opts.MaxDepth {
+		return 0, ErrMaxDepthExceeded
+	}
+
+	var err error
+	tagLen := 0
+	if i.Tag, tagLen, err = ParseTag(b); err != nil {
+		return 0, err
+	}
+	if i.Tag.Form() == Constructor && len(b) > tagLen && b[tagLen] == 0x80 {
+		return p.parseIndefinite(i, b, tagLen+1)
+	}
+
+	lLength := 0
+	if i.Length, lLength, err = UnmarshalAsn1ElementLength(b[tagLen-1:]); err != nil {
+		return 0, err
+	}
+	if err := p.opts.checkLength(i.Length, 0); err != nil {
+		return 0, err
+	}
+	n := tagLen + lLength + i.Length
+	if n > len(b) {
+		return 0, io.ErrUnexpectedEOF
+	}
+	i.Value = b[tagLen+lLength : n]
+
+	if p.recursive && i.Tag.Form() == Constructor {
+		if p.opts.Lazy {
+			i.lazy = &parser{opts: p.opts, input: p.input, recursive: true, depth: p.depth}
+			return n, nil
+		}
+		if err := p.parseChildren(i); err != nil {
+			return 0, parseErrorAt(err, b, tagLen+lLength, i.Tag)
+		}
+	}
+
+	return n, nil
+}
+
+// parseChildren parses the Value of the constructed IE as its children.
+//
+// The contents that are not BER are left unparsed, but the limits are never ignored.
+func (p *parser) parseChildren(i *IE) error {
+	x, err := p.parseIEs(i.Value, 2)
+	if err != nil {
+		if isLimitError(err) {
+			return err
+		}
+		return nil
+	}
+	if p.opts.Parents {
+		for _, c := range x {
+			c.parent = i
+		}
+	}
+	i.IE = append(i.IE, x...)
+	return nil
+}
+
+// DecodeChildren parses the children of the IE deferred by Lazy of ParseOptions, if not yet.
+// It does nothing if the IE is not parsed lazily.
+//
+// The error is the one for the limits in ParseOptions, whose Offset is relative to the byte sequence
+// originally given and whose Path starts from the IE. It is ignored when the children are accessed
+// implicitly, in which case they are left unparsed.
+func (i *IE) DecodeChildren() error {
+	p := i.lazy
+	if p == nil {
+		return nil
+	}
+	i.lazy = nil
+
+	if err := p.parseChildren(i); err != nil {
+		return parseErrorAt(err, p.input, offsetOf(p.input, i.Value), i.Tag)
+	}
+	return nil
+}
+
+// children returns the children of the IE, parsing them first if deferred.
+func (i *IE) children() []*IE {
+	_ = i.DecodeChildren()
+	return i.IE
+}
+
+// parseIndefinite sets the contents starting at offset up to the end-of-contents octets
+// as the Value, and returns the number of bytes consumed including the end-of-contents.
+func (p *parser) parseIndefinite(i *IE, b []byte, offset int) (int, error) {
+	start := offset
+	for {
+		if len(b) < offset+2 {
+			return 0, parseErrorAt(io.ErrUnexpectedEOF, b, offset, i.Tag)
+		}
+		if b[offset] == 0 && b[offset+1] == 0 {
+			break
+		}
+
+		child := p.newIE()
+		n, err := p.parse(child, b[offset:])
+		if err != nil {
+			return 0, parseErrorAt(err, b, offset, i.Tag)
+		}
+		if p.recursive {
+			if p.opts.Parents {
+				child.parent = i
+			}
+			i.IE = append(i.IE, child)
+		}
+		offset += n
+	}
+
+	i.Value = b[start:offset]
+	i.SetLength()
+	if err := p.opts.checkLength(i.Length, 0); err != nil {
+		return 0, err
+	}
+	return offset + 2, nil
+}
@@ -0,0 +1,390 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestParseOptionsCopy(t *testing.T) {
+	b := []byte{0x30, 0x03, 0x04, 0x01, 0xaa}
+
+	shared, err := tcap.ParseAsBER(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	owned, err := tcap.ParseOptions{Copy: true}.ParseAsBER(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b[4] = 0xbb
+	verify.Values(t, "shared", shared[0].IE[0].Value, []byte{0xbb})
+	verify.Values(t, "owned", owned[0].IE[0].Value, []byte{0xaa})
+}
+
+func TestParseOptionsMaxDepth(t *testing.T) {
+	// 30 nested SEQUENCEs around an INTEGER.
+	b := []byte{0x02, 0x01, 0x05}
+	for n := 0; n < 30; n++ {
+		b = append([]byte{0x30, uint8(len(b))}, b...)
+	}
+
+	if _, err := (tcap.ParseOptions{MaxDepth: 31}).ParseAsBER(b); err != nil {
+		t.Errorf("within limit: got %v", err)
+	}
+	if _, err := (tcap.ParseOptions{MaxDepth: 30}).ParseAsBER(b); !errors.Is(err, tcap.ErrMaxDepthExceeded) {
+		t.Errorf("over limit: got %v, want %v", err, tcap.ErrMaxDepthExceeded)
+	}
+
+	indefinite := []byte{0x30, 0x80, 0x30, 0x80, 0x02, 0x01, 0x05, 0x00, 0x00, 0x00, 0x00}
+	if _, err := (tcap.ParseOptions{MaxDepth: 2}).ParseIE(indefinite); !errors.Is(err, tcap.ErrMaxDepthExceeded) {
+		t.Errorf("indefinite over limit: got %v, want %v", err, tcap.ErrMaxDepthExceeded)
+	}
+}
+
+func TestParseTCAPMaxDepth(t *testing.T) {
+	// 20 nested SEQUENCEs in the Parameter of Invoke.
+	param := []byte{0x02, 0x01, 0x05}
+	for n := 0; n < 20; n++ {
+		param = append([]byte{0x30, uint8(len(param))}, param...)
+	}
+	b, err := tcap.NewBeginInvoke(0x11111111, 0, 71, param).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := (tcap.ParseOptions{}).ParseTCAP(b); err != nil {
+		t.Errorf("no limit: got %v", err)
+	}
+	if _, err := (tcap.ParseOptions{MaxDepth: 32}).ParseTCAP(b); err != nil {
+		t.Errorf("within limit: got %v", err)
+	}
+	if _, err := (tcap.ParseOptions{MaxDepth: 16}).ParseTCAP(b); !errors.Is(err, tcap.ErrMaxDepthExceeded) {
+		t.Errorf("over limit: got %v, want %v", err, tcap.ErrMaxDepthExceeded)
+	}
+	if _, _, err := (tcap.ParseOptions{MaxDepth: 16}).ParseTCAPWithRejects(b); !errors.Is(err, tcap.ErrMaxDepthExceeded) {
+		t.Errorf("with rejects: got %v, want %v", err, tcap.ErrMaxDepthExceeded)
+	}
+}
+
+func TestParseTCAPMaxLength(t *testing.T) {
+	o := tcap.ParseOptions{MaxElementLength: 64, MaxMessageLength: 128}
+	var tooLong *tcap.TooLongError
+
+	b, err := tcap.NewBeginInvoke(0x11111111, 0, 71, []byte{0x04, 0x01, 0xaa}).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := o.ParseTCAP(b); err != nil {
+		t.Errorf("within limit: got %v", err)
+	}
+
+	// Begin claiming 16 MiB in long form.
+	claimed := append([]byte{0x62, 0x83, 0xff, 0xff, 0xff}, b[2:]...)
+	if _, err := o.ParseTCAP(claimed); !errors.As(err, &tooLong) || tooLong.Field != "element" {
+		t.Errorf("element: got %v", err)
+	}
+	if _, _, err := o.ParseTCAPWithRejects(claimed); !errors.As(err, &tooLong) || tooLong.Field != "element" {
+		t.Errorf("element with rejects: got %v", err)
+	}
+
+	// Parameter longer than MaxElementLength in a message within MaxMessageLength.
+	long, err := tcap.NewBeginInvoke(0x11111111, 0, 71, append([]byte{0x04, 0x50}, make([]byte, 0x50)...)).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := o.ParseTCAP(long); !errors.As(err, &tooLong) || tooLong.Field != "element" {
+		t.Errorf("parameter: got %v", err)
+	}
+
+	if _, err := o.ParseTCAP(append(b, make([]byte, 128)...)); !errors.As(err, &tooLong) || tooLong.Field != "message" {
+		t.Errorf("message: got %v", err)
+	}
+}
+
+func TestParseOptionsMaxLength(t *testing.T) {
+	o := tcap.ParseOptions{MaxElementLength: 16, MaxMessageLength: 32}
+
+	var tooLong *tcap.TooLongError
+	claimed := []byte{0x30, 0x83, 0x10, 0x00, 0x00, 0x02, 0x01, 0x05}
+	if _, err := o.ParseAsBER(claimed); !errors.As(err, &tooLong) || tooLong.Field != "element" {
+		t.Errorf("element: got %v", err)
+	}
+	if _, err := o.ParseIE(make([]byte, 33)); !errors.As(err, &tooLong) || tooLong.Field != "message" {
+		t.Errorf("message: got %v", err)
+	}
+	if _, err := tcap.ParseIE([]byte{0x04, 0x85, 0x01, 0x00, 0x00, 0x00, 0x00}); !errors.Is(err, tcap.ErrLengthTooLong) {
+		t.Errorf("length field: got %v, want %v", err, tcap.ErrLengthTooLong)
+	}
+
+	d := tcap.NewDecoder(bytes.NewReader(claimed))
+	d.Options = o
+	if _, err := d.ReadElement(); !errors.As(err, &tooLong) {
+		t.Errorf("Decoder: got %v", err)
+	}
+}
+
+func TestParseError(t *testing.T) {
+	b, err := tcap.NewBeginInvoke(0x11111111, 1, 45, nil).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// make the length of Operation Code exceed the message.
+	offset := bytes.Index(b, []byte{0x02, 0x01, 0x2d})
+	b[offset+1] = 0x7f
+
+	_, err = tcap.Parse(b)
+	var pe *tcap.ParseError
+	if !errors.As(err, &pe) {
+		t.Fatalf("got %v, want ParseError", err)
+	}
+	if pe.Offset != offset {
+		t.Errorf("Offset: got %d, want %d", pe.Offset, offset)
+	}
+	if got, want := pe.Tag, tcap.NewUniversalPrimitiveTag(2); got != want {
+		t.Errorf("Tag: got %#x, want %#x", got, want)
+	}
+	verify.Values(t, "Path", pe.Path, []tcap.Tag{0x62, 0x6c, 0xa1})
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Unwrap: got %v, want %v", pe.Err, io.ErrUnexpectedEOF)
+	}
+
+	nested := []byte{0x30, 0x07, 0x30, 0x05, 0x30, 0x03, 0x02, 0x01, 0x05}
+	_, err = tcap.ParseOptions{MaxDepth: 2}.ParseAsBER(nested)
+	if !errors.As(err, &pe) {
+		t.Fatalf("got %v, want ParseError", err)
+	}
+	if pe.Offset != 4 {
+		t.Errorf("Offset: got %d, want 4", pe.Offset)
+	}
+	verify.Values(t, "Path", pe.Path, []tcap.Tag{0x30, 0x30})
+}
+
+func TestParseErrorIndefinite(t *testing.T) {
+	// Begin in indefinite-length form with the OTID of non-minimal length, whose Invoke lacks
+	// the Operation Code, which is found only after the conversion to definite-length form.
+	b := []byte{
+		0x62, 0x80,
+		0x48, 0x81, 0x04, 0x11, 0x11, 0x11, 0x11,
+		0x6c, 0x80,
+		0xa1, 0x80, 0x04, 0x01, 0x01, 0x00, 0x00,
+		0x00, 0x00,
+		0x00, 0x00,
+	}
+
+	_, err := tcap.Parse(b)
+	var pe *tcap.ParseError
+	if !errors.As(err, &pe) {
+		t.Fatalf("got %v, want ParseError", err)
+	}
+	// the end of the contents of Invoke in the byte sequence given.
+	if got, want := pe.Offset, 16; got != want {
+		t.Errorf("Offset: got %d, want %d", got, want)
+	}
+	verify.Values(t, "Path", pe.Path, []tcap.Tag{0x62, 0x6c, 0xa1})
+}
+
+func TestParseOptionsRaw(t *testing.T) {
+	payload := []byte{0xff, 0xff, 0x30, 0x80, 0x02, 0x01, 0x05, 0x00, 0x00}
+
+	ies, err := tcap.ParseOptions{Raw: true}.ParseAsBER(payload[2:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "Raw", ies[0].Raw(), payload[2:])
+	if got, want := ies[0].IE[0].Offset(), 2; got != want {
+		t.Errorf("Offset: got %d, want %d", got, want)
+	}
+	verify.Values(t, "child Raw", ies[0].IE[0].Raw(), []byte{0x02, 0x01, 0x05})
+
+	plain, err := tcap.ParseAsBER(payload[2:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := plain[0].Offset(); got != -1 {
+		t.Errorf("Offset without option: got %d, want -1", got)
+	}
+}
+
+func TestParseOptionsLazy(t *testing.T) {
+	b := []byte{0x30, 0x08, 0xa1, 0x03, 0x02, 0x01, 0x05, 0x04, 0x01, 0xaa}
+
+	ies, err := tcap.ParseOptions{Lazy: true}.ParseAsBER(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ies[0].IE != nil {
+		t.Fatalf("children parsed eagerly: %v", ies[0].IE)
+	}
+
+	found := ies[0].FindFirst(0xa1, 0x02)
+	if found == nil {
+		t.Fatal("not found")
+	}
+	verify.Values(t, "found", found.Value, []byte{0x05})
+	if len(ies[0].IE) != 2 {
+		t.Errorf("children not stored: got %d, want 2", len(ies[0].IE))
+	}
+
+	// 30 nested SEQUENCEs, of which the limit is checked on access.
+	deep := []byte{0x02, 0x01, 0x05}
+	for n := 0; n < 30; n++ {
+		deep = append([]byte{0x30, uint8(len(deep))}, deep...)
+	}
+	ies, err = tcap.ParseOptions{Lazy: true, MaxDepth: 10}.ParseAsBER(deep)
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := ies[0]
+	for n := 1; n < 10; n++ {
+		if err := i.DecodeChildren(); err != nil {
+			t.Fatalf("depth %d: %v", n, err)
+		}
+		i = i.IE[0]
+	}
+	if err := i.DecodeChildren(); !errors.Is(err, tcap.ErrMaxDepthExceeded) {
+		t.Errorf("got %v, want %v", err, tcap.ErrMaxDepthExceeded)
+	}
+}
+
+func TestParseOptionsRawFidelity(t *testing.T) {
+	// Continue with 2-octet Transaction IDs, of which the length of the message is in long form
+	// and the Component Portion is in indefinite-length form.
+	b := []byte{
+		0x65, 0x81, 0x11,
+		0x48, 0x02, 0x12, 0x34,
+		0x49, 0x02, 0x56, 0x78,
+		0x6c, 0x80,
+		0xa1, 0x03, 0x02, 0x01, 0x01,
+		0x00, 0x00,
+	}
+
+	ies, err := tcap.ParseOptions{Raw: true, Parents: true}.ParseAsBER(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ies[0].MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "as is", got, b)
+
+	ies[0].FindFirst(0x49).SetValue([]byte{0x9a, 0xbc})
+	if got, err = ies[0].MarshalBinary(); err != nil {
+		t.Fatal(err)
+	}
+	want := bytes.Clone(b)
+	want[9], want[10] = 0x9a, 0xbc
+	verify.Values(t, "rewritten", got, want)
+
+	plain, err := tcap.ParseAsBER(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err = plain[0].MarshalBinary(); err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(got, b) {
+		t.Error("without Raw: got the original forms")
+	}
+}
+
+func TestParseOptionsStrictDialogue(t *testing.T) {
+	m := tcap.NewBeginDialogue(0x11111111, tcap.ShortMsgMTRelayContext, 3)
+	b, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (tcap.ParseOptions{StrictDialogue: true}).ParseTCAP(b); err != nil {
+		t.Fatalf("on-spec: %v", err)
+	}
+
+	// protocol-version without the unused bits octet, in octet-aligned EXTERNAL.
+	m.Dialogue.DialoguePDU.ProtocolVersion.Value = []byte{0x80}
+	m.Dialogue.SingleAsn1Type.Tag = tcap.NewContextSpecificPrimitiveTag(1)
+	m.SetLength()
+	if b, err = m.MarshalBinary(); err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := tcap.ParseOptions{}.ParseTCAP(b)
+	if err != nil {
+		t.Fatalf("permissive: %v", err)
+	}
+	want := []tcap.Violation{
+		{Field: "SingleAsn1Type", Reason: "encoding is not single-ASN1-type"},
+		{Field: "DialoguePDU.ProtocolVersion", Reason: "malformed BIT STRING, e.g., missing unused bits octet"},
+	}
+	verify.Values(t, "anomalies", parsed.Dialogue.Anomalies(), want)
+	verify.Values(t, "version", parsed.Dialogue.DialoguePDU.Version(), "1")
+
+	_, err = tcap.ParseOptions{StrictDialogue: true}.ParseTCAP(b)
+	var aerr *tcap.DialogueAnomalyError
+	if !errors.As(err, &aerr) {
+		t.Fatalf("strict: got %v, want DialogueAnomalyError", err)
+	}
+	verify.Values(t, "strict anomalies", aerr.Anomalies, want)
+}
+
+func TestParseTCAPWithRejects(t *testing.T) {
+	valid, err := tcap.NewInvoke(1, -1, 46, true, []byte{0x04, 0x01, 0xaa}).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	comps := append(valid,
+		0xa5, 0x03, 0x02, 0x01, 0x02, // unknown component with Invoke ID 2
+		0xa1, 0x03, 0x02, 0x01, 0x03, // Invoke without Operation Code
+		0xa4, 0x03, 0x02, 0x01, 0x04, // Reject without Problem Code, which is not answered
+		0xa1, 0x10, 0x02, 0x01, // truncated
+	)
+	portion := append([]byte{0x6c, byte(len(comps))}, comps...)
+	b, err := (&tcap.TCAP{Transaction: tcap.NewBegin(0x11111111, portion)}).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := tcap.Parse(b); err == nil {
+		t.Fatal("Parse: got no error")
+	}
+	m, rejects, err := tcap.ParseOptions{}.ParseTCAPWithRejects(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "invoke IDs", m.InvokeID(), []uint8{1})
+
+	var problems []tcap.Problem
+	var derivable []bool
+	for _, r := range rejects {
+		p, err := r.Problem()
+		if err != nil {
+			t.Fatal(err)
+		}
+		problems = append(problems, p)
+		derivable = append(derivable, r.IsInvokeIDDerivable())
+	}
+	verify.Values(t, "problems", problems, []tcap.Problem{
+		tcap.ProblemUnrecognizedComponent, tcap.ProblemMistypedComponent, tcap.ProblemBadlyStructuredComponent,
+	})
+	verify.Values(t, "derivable", derivable, []bool{true, true, false})
+	verify.Values(t, "reject invoke IDs", []uint8{rejects[0].InvID(), rejects[1].InvID()}, []uint8{2, 3})
+
+	indefinite := []byte{
+		0x62, 0x80, 0x48, 0x04, 0x11, 0x11, 0x11, 0x11,
+		0x6c, 0x80, 0xa1, 0x80, 0x02, 0x01, 0x01, 0x02, 0x01, 0x2d, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00,
+	}
+	m, rejects, err = tcap.ParseOptions{}.ParseTCAPWithRejects(indefinite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "indefinite-length", []any{m.InvokeID(), len(rejects)}, []any{[]uint8{1}, 0})
+}
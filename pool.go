@@ -0,0 +1,48 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import "sync"
+
+// Allocator provides the IEs to parse into, which can be set in ParseOptions to reuse them.
+type Allocator interface {
+	NewIE() *IE
+}
+
+// Pool is an Allocator backed by sync.Pool, from which the IEs parsed can be returned with Release.
+//
+// The zero value is ready to use. It is safe for concurrent use.
+type Pool struct {
+	pool sync.Pool
+}
+
+// NewIE returns an empty IE from the pool, or a new one if the pool is empty.
+func (p *Pool) NewIE() *IE {
+	if i, ok := p.pool.Get().(*IE); ok {
+		return i
+	}
+	return &IE{}
+}
+
+// Release resets the IE and all its descendants and returns them to the pool.
+//
+// They must not be used after Release, nor the ones retrieved from them (e.g., found with FindAll).
+func (p *Pool) Release(i *IE) {
+	if i == nil {
+		return
+	}
+	for _, c := range i.IE {
+		p.Release(c)
+	}
+	i.Reset()
+	p.pool.Put(i)
+}
+
+// Reset clears the IE to the zero value, keeping the capacity of its slice of children for reuse.
+func (i *IE) Reset() {
+	children := i.IE
+	clear(children)
+	*i = IE{IE: children[:0]}
+}
@@ -0,0 +1,44 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestPool(t *testing.T) {
+	b := []byte{0x30, 0x06, 0x04, 0x01, 0xaa, 0x02, 0x01, 0x05}
+
+	var pool tcap.Pool
+	o := tcap.ParseOptions{Allocator: &pool}
+	for n := 0; n < 3; n++ {
+		ies, err := o.ParseAsBER(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []*tcap.IE{tcap.NewIE(0x30, b[2:])}
+		want[0].IE = []*tcap.IE{tcap.NewIE(0x04, []byte{0xaa}), tcap.NewIE(0x02, []byte{0x05})}
+		if !tcap.Equal(ies[0], want[0]) {
+			t.Errorf("round %d: got %v", n, ies[0])
+		}
+		for _, i := range ies {
+			pool.Release(i)
+		}
+	}
+}
+
+func TestIEReset(t *testing.T) {
+	i := tcap.NewIE(0x30, []byte{0x04, 0x01, 0xaa})
+	i.IE = []*tcap.IE{tcap.NewIE(0x04, []byte{0xaa})}
+	i.Reset()
+
+	verify.Values(t, "tag", i.Tag, tcap.Tag(0))
+	verify.Values(t, "length", i.Length, 0)
+	verify.Values(t, "value", len(i.Value), 0)
+	verify.Values(t, "children", len(i.IE), 0)
+}
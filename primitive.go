@@ -0,0 +1,42 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import "errors"
+
+// Errors returned when decoding the value of IE as BOOLEAN or NULL.
+var (
+	ErrInvalidBoolean = errors.New("tcap: BOOLEAN must be one octet")
+	ErrInvalidNull    = errors.New("tcap: NULL must have no octets")
+)
+
+// NewBoolean creates a new IE with v encoded as a BOOLEAN, where TRUE is 0xff.
+func NewBoolean(tag Tag, v bool) *IE {
+	if v {
+		return NewIE(tag, []byte{0xff})
+	}
+	return NewIE(tag, []byte{0x00})
+}
+
+// Bool returns the value of IE decoded as a BOOLEAN, where any non-zero octet is TRUE.
+func (i *IE) Bool() (bool, error) {
+	if len(i.Value) != 1 {
+		return false, ErrInvalidBoolean
+	}
+	return i.Value[0] != 0, nil
+}
+
+// NewNull creates a new IE with no value, i.e., a NULL.
+func NewNull(tag Tag) *IE {
+	return NewIE(tag, []byte{})
+}
+
+// Null returns nil if the IE is a valid NULL, i.e., it has no value.
+func (i *IE) Null() error {
+	if len(i.Value) != 0 {
+		return ErrInvalidNull
+	}
+	return nil
+}
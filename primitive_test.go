@@ -0,0 +1,57 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestBoolean(t *testing.T) {
+	tag := tcap.NewUniversalPrimitiveTag(1)
+
+	for _, v := range []bool{true, false} {
+		b, err := tcap.NewBoolean(tag, v).MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		i, err := tcap.ParseIE(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := i.Bool()
+		if err != nil {
+			t.Fatal(err)
+		}
+		verify.Values(t, "bool", got, v)
+	}
+
+	if got, _ := tcap.NewIE(tag, []byte{0x01}).Bool(); !got {
+		t.Error("non-zero octet: got false, want true")
+	}
+	if _, err := tcap.NewIE(tag, []byte{0x00, 0x00}).Bool(); !errors.Is(err, tcap.ErrInvalidBoolean) {
+		t.Errorf("got %v, want %v", err, tcap.ErrInvalidBoolean)
+	}
+}
+
+func TestNull(t *testing.T) {
+	tag := tcap.NewUniversalPrimitiveTag(5)
+
+	b, err := tcap.NewNull(tag).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "encoded", b, []byte{0x05, 0x00})
+
+	if err := tcap.NewNull(tag).Null(); err != nil {
+		t.Error(err)
+	}
+	if err := tcap.NewIE(tag, []byte{0x00}).Null(); !errors.Is(err, tcap.ErrInvalidNull) {
+		t.Errorf("got %v, want %v", err, tcap.ErrInvalidNull)
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import (
+	"errors"
+	"strings"
+	"sync"
+)
+
+// ErrInvalidTIDLength is returned when TIDLength of PeerProfile is out of range.
+var ErrInvalidTIDLength = errors.New("tcap: TID length must be 0-4 octets")
+
+// PeerProfile represents the encoding quirks to be applied to the TCAP messages sent to a specific peer.
+//
+// Real networks are full of per-vendor deviations, and some peers refuse the messages
+// that are valid but not encoded in the way they expect.
+type PeerProfile struct {
+	// TIDLength is the length of Transaction IDs in octets (1-4) the peer expects.
+	// The IDs are truncated to the lower octets or padded with zeros. Zero leaves them as they are.
+	TIDLength int
+
+	// OmitDialogueInContinue removes the Dialogue Portion from Continue messages,
+	// for the peers that cannot handle the optional Dialogue Portion after the dialogue is established.
+	OmitDialogueInContinue bool
+}
+
+// Validate checks if the PeerProfile can be applied.
+func (p *PeerProfile) Validate() error {
+	if p.TIDLength < 0 || p.TIDLength > 4 {
+		return ErrInvalidTIDLength
+	}
+	return nil
+}
+
+// Apply modifies the TCAP given according to the PeerProfile and updates the lengths.
+func (p *PeerProfile) Apply(t *TCAP) {
+	if p == nil || t == nil {
+		return
+	}
+
+	if tx := t.Transaction; tx != nil {
+		if p.TIDLength > 0 {
+			resizeTID(tx.OrigTransactionID, p.TIDLength)
+			resizeTID(tx.DestTransactionID, p.TIDLength)
+		}
+
+		if p.OmitDialogueInContinue && tx.Type.Code() == Continue {
+			t.Dialogue = nil
+		}
+	}
+
+	t.SetLength()
+}
+
+// resizeTID truncates the Transaction ID to the lower octets or pads it with zeros to fit in l octets.
+func resizeTID(tid *IE, l int) {
+	if tid == nil {
+		return
+	}
+
+	v := tid.Value
+	switch {
+	case len(v) > l:
+		v = v[len(v)-l:]
+	case len(v) < l:
+		v = append(make([]byte, l-len(v)), v...)
+	}
+	tid.Value = append([]byte{}, v...)
+	tid.SetLength()
+}
+
+// PeerProfiles is a set of PeerProfile keyed by Global Title prefix or Point Code.
+//
+// It is safe for concurrent use.
+type PeerProfiles struct {
+	mu   sync.RWMutex
+	byGT map[string]*PeerProfile
+	byPC map[uint32]*PeerProfile
+
+	// Default is used when no PeerProfile matches.
+	Default *PeerProfile
+}
+
+// NewPeerProfiles creates a new empty PeerProfiles.
+func NewPeerProfiles() *PeerProfiles {
+	return &PeerProfiles{
+		byGT: map[string]*PeerProfile{},
+		byPC: map[uint32]*PeerProfile{},
+	}
+}
+
+// SetGT sets the PeerProfile for the peers whose Global Title digits start with prefix.
+func (p *PeerProfiles) SetGT(prefix string, profile *PeerProfile) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.byGT[prefix] = profile
+}
+
+// SetPC sets the PeerProfile for the peer with the Point Code.
+func (p *PeerProfiles) SetPC(pc uint32, profile *PeerProfile) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.byPC[pc] = profile
+}
+
+// Replace replaces all the PeerProfiles at once with the ones keyed by Global Title prefix
+// and Point Code, so that the routing table can be reloaded at runtime without a restart.
+//
+// The maps given are copied. Nothing is changed if any of the PeerProfiles is invalid.
+func (p *PeerProfiles) Replace(byGT map[string]*PeerProfile, byPC map[uint32]*PeerProfile) error {
+	gts := make(map[string]*PeerProfile, len(byGT))
+	for prefix, profile := range byGT {
+		if err := profile.Validate(); err != nil {
+			return err
+		}
+		gts[prefix] = profile
+	}
+	pcs := make(map[uint32]*PeerProfile, len(byPC))
+	for pc, profile := range byPC {
+		if err := profile.Validate(); err != nil {
+			return err
+		}
+		pcs[pc] = profile
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.byGT, p.byPC = gts, pcs
+	return nil
+}
+
+// Lookup returns the PeerProfile for the peer identified by the Global Title digits and Point Code.
+//
+// The longest Global Title prefix matched takes precedence over the Point Code.
+// Default is returned if none of them matches, which can be nil.
+func (p *PeerProfiles) Lookup(gt string, pc uint32) *PeerProfile {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var (
+		found   *PeerProfile
+		longest = -1
+	)
+	for prefix, profile := range p.byGT {
+		if len(prefix) > longest && strings.HasPrefix(gt, prefix) {
+			found, longest = profile, len(prefix)
+		}
+	}
+	if found != nil {
+		return found
+	}
+
+	if profile, ok := p.byPC[pc]; ok {
+		return profile
+	}
+	return p.Default
+}
@@ -0,0 +1,77 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestPeerProfile(t *testing.T) {
+	profiles := tcap.NewPeerProfiles()
+	profiles.SetGT("8190", &tcap.PeerProfile{TIDLength: 2})
+	profiles.SetGT("81901", &tcap.PeerProfile{TIDLength: 1, OmitDialogueInContinue: true})
+	profiles.SetPC(1234, &tcap.PeerProfile{TIDLength: 3})
+
+	if got, want := profiles.Lookup("819012345", 0).TIDLength, 1; got != want {
+		t.Errorf("longest prefix: got %d, want %d", got, want)
+	}
+	if got, want := profiles.Lookup("819099999", 1234).TIDLength, 2; got != want {
+		t.Errorf("GT over PC: got %d, want %d", got, want)
+	}
+	if got, want := profiles.Lookup("4412345", 1234).TIDLength, 3; got != want {
+		t.Errorf("PC: got %d, want %d", got, want)
+	}
+	if got := profiles.Lookup("4412345", 1); got != nil {
+		t.Errorf("no match: got %v, want nil", got)
+	}
+
+	m := tcap.NewContinueInvoke(0x11223344, 0x55667788, 1, 59, nil)
+	m.Dialogue = tcap.NewDialogue(1, 1, tcap.NewAARE(1, tcap.NetworkUnstructuredSsContext, 2, tcap.Accepted, tcap.DialogueServiceUser, tcap.Null), []byte{})
+	m.SetLength()
+	profiles.Lookup("819012345", 0).Apply(m)
+
+	b, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := m.Dialogue, (*tcap.Dialogue)(nil); got != want {
+		t.Errorf("Dialogue: got %v, want nil", got)
+	}
+	want := []byte{
+		0x65, 0x10, 0x48, 0x01, 0x44, 0x49, 0x01, 0x88,
+		0x6c, 0x08, 0xa1, 0x06, 0x02, 0x01, 0x01, 0x02, 0x01, 0x3b,
+	}
+	if got := b; !verify.Values(t, "", got, want) {
+		t.Fail()
+	}
+}
+
+func TestPeerProfilesReplace(t *testing.T) {
+	profiles := tcap.NewPeerProfiles()
+	profiles.SetGT("8190", &tcap.PeerProfile{TIDLength: 2})
+
+	if err := profiles.Replace(map[string]*tcap.PeerProfile{"44": {TIDLength: 5}}, nil); err != tcap.ErrInvalidTIDLength {
+		t.Errorf("invalid: got %v, want %v", err, tcap.ErrInvalidTIDLength)
+	}
+	if got, want := profiles.Lookup("819012345", 0).TIDLength, 2; got != want {
+		t.Errorf("after failed Replace: got %d, want %d", got, want)
+	}
+
+	if err := profiles.Replace(map[string]*tcap.PeerProfile{"44": {TIDLength: 3}}, map[uint32]*tcap.PeerProfile{1234: {TIDLength: 1}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := profiles.Lookup("819012345", 0); got != nil {
+		t.Errorf("removed GT: got %v, want nil", got)
+	}
+	if got, want := profiles.Lookup("4412345", 0).TIDLength, 3; got != want {
+		t.Errorf("GT: got %d, want %d", got, want)
+	}
+	if got, want := profiles.Lookup("", 1234).TIDLength, 1; got != want {
+		t.Errorf("PC: got %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrComponentTooLarge is returned when a single Component does not fit in a message.
+var ErrComponentTooLarge = errors.New("tcap: component does not fit in a message")
+
+// ComponentQueue buffers the Components to be sent, and packs them into messages in order,
+// as many as fit in MaxLen octets each.
+//
+// It is safe for concurrent use.
+type ComponentQueue struct {
+	mu      sync.Mutex
+	pending []*Component
+
+	// MaxLen is the maximum length of a TCAP message, typically derived from the SCCP MTU.
+	MaxLen int
+}
+
+// NewComponentQueue creates a new empty ComponentQueue.
+func NewComponentQueue(maxLen int) *ComponentQueue {
+	return &ComponentQueue{MaxLen: maxLen}
+}
+
+// Push appends the Components to the queue.
+func (q *ComponentQueue) Push(comps ...*Component) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.pending = append(q.pending, comps...)
+}
+
+// Len returns the number of Components pending.
+func (q *ComponentQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.pending)
+}
+
+// Next puts as many pending Components as fit in MaxLen into the message given, in order,
+// and removes them from the queue. The Components already in the message are kept in front.
+//
+// It returns true if Components are still pending, which should be sent in the next Continue.
+func (q *ComponentQueue) Next(t *TCAP) (bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	n, err := q.fill(t)
+	if err != nil {
+		return false, err
+	}
+	q.pending = q.pending[n:]
+	return len(q.pending) > 0, nil
+}
+
+// Flush packs all the pending Components into messages and empties the queue.
+//
+// The message is created by newMessage for each, with last set to true for the one that
+// carries the last Component, e.g., Continue for all but the last one, which is End.
+// The messages returned should be sent in order.
+func (q *ComponentQueue) Flush(newMessage func(last bool) *TCAP) ([]*TCAP, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var msgs []*TCAP
+	for len(q.pending) > 0 {
+		// try as the last first, so that the one fits all is built with it.
+		t := newMessage(true)
+		n, err := q.fill(t)
+		if err != nil {
+			return nil, err
+		}
+		if n < len(q.pending) {
+			t = newMessage(false)
+			if n, err = q.fill(t); err != nil {
+				return nil, err
+			}
+		}
+		q.pending = q.pending[n:]
+		msgs = append(msgs, t)
+	}
+	return msgs, nil
+}
+
+// fill puts the pending Components into t as many as fit, and returns the number of them.
+func (q *ComponentQueue) fill(t *TCAP) (int, error) {
+	var existing []*Component
+	if t.Components != nil {
+		existing = t.Components.Component
+	}
+
+	n := 0
+	for n < len(q.pending) {
+		comps := append(append([]*Component{}, existing...), q.pending[:n+1]...)
+		t.Components = NewComponents(comps...)
+		t.SetLength()
+		if t.MarshalLen() > q.MaxLen {
+			break
+		}
+		n++
+	}
+
+	if n == 0 && len(q.pending) > 0 && len(existing) == 0 {
+		return 0, ErrComponentTooLarge
+	}
+
+	comps := append(append([]*Component{}, existing...), q.pending[:n]...)
+	if len(comps) == 0 {
+		t.Components = nil
+	} else {
+		t.Components = NewComponents(comps...)
+	}
+	t.SetLength()
+	return n, nil
+}
@@ -0,0 +1,72 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestComponentQueue(t *testing.T) {
+	param := append([]byte{0x04, 0x28}, bytes.Repeat([]byte{0xaa}, 40)...)
+
+	q := tcap.NewComponentQueue(120)
+	for i := 0; i < 5; i++ {
+		q.Push(tcap.NewReturnResult(i, 45, true, true, param))
+	}
+
+	msgs, err := q.Flush(func(last bool) *tcap.TCAP {
+		if last {
+			return &tcap.TCAP{Transaction: tcap.NewEnd(0x11111111, []byte{})}
+		}
+		return &tcap.TCAP{Transaction: tcap.NewContinue(0x22222222, 0x11111111, []byte{})}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Len() != 0 {
+		t.Errorf("Len: got %d, want 0", q.Len())
+	}
+
+	var types []string
+	var ids []uint8
+	for _, m := range msgs {
+		b, err := m.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(b) > 120 {
+			t.Errorf("message too long: %d", len(b))
+		}
+		parsed, err := tcap.Parse(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		types = append(types, parsed.Transaction.MessageTypeString())
+		ids = append(ids, parsed.InvokeID()...)
+	}
+	if len(types) < 2 {
+		t.Fatalf("not overflowed: got %d messages", len(types))
+	}
+	for i, typ := range types {
+		want := "Continue"
+		if i == len(types)-1 {
+			want = "End"
+		}
+		if typ != want {
+			t.Errorf("message %d: got %s, want %s", i, typ, want)
+		}
+	}
+	verify.Values(t, "invoke IDs", ids, []uint8{0, 1, 2, 3, 4})
+
+	q.Push(tcap.NewReturnResult(0, 45, true, true, append([]byte{0x04, 0x81, 0xc8}, bytes.Repeat([]byte{0xaa}, 200)...)))
+	if _, err := q.Next(&tcap.TCAP{Transaction: tcap.NewEnd(1, []byte{})}); !errors.Is(err, tcap.ErrComponentTooLarge) {
+		t.Errorf("too large: got %v, want %v", err, tcap.ErrComponentTooLarge)
+	}
+}
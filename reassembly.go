@@ -0,0 +1,97 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrNotReturnResult is returned when the Component given to ResultReassembler is not a ReturnResult.
+var ErrNotReturnResult = errors.New("tcap: not a ReturnResult")
+
+// ResultReassembler concatenates the parameters of the ReturnResultNotLast Components followed by
+// the ReturnResultLast for the same Invoke ID, so that the segmented result is seen as one.
+//
+// It is safe for concurrent use.
+type ResultReassembler struct {
+	mu      sync.Mutex
+	pending map[uint8]*Component
+}
+
+// NewResultReassembler creates a new ResultReassembler.
+func NewResultReassembler() *ResultReassembler {
+	return &ResultReassembler{pending: map[uint8]*Component{}}
+}
+
+// Add adds the ReturnResult received. It returns the ReturnResultLast with the parameters of all the
+// segments concatenated in order and true when c is the ReturnResultLast, or nil and false when c is
+// a ReturnResultNotLast, which is kept until the last one.
+//
+// The parameter assembled has the tag of the first segment and the contents of all the segments.
+// The ReturnResultLast without any preceding segment is returned as it is.
+func (r *ResultReassembler) Add(c *Component) (*Component, bool, error) {
+	code := c.Type.Code()
+	if code != ReturnResultLast && code != ReturnResultNotLast {
+		return nil, false, ErrNotReturnResult
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := c.InvID()
+	first, ok := r.pending[id]
+	if code == ReturnResultNotLast {
+		if !ok {
+			first = c.Clone()
+			first.Type = NewContextSpecificConstructorTag(ReturnResultLast)
+			r.pending[id] = first
+			return nil, false, nil
+		}
+		appendParameter(first, c)
+		return nil, false, nil
+	}
+
+	if !ok {
+		return c, true, nil
+	}
+	delete(r.pending, id)
+	appendParameter(first, c)
+	first.SetLength()
+	return first, true, nil
+}
+
+// appendParameter appends the contents of the parameter of c to the one of dst.
+func appendParameter(dst, c *Component) {
+	if c.Parameter == nil {
+		return
+	}
+	if dst.Parameter == nil {
+		dst.Parameter = c.Parameter.Clone()
+		if dst.OperationCode == nil && c.OperationCode != nil {
+			dst.OperationCode = c.OperationCode.Clone()
+			dst.ResultRetres = NewIE(NewUniversalConstructorTag(0x10), nil)
+		}
+		return
+	}
+	v := append(append([]byte{}, dst.Parameter.Value...), c.Parameter.Value...)
+	dst.Parameter = NewIE(dst.Parameter.Tag, v)
+}
+
+// Pending returns the number of Invoke IDs whose results are not completed yet.
+func (r *ResultReassembler) Pending() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.pending)
+}
+
+// Discard drops the segments received for the Invoke ID, e.g., when the dialogue is aborted.
+func (r *ResultReassembler) Discard(invID uint8) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.pending, invID)
+}
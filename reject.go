@@ -0,0 +1,122 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidProblem is returned when the Component is not a Reject or its problem is malformed.
+var ErrInvalidProblem = errors.New("tcap: invalid reject problem")
+
+// Problem is the problem in Reject, i.e., the Problem Type and the Problem Code of the type.
+type Problem struct {
+	// Type is GeneralProblem, InvokeProblem, ReturnResultProblem or ReturnErrorProblem.
+	Type int
+
+	// Code is one of the Problem Code definitions of the Type.
+	Code uint8
+}
+
+// Problems defined in Q.773.
+var (
+	ProblemUnrecognizedComponent    = Problem{GeneralProblem, UnrecognizedComponent}
+	ProblemMistypedComponent        = Problem{GeneralProblem, MistypedComponent}
+	ProblemBadlyStructuredComponent = Problem{GeneralProblem, BadlyStructuredComponent}
+
+	ProblemDuplicateInvokeID         = Problem{InvokeProblem, InvokeProblemDuplicateInvokeID}
+	ProblemUnrecognizedOperation     = Problem{InvokeProblem, InvokeProblemUnrecognizedOperation}
+	ProblemInvokeMistypedParameter   = Problem{InvokeProblem, InvokeProblemMistypedParameter}
+	ProblemResourceLimitation        = Problem{InvokeProblem, InvokeProblemResourceLimitation}
+	ProblemInitiatingRelease         = Problem{InvokeProblem, InvokeProblemInitiatingRelease}
+	ProblemUnrecognizedLinkedID      = Problem{InvokeProblem, InvokeProblemUnrecognizedLinkedID}
+	ProblemLinkedResponseUnexpected  = Problem{InvokeProblem, InvokeProblemLinkedResponseUnexpected}
+	ProblemUnexpectedLinkedOperation = Problem{InvokeProblem, InvokeProblemUnexpectedLinkedOperation}
+
+	ProblemResultUnrecognizedInvokeID = Problem{ReturnResultProblem, ResultProblemUnrecognizedInvokeID}
+	ProblemReturnResultUnexpected     = Problem{ReturnResultProblem, ResultProblemReturnResultUnexpected}
+	ProblemResultMistypedParameter    = Problem{ReturnResultProblem, ResultProblemMistypedParameter}
+
+	ProblemErrorUnrecognizedInvokeID = Problem{ReturnErrorProblem, ErrorProblemUnrecognizedInvokeID}
+	ProblemReturnErrorUnexpected     = Problem{ReturnErrorProblem, ErrorProblemReturnErrorUnexpected}
+	ProblemUnrecognizedError         = Problem{ReturnErrorProblem, ErrorProblemUnrecognizedError}
+	ProblemUnexpectedError           = Problem{ReturnErrorProblem, ErrorProblemUnexpectedError}
+	ProblemErrorMistypedParameter    = Problem{ReturnErrorProblem, ErrorProblemMistypedParameter}
+)
+
+var problemNames = map[Problem]string{
+	ProblemUnrecognizedComponent:      "unrecognizedComponent",
+	ProblemMistypedComponent:          "mistypedComponent",
+	ProblemBadlyStructuredComponent:   "badlyStructuredComponent",
+	ProblemDuplicateInvokeID:          "duplicateInvokeID",
+	ProblemUnrecognizedOperation:      "unrecognizedOperation",
+	ProblemInvokeMistypedParameter:    "mistypedParameter",
+	ProblemResourceLimitation:         "resourceLimitation",
+	ProblemInitiatingRelease:          "initiatingRelease",
+	ProblemUnrecognizedLinkedID:       "unrecognizedLinkedID",
+	ProblemLinkedResponseUnexpected:   "linkedResponseUnexpected",
+	ProblemUnexpectedLinkedOperation:  "unexpectedLinkedOperation",
+	ProblemResultUnrecognizedInvokeID: "unrecognizedInvokeID",
+	ProblemReturnResultUnexpected:     "returnResultUnexpected",
+	ProblemResultMistypedParameter:    "mistypedParameter",
+	ProblemErrorUnrecognizedInvokeID:  "unrecognizedInvokeID",
+	ProblemReturnErrorUnexpected:      "returnErrorUnexpected",
+	ProblemUnrecognizedError:          "unrecognizedError",
+	ProblemUnexpectedError:            "unexpectedError",
+	ProblemErrorMistypedParameter:     "mistypedParameter",
+}
+
+// String returns Problem in the form of "type: code", e.g., "invoke: duplicateInvokeID".
+func (p Problem) String() string {
+	var typ string
+	switch p.Type {
+	case GeneralProblem:
+		typ = "general"
+	case InvokeProblem:
+		typ = "invoke"
+	case ReturnResultProblem:
+		typ = "returnResult"
+	case ReturnErrorProblem:
+		typ = "returnError"
+	default:
+		typ = fmt.Sprintf("%d", p.Type)
+	}
+	if name, ok := problemNames[p]; ok {
+		return typ + ": " + name
+	}
+	return fmt.Sprintf("%s: %d", typ, p.Code)
+}
+
+// NewRejectWithProblem returns a new single Reject Component of the Invoke ID and the Problem.
+func NewRejectWithProblem(invID int, p Problem) *Component {
+	return NewReject(invID, p.Type, p.Code, nil)
+}
+
+// NewRejectNotDerivable returns a new single Reject Component of the Problem, whose Invoke ID is
+// NULL as it is not derivable from the Component received, e.g., a badly structured one.
+func NewRejectNotDerivable(p Problem) *Component {
+	c := NewRejectWithProblem(0, p)
+	c.InvokeID = NewIE(NewUniversalPrimitiveTag(5), nil)
+	c.SetLength()
+	return c
+}
+
+// Problem returns the Problem of the Reject.
+func (c *Component) Problem() (Problem, error) {
+	pc := c.ProblemCode
+	if c.Type.Code() != Reject || pc == nil || pc.Tag.Class() != ContextSpecific || len(pc.Value) != 1 {
+		return Problem{}, ErrInvalidProblem
+	}
+	if t := pc.Tag.Code(); t < GeneralProblem || t > ReturnErrorProblem {
+		return Problem{}, ErrInvalidProblem
+	}
+	return Problem{Type: pc.Tag.Code(), Code: pc.Value[0]}, nil
+}
+
+// IsInvokeIDDerivable reports whether the Invoke ID of the Reject is not NULL.
+func (c *Component) IsInvokeIDDerivable() bool {
+	return c.InvokeID != nil && c.InvokeID.Tag != NewUniversalPrimitiveTag(5)
+}
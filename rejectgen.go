@@ -0,0 +1,124 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import "io"
+
+// ParseComponentsWithRejects parses given byte sequence as an Components, like ParseComponents,
+// but the Components that cannot be parsed are skipped instead of failing the whole Component Portion.
+//
+// For each of the ones skipped, a Reject ready to be sent back is returned in order, with the
+// Problem and the Invoke ID as Q.774 specifies:
+//   - UnrecognizedComponent, if the tag of the Component is not known.
+//   - MistypedComponent, if the elements in the Component cannot be parsed.
+//   - BadlyStructuredComponent, if the Component itself cannot be parsed, in which case the rest
+//     of the Component Portion is discarded.
+//
+// The Invoke ID of the Reject is the one of the Component if it is derivable, or NULL otherwise.
+// The malformed Rejects are discarded without being answered, as Q.774 specifies.
+// The error is returned only if the Component Portion itself is malformed. Components is nil if no
+// Component can be parsed.
+func ParseComponentsWithRejects(b []byte) (*Components, []*Component, error) {
+	if len(b) < 2 {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+	portion, err := splitIEs(b)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var comps, rejects []*Component
+	data := portion[0].Value
+	for len(data) > 0 {
+		i := &IE{}
+		n, err := (&parser{}).parse(i, data)
+		if err != nil {
+			if tag, _, err := ParseTag(data); err != nil || tag != rejectTag {
+				rejects = append(rejects, NewRejectNotDerivable(ProblemBadlyStructuredComponent))
+			}
+			break
+		}
+
+		c, err := ParseComponent(data[:n])
+		switch {
+		case i.Tag == rejectTag && err != nil:
+			// a Reject is never answered with a Reject, which the peers could loop with.
+		case !isComponentTag(i.Tag):
+			rejects = append(rejects, newRejectFor(i, ProblemUnrecognizedComponent))
+		case err != nil:
+			rejects = append(rejects, newRejectFor(i, ProblemMistypedComponent))
+		default:
+			comps = append(comps, c)
+		}
+		data = data[n:]
+	}
+
+	if len(comps) == 0 {
+		return nil, rejects, nil
+	}
+	return NewComponents(comps...), rejects, nil
+}
+
+// ParseTCAPWithRejects parses given byte sequence as a TCAP with the options, like ParseTCAP,
+// but the Components that cannot be parsed are replaced by the Rejects to be sent back, as
+// ParseComponentsWithRejects does. The message returned contains the rest of the Components.
+func (o ParseOptions) ParseTCAPWithRejects(b []byte) (*TCAP, []*Component, error) {
+	if err := o.checkLimits(b); err != nil {
+		return nil, nil, err
+	}
+
+	// the Components in indefinite-length form would be taken as malformed.
+	b, m, _ := definiteForm(b)
+
+	t := &TCAP{}
+	payload, err := t.unmarshalPortions(b)
+	if err != nil {
+		return nil, nil, m.originalOffset(err)
+	}
+	if o.StrictDialogue && t.Dialogue != nil {
+		if v := t.Dialogue.Anomalies(); len(v) > 0 {
+			return nil, nil, &DialogueAnomalyError{Anomalies: v}
+		}
+	}
+	if len(payload) == 0 {
+		return t, nil, nil
+	}
+
+	var rejects []*Component
+	t.Components, rejects, err = ParseComponentsWithRejects(payload)
+	if err != nil {
+		return nil, nil, m.originalOffset(parseErrorAt(err, b, offsetOf(b, payload), t.Transaction.Type))
+	}
+	return t, rejects, nil
+}
+
+// rejectTag is the tag of Reject.
+var rejectTag = NewContextSpecificConstructorTag(Reject)
+
+// isComponentTag reports whether the tag is the one of the Component types known.
+func isComponentTag(tag Tag) bool {
+	if tag.Class() != ContextSpecific || tag.Form() != Constructor {
+		return false
+	}
+	switch tag.Code() {
+	case Invoke, ReturnResultLast, ReturnError, Reject, ReturnResultNotLast:
+		return true
+	}
+	return false
+}
+
+// newRejectFor returns a new Reject of the Problem for the Component given as an IE, with the
+// Invoke ID derived from its first element if it is a valid one.
+func newRejectFor(i *IE, p Problem) *Component {
+	elems, err := splitIEs(i.Value)
+	if err != nil || len(elems) == 0 || elems[0].Tag != NewUniversalPrimitiveTag(2) {
+		return NewRejectNotDerivable(p)
+	}
+	id, err := elems[0].Int64()
+	if err != nil || id < -128 || id > 127 {
+		return NewRejectNotDerivable(p)
+	}
+	return NewRejectWithProblem(int(id), p)
+}
@@ -0,0 +1,127 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidResult is returned when the result or result-source-diagnostic in AARE is malformed.
+var ErrInvalidResult = errors.New("tcap: invalid associate result")
+
+// AssociateResult is the result in AARE, i.e., Accepted or RejectPerm.
+type AssociateResult uint8
+
+// String returns the name of AssociateResult as in Q.773.
+func (r AssociateResult) String() string {
+	switch uint8(r) {
+	case Accepted:
+		return "accepted"
+	case RejectPerm:
+		return "reject-permanent"
+	}
+	return fmt.Sprintf("%d", uint8(r))
+}
+
+// SourceDiagnostic is the result-source-diagnostic in AARE.
+type SourceDiagnostic struct {
+	// Source is DialogueServiceUser or DialogueServiceProvider.
+	Source int
+
+	// Reason is the diagnostic of the Source, e.g., ApplicationContextNameNotSupported of
+	// DialogueServiceUser or NoCommonDialoguePortion of DialogueServiceProvider.
+	Reason uint8
+}
+
+// String returns SourceDiagnostic in the form of "source: reason".
+func (s SourceDiagnostic) String() string {
+	var src, reason string
+	switch s.Source {
+	case DialogueServiceUser:
+		src = "dialogue-service-user"
+		switch s.Reason {
+		case Null:
+			reason = "null"
+		case NoReasonGiven:
+			reason = "no-reason-given"
+		case ApplicationContextNameNotSupported:
+			reason = "application-context-name-not-supported"
+		}
+	case DialogueServiceProvider:
+		src = "dialogue-service-provider"
+		switch s.Reason {
+		case Null:
+			reason = "null"
+		case NoReasonGiven:
+			reason = "no-reason-given"
+		case NoCommonDialoguePortion:
+			reason = "no-common-dialogue-portion"
+		}
+	default:
+		src = fmt.Sprintf("%d", s.Source)
+	}
+	if reason == "" {
+		reason = fmt.Sprintf("%d", s.Reason)
+	}
+	return src + ": " + reason
+}
+
+// NewAAREWithResult returns a new AARE with the typed result and result-source-diagnostic.
+func NewAAREWithResult(protover int, context, contextver uint8, result AssociateResult, diag SourceDiagnostic, userinfo ...*IE) *DialoguePDU {
+	return NewAARE(protover, context, contextver, uint8(result), diag.Source, diag.Reason, userinfo...)
+}
+
+// SetResult sets the result and result-source-diagnostic of the AARE, and updates the lengths.
+func (d *DialoguePDU) SetResult(result AssociateResult, diag SourceDiagnostic) {
+	d.Result = NewResult(uint8(result))
+	d.ResultSourceDiagnostic = NewResultSourceDiagnostic(diag.Source, diag.Reason)
+	d.SetLength()
+}
+
+// AssociateResult returns the result of the AARE.
+func (d *DialoguePDU) AssociateResult() (AssociateResult, error) {
+	if d.Type.Code() != AARE || d.Result == nil {
+		return 0, ErrInvalidResult
+	}
+	v, err := parseResultInteger(d.Result.Value)
+	if err != nil {
+		return 0, err
+	}
+	return AssociateResult(v), nil
+}
+
+// SourceDiagnostic returns the result-source-diagnostic of the AARE.
+func (d *DialoguePDU) SourceDiagnostic() (SourceDiagnostic, error) {
+	if d.Type.Code() != AARE || d.ResultSourceDiagnostic == nil {
+		return SourceDiagnostic{}, ErrInvalidResult
+	}
+	choice, err := ParseIE(d.ResultSourceDiagnostic.Value)
+	if err != nil {
+		return SourceDiagnostic{}, ErrInvalidResult
+	}
+	src := choice.Tag.Code()
+	if choice.Tag.Class() != ContextSpecific || (src != DialogueServiceUser && src != DialogueServiceProvider) {
+		return SourceDiagnostic{}, ErrInvalidResult
+	}
+	v, err := parseResultInteger(choice.Value)
+	if err != nil {
+		return SourceDiagnostic{}, err
+	}
+	return SourceDiagnostic{Source: src, Reason: uint8(v)}, nil
+}
+
+// parseResultInteger parses b as the INTEGER wrapped in result or result-source-diagnostic.
+func parseResultInteger(b []byte) (int64, error) {
+	i, err := ParseIE(b)
+	if err != nil || i.Tag != NewUniversalPrimitiveTag(2) {
+		return 0, ErrInvalidResult
+	}
+	v, err := i.Int64()
+	if err != nil || v < 0 || v > 0xff {
+		return 0, ErrInvalidResult
+	}
+	return v, nil
+}
@@ -0,0 +1,55 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestAssociateResult(t *testing.T) {
+	diag := tcap.SourceDiagnostic{Source: tcap.DialogueServiceUser, Reason: tcap.ApplicationContextNameNotSupported}
+	aare := tcap.NewAAREWithResult(1, tcap.ShortMsgMTRelayContext, 3, tcap.AssociateResult(tcap.RejectPerm), diag)
+
+	b, err := aare.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := tcap.ParseDialoguePDU(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := parsed.AssociateResult()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "result", res.String(), "reject-permanent")
+
+	got, err := parsed.SourceDiagnostic()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "diagnostic", got, diag)
+	verify.Values(t, "diagnostic string", got.String(), "dialogue-service-user: application-context-name-not-supported")
+
+	parsed.SetResult(tcap.AssociateResult(tcap.Accepted), tcap.SourceDiagnostic{Source: tcap.DialogueServiceProvider, Reason: tcap.NoCommonDialoguePortion})
+	if res, _ := parsed.AssociateResult(); res != tcap.AssociateResult(tcap.Accepted) {
+		t.Errorf("result after SetResult: got %s", res)
+	}
+	if got, _ := parsed.SourceDiagnostic(); got.String() != "dialogue-service-provider: no-common-dialogue-portion" {
+		t.Errorf("diagnostic after SetResult: got %s", got)
+	}
+	if parsed.Length != aare.Length {
+		t.Errorf("Length after SetResult: got %d, want %d", parsed.Length, aare.Length)
+	}
+
+	if _, err := tcap.NewAARQ(1, tcap.ShortMsgMTRelayContext, 3).AssociateResult(); !errors.Is(err, tcap.ErrInvalidResult) {
+		t.Errorf("AARQ: got %v, want %v", err, tcap.ErrInvalidResult)
+	}
+}
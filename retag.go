@@ -0,0 +1,55 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import "errors"
+
+// ErrNotSingleElement is returned by Unwrap when the value does not consist of exactly one element.
+var ErrNotSingleElement = errors.New("tcap: value is not a single element")
+
+// Retag returns a copy of IE with its tag replaced by the class and number of the tag
+// given, as IMPLICIT tagging does.
+//
+// The form (primitive or constructed) of the original IE is kept, as it is determined
+// by the underlying type, not by the tag given.
+func Retag(i *IE, tag Tag) *IE {
+	return &IE{
+		Tag:    tag&^0x20 | i.Tag&0x20,
+		Length: i.Length,
+		Value:  append([]byte{}, i.Value...),
+		IE:     i.IE,
+	}
+}
+
+// ExplicitWrap wraps the encoding of IE in a constructed IE with the tag, as EXPLICIT tagging does.
+func ExplicitWrap(tag Tag, i *IE) (*IE, error) {
+	b, err := i.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	w := NewIE(tag|0x20, b)
+	w.IE = []*IE{i}
+	return w, nil
+}
+
+// Unwrap returns the IE wrapped by EXPLICIT tagging.
+//
+// InvalidTagError is returned if the IE is not constructed, and ErrNotSingleElement
+// if it does not contain exactly one element.
+func Unwrap(i *IE) (*IE, error) {
+	if i.Tag.Form() != 1 {
+		return nil, &InvalidTagError{Tag: i.Tag}
+	}
+
+	inner, err := ParseIERecursive(i.Value)
+	if err != nil {
+		return nil, err
+	}
+	if inner.MarshalLen() != len(i.Value) {
+		return nil, ErrNotSingleElement
+	}
+	return inner, nil
+}
@@ -0,0 +1,51 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestRetag(t *testing.T) {
+	octets := tcap.NewIE(tcap.NewUniversalPrimitiveTag(4), []byte{0x01, 0x02})
+	seq := tcap.NewIE(tcap.NewUniversalConstructorTag(16), []byte{0x80, 0x00})
+
+	implicit := tcap.Retag(octets, tcap.NewContextSpecificConstructorTag(1))
+	if got, want := implicit.Tag, tcap.NewContextSpecificPrimitiveTag(1); got != want {
+		t.Errorf("primitive: got %#x, want %#x", got, want)
+	}
+	implicit = tcap.Retag(seq, tcap.NewContextSpecificPrimitiveTag(1))
+	if got, want := implicit.Tag, tcap.NewContextSpecificConstructorTag(1); got != want {
+		t.Errorf("constructed: got %#x, want %#x", got, want)
+	}
+
+	explicit, err := tcap.ExplicitWrap(tcap.NewContextSpecificPrimitiveTag(2), octets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := explicit.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "explicit", b, []byte{0xa2, 0x04, 0x04, 0x02, 0x01, 0x02})
+
+	inner, err := tcap.Unwrap(explicit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "unwrapped", inner.Value, octets.Value)
+
+	if _, err := tcap.Unwrap(octets); err == nil {
+		t.Error("primitive: got nil error")
+	}
+	two := tcap.NewIE(tcap.NewContextSpecificConstructorTag(2), []byte{0x80, 0x01, 0x00, 0x81, 0x01, 0x00})
+	if _, err := tcap.Unwrap(two); !errors.Is(err, tcap.ErrNotSingleElement) {
+		t.Errorf("two elements: got %v, want %v", err, tcap.ErrNotSingleElement)
+	}
+}
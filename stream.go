@@ -0,0 +1,69 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import (
+	"bytes"
+	"io"
+)
+
+// StreamIE is an IE whose value is supplied as an io.Reader with a known length.
+//
+// It is for very large values that should be streamed to the wire without being held
+// fully in memory. The value is read only once, in WriteTo.
+type StreamIE struct {
+	Tag
+	Length int
+	Reader io.Reader
+}
+
+// NewStreamIE creates a new StreamIE that reads length bytes of value from r.
+func NewStreamIE(tag Tag, r io.Reader, length int) *StreamIE {
+	return &StreamIE{
+		Tag:    tag,
+		Length: length,
+		Reader: r,
+	}
+}
+
+// Wrap returns a new constructed StreamIE with the tag that contains the StreamIE.
+//
+// The StreamIE must not be used after this, as its Reader is consumed by the new one.
+func (s *StreamIE) Wrap(tag Tag) *StreamIE {
+	return &StreamIE{
+		Tag:    tag | 0x20,
+		Length: s.MarshalLen(),
+		Reader: io.MultiReader(bytes.NewReader(s.header()), io.LimitReader(s.Reader, int64(s.Length))),
+	}
+}
+
+// MarshalLen returns the serial length of StreamIE.
+func (s *StreamIE) MarshalLen() int {
+	return len(s.header()) + s.Length
+}
+
+// WriteTo writes the tag, length and value read from Reader to w.
+//
+// io.ErrUnexpectedEOF is returned if Reader has less than Length bytes.
+func (s *StreamIE) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(s.header())
+	written := int64(n)
+	if err != nil {
+		return written, err
+	}
+
+	m, err := io.CopyN(w, s.Reader, int64(s.Length))
+	written += m
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return written, err
+}
+
+func (s *StreamIE) header() []byte {
+	b := make([]byte, s.Tag.MarshalLen())
+	_, _ = s.Tag.MarshalTo(b)
+	return append(b, MarshalAsn1ElementLength(s.Length)...)
+}
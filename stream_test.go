@@ -0,0 +1,42 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestStreamIE(t *testing.T) {
+	value := bytes.Repeat([]byte{0xab}, 300)
+
+	s := tcap.NewStreamIE(tcap.NewUniversalPrimitiveTag(4), bytes.NewReader(value), len(value)).
+		Wrap(tcap.NewUniversalConstructorTag(16))
+
+	var buf bytes.Buffer
+	n, err := s.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := n, int64(s.MarshalLen()); got != want {
+		t.Errorf("written: got %d, want %d", got, want)
+	}
+
+	// the result must be the same as the one of IE
+	inner := tcap.NewIE(tcap.NewUniversalPrimitiveTag(4), value)
+	ib, _ := inner.MarshalBinary()
+	want, _ := tcap.NewIE(tcap.NewUniversalConstructorTag(16), ib).MarshalBinary()
+	verify.Values(t, "encoded", buf.Bytes(), want)
+
+	short := tcap.NewStreamIE(tcap.NewUniversalPrimitiveTag(4), bytes.NewReader(value[:10]), 20)
+	if _, err := short.WriteTo(io.Discard); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("short reader: got %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+}
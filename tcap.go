@@ -13,6 +13,7 @@ package tcap
 import (
 	"encoding/binary"
 	"fmt"
+	"io"
 )
 
 // TCAP represents a General Structure of TCAP Information Elements.
@@ -42,6 +43,27 @@ func NewBeginInvokeWithDialogue(otid uint32, dlgType, ctx, ctxver uint8, invID,
 	return t
 }
 
+// NewBeginDialogue creates a new TCAP of type Transaction=Begin with AARQ in Dialogue Portion
+// and no Component Portion, e.g., to open a dialogue whose Invokes are sent in the next Continue.
+func NewBeginDialogue(otid uint32, ctx, ctxver uint8) *TCAP {
+	return NewBeginMessage(WithOTID(otid), WithDialoguePDU(NewAARQ(1, ctx, ctxver)))
+}
+
+// NewContinueDialogue creates a new TCAP of type Transaction=Continue with AARE(accepted) in
+// Dialogue Portion and no Component Portion, which confirms the dialogue opened by the peer.
+func NewContinueDialogue(otid, dtid uint32, ctx, ctxver uint8) *TCAP {
+	return NewContinueMessage(
+		WithOTID(otid), WithDTID(dtid),
+		WithDialoguePDU(NewAARE(1, ctx, ctxver, Accepted, DialogueServiceUser, Null)),
+	)
+}
+
+// NewEmptyContinue creates a new TCAP of type Transaction=Continue with neither Dialogue Portion
+// nor Component Portion, e.g., to keep the transaction alive.
+func NewEmptyContinue(otid, dtid uint32) *TCAP {
+	return NewContinueMessage(WithOTID(otid), WithDTID(dtid))
+}
+
 // NewContinueInvoke creates a new TCAP of type Transaction=Continue, Component=Invoke.
 func NewContinueInvoke(otid, dtid uint32, invID, opCode int, payload []byte) *TCAP {
 	t := &TCAP{
@@ -73,6 +95,62 @@ func NewEndReturnResultWithDialogue(dtid uint32, dlgType, ctx, ctxver uint8, inv
 	return t
 }
 
+// NewUnidirectionalInvoke creates a new TCAP of type Transaction=Unidirectional, Component=Invoke
+// with AUDT in Dialogue Portion, which never opens a transaction.
+func NewUnidirectionalInvoke(ctx, ctxver uint8, invID, opCode int, payload []byte) *TCAP {
+	t := &TCAP{
+		Transaction: NewUnidirectional([]byte{}),
+		Dialogue:    NewDialogue(UnidialogueAsID, 1, NewAUDT(1, ctx, ctxver), []byte{}),
+		Components:  NewComponents(NewInvoke(invID, -1, opCode, true, payload)),
+	}
+	t.SetLength()
+
+	return t
+}
+
+// NewUserAbort creates a new TCAP of type Transaction=Abort with ABRT(dialogue-service-user) in Dialogue Portion.
+//
+// The EXTERNALs given (e.g., the one created by NewMAPUserAbortInfo) are put in the user information.
+func NewUserAbort(dtid uint32, externals ...*IE) *TCAP {
+	abrt := NewABRT(uint8(AbortDialogueServiceUser))
+	abrt.SetUserInformation(externals...)
+
+	t := &TCAP{
+		Transaction: NewAbort(dtid, 0, []byte{}),
+		Dialogue:    NewDialogue(DialogueAsID, 1, abrt, []byte{}),
+	}
+	t.Transaction.PAbortCause = nil
+	t.SetLength()
+
+	return t
+}
+
+// NewACNNotSupported creates a new TCAP that rejects the dialogue because the application context
+// proposed is not supported, with AARE(reject-permanent, application-context-name-not-supported)
+// in Dialogue Portion.
+//
+// The ctx and ctxver are of the alternative application context the responder supports.
+// It is Abort by default as TC-U-ABORT in Q.771, but End can be used if isEnd is true,
+// which some implementations expect.
+func NewACNNotSupported(dtid uint32, ctx, ctxver uint8, isEnd bool) *TCAP {
+	t := &TCAP{
+		Dialogue: NewDialogue(
+			DialogueAsID, 1,
+			NewAARE(1, ctx, ctxver, RejectPerm, DialogueServiceUser, ApplicationContextNameNotSupported),
+			[]byte{},
+		),
+	}
+	if isEnd {
+		t.Transaction = NewEnd(dtid, []byte{})
+	} else {
+		t.Transaction = NewAbort(dtid, 0, []byte{})
+		t.Transaction.PAbortCause = nil
+	}
+	t.SetLength()
+
+	return t
+}
+
 // MarshalBinary returns the byte sequence generated from a TCAP instance.
 func (t *TCAP) MarshalBinary() ([]byte, error) {
 	b := make([]byte, t.MarshalLen())
@@ -82,6 +160,16 @@ func (t *TCAP) MarshalBinary() ([]byte, error) {
 	return b, nil
 }
 
+// AppendBinary appends the byte sequence generated from a TCAP instance to b.
+func (t *TCAP) AppendBinary(b []byte) ([]byte, error) {
+	return appendBinary(b, t)
+}
+
+// WriteTo writes the byte sequence generated from a TCAP instance to w.
+func (t *TCAP) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(w, t)
+}
+
 // MarshalTo puts the byte sequence in the byte array given as b.
 func (t *TCAP) MarshalTo(b []byte) error {
 	var offset = 0
@@ -119,40 +207,100 @@ func Parse(b []byte) (*TCAP, error) {
 }
 
 // UnmarshalBinary sets the values retrieved from byte sequence in a TCAP.
+//
+// The error returned is a ParseError, which tells where in the byte sequence it failed.
+//
+// The constructed IEs in indefinite-length form are accepted, in which case the IEs parsed
+// refer to the definite-length form re-encoded, while the Payloads of the Transaction and
+// Dialogue refer to the byte sequence given.
 func (t *TCAP) UnmarshalBinary(b []byte) error {
+	if err := t.unmarshalBinary(b); err != nil {
+		d, m, ok := definiteForm(b)
+		if !ok {
+			return err
+		}
+		*t = TCAP{}
+		if err := t.unmarshalBinary(d); err != nil {
+			return m.originalOffset(err)
+		}
+		t.originalPayloads(b)
+	}
+	return nil
+}
+
+// originalPayloads points the Payloads of the Transaction and Dialogue at the portions
+// in the byte sequence as it is, i.e., in indefinite-length form, instead of the ones re-encoded.
+func (t *TCAP) originalPayloads(b []byte) {
+	p := &parser{opts: ParseOptions{Raw: true}, input: b}
+	ies, err := p.parseIEs(b, 2)
+	if err != nil || len(ies) == 0 {
+		return
+	}
+	contents := ies[0].Value
+	portions, err := p.parseIEs(contents, 2)
+	if err != nil {
+		return
+	}
+	first := true
+	for _, i := range portions {
+		switch i.Tag {
+		case NewApplicationWideConstructorTag(11), NewApplicationWideConstructorTag(12):
+		default:
+			continue
+		}
+		if first {
+			t.Transaction.Payload = contents[offsetOf(contents, i.Raw()):]
+			first = false
+		}
+		if t.Dialogue != nil && i.Tag == NewApplicationWideConstructorTag(12) {
+			t.Dialogue.Payload = i.Raw()
+		}
+	}
+}
+
+// unmarshalBinary does the actual work of UnmarshalBinary for the definite-length form.
+func (t *TCAP) unmarshalBinary(b []byte) error {
+	payload, err := t.unmarshalPortions(b)
+	if err != nil || len(payload) == 0 {
+		return err
+	}
+
+	t.Components, err = ParseComponents(payload)
+	if err != nil {
+		return parseErrorAt(err, b, offsetOf(b, payload), t.Transaction.Type)
+	}
+	return nil
+}
+
+// unmarshalPortions sets the Transaction and Dialogue retrieved from byte sequence in a TCAP,
+// and returns the Component Portion left, which is nil if absent.
+func (t *TCAP) unmarshalPortions(b []byte) ([]byte, error) {
 	var err error
 	var offset = 0
 
 	t.Transaction, err = ParseTransaction(b[offset:])
 	if err != nil {
-		return err
+		return nil, parseErrorAt(err, b, offset)
 	}
 	if len(t.Transaction.Payload) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	switch t.Transaction.Payload[0] {
 	case 0x6b:
 		t.Dialogue, err = ParseDialogue(t.Transaction.Payload)
 		if err != nil {
-			return err
+			return nil, parseErrorAt(err, b, offsetOf(b, t.Transaction.Payload), t.Transaction.Type)
 		}
 		if len(t.Dialogue.Payload) == 0 {
-			return nil
-		}
-
-		t.Components, err = ParseComponents(t.Dialogue.Payload)
-		if err != nil {
-			return err
+			return nil, nil
 		}
+		return t.Dialogue.Payload, nil
 	case 0x6c:
-		t.Components, err = ParseComponents(t.Transaction.Payload)
-		if err != nil {
-			return err
-		}
+		return t.Transaction.Payload, nil
 	}
 
-	return nil
+	return nil, nil
 }
 
 // ParseBer parses given byte sequence as a TCAP.
@@ -360,9 +508,41 @@ func (t *TCAP) LayerPayload() [][]byte {
 	return nil
 }
 
+// IsACNNotSupported reports whether the TCAP rejects the dialogue because the application context
+// proposed is not supported, and returns the alternative application context in the AARE.
+//
+// The alternative can be used to retry the dialogue, e.g., with the lower version of MAP.
+func (t *TCAP) IsACNNotSupported() (ctx, ctxver uint8, ok bool) {
+	if t.Dialogue == nil || t.Dialogue.DialoguePDU == nil {
+		return 0, 0, false
+	}
+
+	pdu := t.Dialogue.DialoguePDU
+	if res, err := pdu.AssociateResult(); err != nil || res != AssociateResult(RejectPerm) {
+		return 0, 0, false
+	}
+	diag, err := pdu.SourceDiagnostic()
+	if err != nil || diag != (SourceDiagnostic{DialogueServiceUser, ApplicationContextNameNotSupported}) {
+		return 0, 0, false
+	}
+
+	if acn := pdu.ApplicationContextName; acn != nil && len(acn.Value) >= 9 {
+		ctx, ctxver = acn.Value[7], acn.Value[8]
+	}
+	return ctx, ctxver, true
+}
+
+// PAbort returns the P-Abort Cause in Transaction Portion, and whether the TCAP is TC-P-ABORT.
+func (t *TCAP) PAbort() (PAbortCause, bool) {
+	if t.Transaction == nil {
+		return 0, false
+	}
+	return t.Transaction.PAbort()
+}
+
 // String returns TCAP in human readable string.
 func (t *TCAP) String() string {
-	return fmt.Sprintf("{Transaction: %v, Dialogue: %v, Components: %v}",
+	return fmt.Sprintf("{Transaction: %s, Dialogue: %s, Components: %s}",
 		t.Transaction,
 		t.Dialogue,
 		t.Components,
@@ -0,0 +1,123 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"sync"
+)
+
+// ErrNoTransactionID is returned when no transaction ID that is not in use can be generated.
+var ErrNoTransactionID = errors.New("tcap: no transaction ID available")
+
+// minTIDAttempts is the minimum number of candidates tried before giving up.
+const minTIDAttempts = 64
+
+// TIDGenerator generates the Originating Transaction IDs, which never collide with
+// the ones of the transactions currently open.
+//
+// It is safe for concurrent use.
+type TIDGenerator struct {
+	mu     sync.Mutex
+	inUse  map[uint32]struct{}
+	source func() (uint32, error)
+}
+
+// NewTIDGenerator creates a new TIDGenerator generating the IDs from crypto/rand,
+// so that they cannot be guessed by the peers.
+func NewTIDGenerator() *TIDGenerator {
+	return NewTIDGeneratorFunc(randomTID)
+}
+
+// NewSequentialTIDGenerator creates a new TIDGenerator generating the IDs in sequence from start,
+// wrapping around at the maximum, e.g., for the tests and the traces easy to follow.
+func NewSequentialTIDGenerator(start uint32) *TIDGenerator {
+	next := start
+	return NewTIDGeneratorFunc(func() (uint32, error) {
+		tid := next
+		next++
+		return tid, nil
+	})
+}
+
+// NewTIDGeneratorFunc creates a new TIDGenerator generating the candidates of IDs with source,
+// which is called with the lock held.
+func NewTIDGeneratorFunc(source func() (uint32, error)) *TIDGenerator {
+	return &TIDGenerator{
+		inUse:  make(map[uint32]struct{}),
+		source: source,
+	}
+}
+
+// randomTID returns a random ID from crypto/rand.
+func randomTID() (uint32, error) {
+	var b [4]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint32(b[:]), nil
+}
+
+// Allocate returns a new ID not in use, and marks it in use until Release.
+//
+// ErrNoTransactionID is returned if all the candidates tried are in use.
+func (g *TIDGenerator) Allocate() (uint32, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	// a sequence finds the one not in use within the number of the ones in use.
+	attempts := max(minTIDAttempts, len(g.inUse)+1)
+	for n := 0; n < attempts; n++ {
+		tid, err := g.source()
+		if err != nil {
+			return 0, err
+		}
+		if _, ok := g.inUse[tid]; !ok {
+			g.inUse[tid] = struct{}{}
+			return tid, nil
+		}
+	}
+	return 0, ErrNoTransactionID
+}
+
+// Reserve marks the ID in use, e.g., the one of a transaction restored, and reports
+// whether it was not in use.
+func (g *TIDGenerator) Reserve(tid uint32) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, ok := g.inUse[tid]; ok {
+		return false
+	}
+	g.inUse[tid] = struct{}{}
+	return true
+}
+
+// Release marks the ID not in use, which should be called when the transaction is closed.
+func (g *TIDGenerator) Release(tid uint32) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	delete(g.inUse, tid)
+}
+
+// InUse reports whether the ID is in use.
+func (g *TIDGenerator) InUse(tid uint32) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	_, ok := g.inUse[tid]
+	return ok
+}
+
+// Len returns the number of the IDs in use.
+func (g *TIDGenerator) Len() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return len(g.inUse)
+}
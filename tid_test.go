@@ -0,0 +1,63 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestTIDGenerator(t *testing.T) {
+	g := tcap.NewTIDGenerator()
+	seen := make(map[uint32]bool)
+	for n := 0; n < 1000; n++ {
+		tid, err := g.Allocate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seen[tid] {
+			t.Fatalf("collision: %#x", tid)
+		}
+		seen[tid] = true
+	}
+	verify.Values(t, "Len", g.Len(), 1000)
+}
+
+func TestSequentialTIDGenerator(t *testing.T) {
+	g := tcap.NewSequentialTIDGenerator(0xfffffffe)
+	if !g.Reserve(0) {
+		t.Fatal("Reserve: already in use")
+	}
+
+	var tids []uint32
+	for n := 0; n < 3; n++ {
+		tid, err := g.Allocate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		tids = append(tids, tid)
+	}
+	// 0 is skipped as reserved.
+	verify.Values(t, "tids", tids, []uint32{0xfffffffe, 0xffffffff, 1})
+
+	g.Release(0xffffffff)
+	if g.InUse(0xffffffff) {
+		t.Error("released but in use")
+	}
+	if g.Reserve(1) {
+		t.Error("Reserve: got true for the one in use")
+	}
+
+	full := tcap.NewTIDGeneratorFunc(func() (uint32, error) { return 7, nil })
+	if _, err := full.Allocate(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := full.Allocate(); !errors.Is(err, tcap.ErrNoTransactionID) {
+		t.Errorf("got %v, want %v", err, tcap.ErrNoTransactionID)
+	}
+}
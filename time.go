@@ -0,0 +1,73 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrInvalidTime is returned when the value of IE is not a valid UTCTime or GeneralizedTime.
+var ErrInvalidTime = errors.New("tcap: invalid UTCTime or GeneralizedTime")
+
+// The layouts accepted in decoding, from the shortest form. The fractional seconds are accepted
+// after the seconds, and the ones without the zone are in UTC.
+var (
+	utcTimeLayouts = []string{
+		"0601021504Z0700",
+		"060102150405Z0700",
+	}
+	generalizedTimeLayouts = []string{
+		"2006010215Z0700",
+		"200601021504Z0700",
+		"20060102150405Z0700",
+		"2006010215",
+		"200601021504",
+		"20060102150405",
+	}
+)
+
+// NewUTCTime creates a new IE with t encoded as a UTCTime in UTC, e.g., "240131235959Z".
+//
+// The fractional seconds are truncated, as UTCTime cannot represent them.
+func NewUTCTime(tag Tag, t time.Time) *IE {
+	return NewIE(tag, []byte(t.UTC().Format("060102150405Z")))
+}
+
+// UTCTime returns the value of IE decoded as a UTCTime, with the zone offset if any.
+//
+// The two-digit years from 50 to 99 are in 19xx, and the others are in 20xx.
+func (i *IE) UTCTime() (time.Time, error) {
+	t, err := parseTime(string(i.Value), utcTimeLayouts)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if t.Year() >= 2050 {
+		t = t.AddDate(-100, 0, 0)
+	}
+	return t, nil
+}
+
+// NewGeneralizedTime creates a new IE with t encoded as a GeneralizedTime in UTC,
+// with the fractional seconds if any, e.g., "20240131235959.123Z".
+func NewGeneralizedTime(tag Tag, t time.Time) *IE {
+	return NewIE(tag, []byte(t.UTC().Format("20060102150405.999999999Z")))
+}
+
+// GeneralizedTime returns the value of IE decoded as a GeneralizedTime, with the fractional
+// seconds and the zone offset if any. The local time without the zone is taken as in UTC.
+func (i *IE) GeneralizedTime() (time.Time, error) {
+	return parseTime(string(i.Value), generalizedTimeLayouts)
+}
+
+// parseTime parses s with the first layout that matches.
+func parseTime(s string, layouts []string) (time.Time, error) {
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, ErrInvalidTime
+}
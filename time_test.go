@@ -0,0 +1,74 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestUTCTime(t *testing.T) {
+	tag := tcap.NewUniversalPrimitiveTag(23)
+
+	tm := time.Date(2024, 1, 31, 23, 59, 59, 500, time.UTC)
+	i := tcap.NewUTCTime(tag, tm)
+	verify.Values(t, "encoded", string(i.Value), "240131235959Z")
+
+	cases := []struct {
+		value string
+		want  time.Time
+	}{
+		{"240131235959Z", tm.Truncate(time.Second)},
+		{"9912312359Z", time.Date(1999, 12, 31, 23, 59, 0, 0, time.UTC)},
+		{"240201083000+0900", time.Date(2024, 1, 31, 23, 30, 0, 0, time.UTC)},
+	}
+	for _, c := range cases {
+		got, err := tcap.NewIE(tag, []byte(c.value)).UTCTime()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(c.want) {
+			t.Errorf("%s: got %v, want %v", c.value, got, c.want)
+		}
+	}
+
+	if _, err := tcap.NewIE(tag, []byte("2401312359")).UTCTime(); !errors.Is(err, tcap.ErrInvalidTime) {
+		t.Errorf("got %v, want %v", err, tcap.ErrInvalidTime)
+	}
+}
+
+func TestGeneralizedTime(t *testing.T) {
+	tag := tcap.NewUniversalPrimitiveTag(24)
+
+	tm := time.Date(2024, 1, 31, 23, 59, 59, 123000000, time.UTC)
+	i := tcap.NewGeneralizedTime(tag, tm)
+	verify.Values(t, "encoded", string(i.Value), "20240131235959.123Z")
+
+	cases := []struct {
+		value string
+		want  time.Time
+	}{
+		{"20240131235959.123Z", tm},
+		{"20240131235959,5-0130", time.Date(2024, 2, 1, 1, 29, 59, 500000000, time.UTC)},
+		{"2024013123", time.Date(2024, 1, 31, 23, 0, 0, 0, time.UTC)},
+	}
+	for _, c := range cases {
+		got, err := tcap.NewIE(tag, []byte(c.value)).GeneralizedTime()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(c.want) {
+			t.Errorf("%s: got %v, want %v", c.value, got, c.want)
+		}
+	}
+
+	if _, err := tcap.NewIE(tag, []byte("20241331")).GeneralizedTime(); !errors.Is(err, tcap.ErrInvalidTime) {
+		t.Errorf("got %v, want %v", err, tcap.ErrInvalidTime)
+	}
+}
@@ -30,6 +30,26 @@ const (
 	ResourceLimitation
 )
 
+// PAbortCause is the P-Abort Cause, i.e., the values of the Abort Cause definitions.
+type PAbortCause uint8
+
+// String returns the name of PAbortCause.
+func (c PAbortCause) String() string {
+	switch uint8(c) {
+	case UnrecognizedMessageType:
+		return "UnrecognizedMessageType"
+	case UnrecognizedTransactionID:
+		return "UnrecognizedTransactionID"
+	case BadlyFormattedTransactionPortion:
+		return "BadlyFormattedTransactionPortion"
+	case IncorrectTransactionPortion:
+		return "IncorrectTransactionPortion"
+	case ResourceLimitation:
+		return "ResourceLimitation"
+	}
+	return fmt.Sprintf("%d", uint8(c))
+}
+
 // Transaction represents a Transaction Portion of TCAP.
 type Transaction struct {
 	Type              Tag
@@ -147,13 +167,23 @@ func (t *Transaction) MarshalBinary() ([]byte, error) {
 	return b, nil
 }
 
+// AppendBinary appends the byte sequence generated from a Transaction instance to b.
+func (t *Transaction) AppendBinary(b []byte) ([]byte, error) {
+	return appendBinary(b, t)
+}
+
+// WriteTo writes the byte sequence generated from a Transaction instance to w.
+func (t *Transaction) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(w, t)
+}
+
 // MarshalTo puts the byte sequence in the byte array given as b.
 func (t *Transaction) MarshalTo(b []byte) error {
 	// 1. Calculate the length header bytes (e.g., [0x32] or [0x81, 0xB1])
 	lenBytes := MarshalAsn1ElementLength(t.Length)
 
 	// 2. Ensure the provided buffer can fit Tag (1) + Length Header + Value
-	totalNeeded := 1 + len(lenBytes) + t.MarshalLen()
+	totalNeeded := t.MarshalLen()
 	if len(b) < totalNeeded {
 		return io.ErrShortBuffer
 	}
@@ -164,7 +194,7 @@ func (t *Transaction) MarshalTo(b []byte) error {
 	// 4. Copy the Length Header starting at index 1
 	copy(b[1:], lenBytes)
 
-	var offset = 2
+	var offset = 1 + len(lenBytes)
 	switch t.Type.Code() {
 	case Unidirectional:
 		break
@@ -211,7 +241,7 @@ func (t *Transaction) MarshalTo(b []byte) error {
 			offset += field.MarshalLen()
 		}
 	}
-	copy(b[offset:t.MarshalLen()], t.Payload)
+	copy(b[offset:totalNeeded], t.Payload)
 	return nil
 }
 
@@ -245,35 +275,40 @@ func (t *Transaction) UnmarshalBinary(b []byte) error {
 	case Begin:
 		t.OrigTransactionID, err = ParseIE(b[offset:])
 		if err != nil {
-			return err
+			return parseErrorAt(err, b, offset, t.Type)
 		}
 		offset += t.OrigTransactionID.MarshalLen()
 	case End:
 		t.DestTransactionID, err = ParseIE(b[offset:])
 		if err != nil {
-			return err
+			return parseErrorAt(err, b, offset, t.Type)
 		}
 		offset += t.DestTransactionID.MarshalLen()
 	case Continue:
 		t.OrigTransactionID, err = ParseIE(b[offset:])
 		if err != nil {
-			return err
+			return parseErrorAt(err, b, offset, t.Type)
 		}
 		offset += t.OrigTransactionID.MarshalLen()
 		t.DestTransactionID, err = ParseIE(b[offset:])
 		if err != nil {
-			return err
+			return parseErrorAt(err, b, offset, t.Type)
 		}
 		offset += t.DestTransactionID.MarshalLen()
 	case Abort:
 		t.DestTransactionID, err = ParseIE(b[offset:])
 		if err != nil {
-			return err
+			return parseErrorAt(err, b, offset, t.Type)
 		}
 		offset += t.DestTransactionID.MarshalLen()
+
+		// U-Abort has the Dialogue Portion instead of P-Abort Cause.
+		if offset >= len(b) || b[offset] != 0x4a {
+			break
+		}
 		t.PAbortCause, err = ParseIE(b[offset:])
 		if err != nil {
-			return err
+			return parseErrorAt(err, b, offset, t.Type)
 		}
 		offset += t.PAbortCause.MarshalLen()
 	}
@@ -300,7 +335,12 @@ func (t *Transaction) SetValsFrom(berParsed *IE) error {
 
 // MarshalLen returns the serial length of Transaction.
 func (t *Transaction) MarshalLen() int {
-	l := 2
+	return headerLen(t.Type, t.Length) + t.valueLen()
+}
+
+// valueLen returns the length of the fields and Payload in Transaction.
+func (t *Transaction) valueLen() int {
+	l := 0
 	switch t.Type.Code() {
 	case Unidirectional:
 		break
@@ -341,7 +381,7 @@ func (t *Transaction) SetLength() {
 	if field := t.PAbortCause; field != nil {
 		field.SetLength()
 	}
-	t.Length = t.MarshalLen() - 2
+	t.Length = t.valueLen()
 }
 
 // MessageTypeString returns the name of Message Type in string.
@@ -407,9 +447,18 @@ func (t *Transaction) AbortCause() string {
 	return ""
 }
 
+// PAbort returns the P-Abort Cause, and whether the Transaction is an Abort with it, i.e., TC-P-ABORT.
+// It returns false for TC-U-ABORT, which has the Dialogue Portion instead.
+func (t *Transaction) PAbort() (PAbortCause, bool) {
+	if t.Type.Code() != Abort || t.PAbortCause == nil || len(t.PAbortCause.Value) != 1 {
+		return 0, false
+	}
+	return PAbortCause(t.PAbortCause.Value[0]), true
+}
+
 // String returns Transaction in human readable string.
 func (t *Transaction) String() string {
-	return fmt.Sprintf("{Type: %#x, Length: %d, OrigTransactionID: %v, DestTransactionID: %v, PAbortCause: %v, Payload: %x}",
+	return fmt.Sprintf("{Type: %#x, Length: %d, OrigTransactionID: %s, DestTransactionID: %s, PAbortCause: %s, Payload: %x}",
 		t.Type,
 		t.Length,
 		t.OrigTransactionID,
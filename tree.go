@@ -0,0 +1,215 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import (
+	"errors"
+	"slices"
+)
+
+// SkipChildren is used as a return value from the function passed to Walk
+// to indicate that the children of the IE are to be skipped.
+var SkipChildren = errors.New("tcap: skip children")
+
+// Parent returns the parent of the IE, which is nil for the root.
+//
+// The parent is known only for the IEs added with AddChild or parsed with Parents in ParseOptions.
+func (i *IE) Parent() *IE {
+	return i.parent
+}
+
+// Root returns the outermost ancestor of the IE, or the IE itself if it has no parent.
+func (i *IE) Root() *IE {
+	r := i
+	for r.parent != nil {
+		r = r.parent
+	}
+	return r
+}
+
+// Path returns the tags from the root to the IE, including its own.
+func (i *IE) Path() []Tag {
+	var path []Tag
+	for c := i; c != nil; c = c.parent {
+		path = append(path, c.Tag)
+	}
+	slices.Reverse(path)
+	return path
+}
+
+// Raw returns the original bytes of the IE in the input, which is recorded only when parsed
+// with Raw in ParseOptions. It is not updated when the IE is modified.
+func (i *IE) Raw() []byte {
+	return i.raw
+}
+
+// Offset returns the position of the IE from the beginning of the input, which is recorded only
+// when parsed with Raw in ParseOptions. It returns -1 if not recorded.
+func (i *IE) Offset() int {
+	if i.raw == nil {
+		return -1
+	}
+	return i.offset
+}
+
+// AddChild appends the children to the IE, and recomputes the Value and Length of
+// the IE and of its ancestors.
+func (i *IE) AddChild(children ...*IE) {
+	for _, c := range children {
+		c.parent = i
+	}
+	i.IE = append(i.IE, children...)
+	i.update()
+}
+
+// RemoveChild removes the child from the IE, and recomputes the Value and Length of
+// the IE and of its ancestors. It reports whether the child was found.
+func (i *IE) RemoveChild(child *IE) bool {
+	for n, c := range i.IE {
+		if c != child {
+			continue
+		}
+
+		i.IE = append(i.IE[:n:n], i.IE[n+1:]...)
+		c.parent = nil
+		if len(i.IE) == 0 {
+			i.Value = []byte{}
+			i.SetLength()
+		}
+		i.update()
+		return true
+	}
+	return false
+}
+
+// SetValue sets the Value of the IE, and recomputes the Length of the IE and
+// the Value and Length of its ancestors.
+//
+// The children of the IE are removed, as they no longer match the Value.
+func (i *IE) SetValue(v []byte) {
+	for _, c := range i.IE {
+		c.parent = nil
+	}
+	i.IE = nil
+	i.Value = v
+	i.SetLength()
+	if i.parent != nil {
+		i.parent.update()
+	}
+}
+
+// update recomputes the Value and Length of the IE and of its ancestors.
+func (i *IE) update() {
+	i.rebuild()
+	for p := i.parent; p != nil; p = p.parent {
+		p.refresh()
+	}
+}
+
+// Rebuild recomputes the Value and Length of the IE from its children bottom-up, if any,
+// which should be called after modifying the children directly, not with AddChild,
+// RemoveChild or SetValue. MarshalBinary encodes the Value as it is, without rebuilding it.
+func (i *IE) Rebuild() {
+	i.rebuild()
+}
+
+// rebuild does the actual work of Rebuild.
+func (i *IE) rebuild() {
+	if len(i.IE) == 0 {
+		return
+	}
+	for _, c := range i.IE {
+		c.rebuild()
+	}
+	i.refresh()
+}
+
+// refresh recomputes the Value and Length of the IE from the encodings of its children.
+func (i *IE) refresh() {
+	if len(i.IE) == 0 {
+		return
+	}
+
+	var v []byte
+	for _, c := range i.IE {
+		v, _ = appendBinary(v, c)
+	}
+	i.Value = v
+	i.SetLength()
+}
+
+// FindAll returns the descendants of the IE that match the path of tags, in order of appearance.
+// The first tag in path is matched against the children of the IE, the second one against
+// their children, and so on, e.g., FindAll(0x6c, 0xa1, 0x02) on a Begin returns the Operation Codes
+// of all the Invokes.
+func (i *IE) FindAll(path ...Tag) []*IE {
+	if len(path) == 0 {
+		return nil
+	}
+
+	var found []*IE
+	for _, c := range i.children() {
+		if c.Tag != path[0] {
+			continue
+		}
+		if len(path) == 1 {
+			found = append(found, c)
+			continue
+		}
+		found = append(found, c.FindAll(path[1:]...)...)
+	}
+	return found
+}
+
+// FindFirst returns the first descendant of the IE that matches the path of tags, or nil if none matches.
+//
+// The path is interpreted in the same way as FindAll.
+func (i *IE) FindFirst(path ...Tag) *IE {
+	if len(path) == 0 {
+		return nil
+	}
+
+	for _, c := range i.children() {
+		if c.Tag != path[0] {
+			continue
+		}
+		if len(path) == 1 {
+			return c
+		}
+		if found := c.FindFirst(path[1:]...); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// Walk calls fn for the IE and each of its descendants in depth-first order, with the path of tags
+// from the IE to the one visited, including its own. The path is reused across the calls, and must
+// be copied to be retained.
+//
+// If fn returns SkipChildren, the children of the IE visited are skipped.
+// If fn returns any other error, the walk stops and the error is returned.
+func (i *IE) Walk(fn func(path []Tag, ie *IE) error) error {
+	err := i.walk(nil, fn)
+	if err == SkipChildren {
+		return nil
+	}
+	return err
+}
+
+// walk calls fn for the IE and its descendants, under the path of the ancestors.
+func (i *IE) walk(path []Tag, fn func(path []Tag, ie *IE) error) error {
+	path = append(path, i.Tag)
+	if err := fn(path, i); err != nil {
+		return err
+	}
+
+	for _, c := range i.children() {
+		if err := c.walk(path, fn); err != nil && err != SkipChildren {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestMutableTree(t *testing.T) {
+	root := tcap.NewIE(tcap.NewUniversalConstructorTag(16), nil)
+	inner := tcap.NewIE(tcap.NewContextSpecificConstructorTag(1), nil)
+	leaf := tcap.NewIE(tcap.NewUniversalPrimitiveTag(2), []byte{0x05})
+
+	root.AddChild(inner)
+	inner.AddChild(leaf)
+	if got, want := root.Length, 5; got != want {
+		t.Errorf("Length after AddChild: got %d, want %d", got, want)
+	}
+
+	leaf.SetValue([]byte{0x01, 0x00})
+	b, err := root.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "SetValue", b, []byte{0x30, 0x06, 0xa1, 0x04, 0x02, 0x02, 0x01, 0x00})
+
+	if !inner.RemoveChild(leaf) {
+		t.Error("RemoveChild: not found")
+	}
+	if inner.RemoveChild(leaf) {
+		t.Error("RemoveChild: removed twice")
+	}
+	b, err = root.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "RemoveChild", b, []byte{0x30, 0x02, 0xa1, 0x00})
+
+	// the children modified directly are picked up by Rebuild.
+	parsed, err := tcap.ParseAsBER([]byte{0x30, 0x05, 0xa1, 0x03, 0x02, 0x01, 0x05})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed[0].IE[0].IE[0].Value = []byte{0x01, 0x00}
+	parsed[0].IE[0].IE[0].SetLength()
+	b, err = parsed[0].MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "not rebuilt", b, []byte{0x30, 0x05, 0xa1, 0x03, 0x02, 0x01, 0x05})
+
+	parsed[0].Rebuild()
+	b, err = parsed[0].MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "parsed", b, []byte{0x30, 0x06, 0xa1, 0x04, 0x02, 0x02, 0x01, 0x00})
+}
+
+func TestParents(t *testing.T) {
+	b, err := tcap.NewBeginInvoke(0x11111111, 1, 45, []byte{0x80, 0x01, 0xaa}).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ies, err := tcap.ParseOptions{Parents: true}.ParseAsBER(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Begin / Component Portion / Invoke / Parameter / [0]
+	invoke := ies[0].IE[1].IE[0]
+	leaf := invoke.IE[2].IE[0]
+	verify.Values(t, "Path", leaf.Path(), []tcap.Tag{0x62, 0x6c, 0xa1, 0x30, 0x80})
+	if leaf.Parent().Parent() != invoke {
+		t.Error("Parent: not the Invoke")
+	}
+	if leaf.Root() != ies[0] {
+		t.Error("Root: not the Begin")
+	}
+
+	plain, err := tcap.ParseAsBER(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := plain[0].IE[1].Parent(); got != nil {
+		t.Errorf("Parent without option: got %v, want nil", got)
+	}
+}
+
+func TestFind(t *testing.T) {
+	m := tcap.NewBeginInvoke(0x11111111, 1, 45, nil)
+	m.Components = tcap.NewComponents(
+		tcap.NewInvoke(1, -1, 45, true, nil),
+		tcap.NewInvoke(2, -1, 46, true, nil),
+	)
+	m.SetLength()
+	b, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ies, err := tcap.ParseAsBER(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var opCodes []byte
+	for _, i := range ies[0].FindAll(0x6c, 0xa1, 0x02) {
+		opCodes = append(opCodes, i.Value...)
+	}
+	// Invoke ID and Operation Code are both INTEGER.
+	verify.Values(t, "FindAll", opCodes, []byte{1, 45, 2, 46})
+
+	if got := ies[0].FindFirst(0x6c, 0xa1); got == nil || got.IE[0].Value[0] != 1 {
+		t.Errorf("FindFirst: got %v", got)
+	}
+	if got := ies[0].FindFirst(0x6c, 0xa2); got != nil {
+		t.Errorf("FindFirst: got %v, want nil", got)
+	}
+}
+
+func TestWalk(t *testing.T) {
+	ies, err := tcap.ParseAsBER([]byte{0x30, 0x08, 0xa1, 0x03, 0x02, 0x01, 0x05, 0x04, 0x01, 0xaa})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var visited []string
+	if err := ies[0].Walk(func(path []tcap.Tag, ie *tcap.IE) error {
+		visited = append(visited, fmt.Sprintf("%x", path))
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "all", visited, []string{"[30]", "[30 a1]", "[30 a1 2]", "[30 4]"})
+
+	visited = nil
+	if err := ies[0].Walk(func(path []tcap.Tag, ie *tcap.IE) error {
+		visited = append(visited, fmt.Sprintf("%x", ie.Tag))
+		if ie.Tag == 0xa1 {
+			return tcap.SkipChildren
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "SkipChildren", visited, []string{"30", "a1", "4"})
+
+	stop := errors.New("stop")
+	visited = nil
+	if err := ies[0].Walk(func(path []tcap.Tag, ie *tcap.IE) error {
+		visited = append(visited, fmt.Sprintf("%x", ie.Tag))
+		if ie.Tag == 0x02 {
+			return stop
+		}
+		return nil
+	}); err != stop {
+		t.Errorf("stop: got %v, want %v", err, stop)
+	}
+	verify.Values(t, "stop", visited, []string{"30", "a1", "2"})
+}
@@ -0,0 +1,167 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrNoTransactionPortion is returned when the message given has no Transaction Portion.
+var ErrNoTransactionPortion = errors.New("tcap: no transaction portion")
+
+// TransactionState is the state of a transaction (Q.774 Transaction State Machine).
+type TransactionState uint8
+
+// TransactionState definitions.
+const (
+	StateIdle TransactionState = iota
+	StateInitiationSent
+	StateInitiationReceived
+	StateActive
+)
+
+// String returns the name of TransactionState as in Q.774.
+func (s TransactionState) String() string {
+	switch s {
+	case StateIdle:
+		return "idle"
+	case StateInitiationSent:
+		return "initiation-sent"
+	case StateInitiationReceived:
+		return "initiation-received"
+	case StateActive:
+		return "active"
+	}
+	return fmt.Sprintf("%d", uint8(s))
+}
+
+// TransactionStateMachine tracks the state of a transaction by the messages sent and received,
+// and rejects the ones not allowed in the state with TransitionError, leaving the state unchanged.
+//
+// The zero value is a transaction in StateIdle. It is safe for concurrent use.
+type TransactionStateMachine struct {
+	mu    sync.Mutex
+	state TransactionState
+}
+
+// NewTransactionStateMachine creates a new TransactionStateMachine in StateIdle.
+func NewTransactionStateMachine() *TransactionStateMachine {
+	return &TransactionStateMachine{}
+}
+
+// State returns the current state.
+func (m *TransactionStateMachine) State() TransactionState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.state
+}
+
+// transition moves to next if the current state is one of from, i.e., the event is allowed.
+func (m *TransactionStateMachine) transition(event string, next TransactionState, from ...TransactionState) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, s := range from {
+		if m.state == s {
+			m.state = next
+			return nil
+		}
+	}
+	return &TransitionError{State: m.state, Event: event}
+}
+
+// SendBegin starts the transaction by the local user.
+func (m *TransactionStateMachine) SendBegin() error {
+	return m.transition("send-begin", StateInitiationSent, StateIdle)
+}
+
+// ReceivedBegin starts the transaction by the peer.
+func (m *TransactionStateMachine) ReceivedBegin() error {
+	return m.transition("received-begin", StateInitiationReceived, StateIdle)
+}
+
+// SendContinue confirms the transaction started by the peer, or continues the active one.
+func (m *TransactionStateMachine) SendContinue() error {
+	return m.transition("send-continue", StateActive, StateInitiationReceived, StateActive)
+}
+
+// ReceivedContinue confirms the transaction started locally, or continues the active one.
+func (m *TransactionStateMachine) ReceivedContinue() error {
+	return m.transition("received-continue", StateActive, StateInitiationSent, StateActive)
+}
+
+// SendEnd ends the transaction by the local user with End.
+func (m *TransactionStateMachine) SendEnd() error {
+	return m.transition("send-end", StateIdle, StateInitiationReceived, StateActive)
+}
+
+// ReceivedEnd ends the transaction by the peer.
+func (m *TransactionStateMachine) ReceivedEnd() error {
+	return m.transition("received-end", StateIdle, StateInitiationSent, StateActive)
+}
+
+// SendAbort aborts the transaction by the local user or provider with Abort.
+//
+// In StateInitiationSent, where the peer does not know the transaction yet, Terminate should
+// be used instead, as no Abort can be sent.
+func (m *TransactionStateMachine) SendAbort() error {
+	return m.transition("send-abort", StateIdle, StateInitiationReceived, StateActive)
+}
+
+// ReceivedAbort aborts the transaction by the peer.
+func (m *TransactionStateMachine) ReceivedAbort() error {
+	return m.transition("received-abort", StateIdle, StateInitiationSent, StateActive)
+}
+
+// Terminate ends the transaction locally without sending any message, e.g., the prearranged end
+// or the expiry of the timer. It is allowed in all the states but StateIdle.
+func (m *TransactionStateMachine) Terminate() error {
+	return m.transition("terminate", StateIdle, StateInitiationSent, StateInitiationReceived, StateActive)
+}
+
+// Send applies the event of the message to be sent, i.e., SendBegin, SendContinue, SendEnd or SendAbort.
+// Unidirectional is allowed in any state, as it is outside of the transactions.
+func (m *TransactionStateMachine) Send(t *TCAP) error {
+	if t == nil || t.Transaction == nil {
+		return ErrNoTransactionPortion
+	}
+	switch t.Transaction.Type.Code() {
+	case Unidirectional:
+		return nil
+	case Begin:
+		return m.SendBegin()
+	case Continue:
+		return m.SendContinue()
+	case End:
+		return m.SendEnd()
+	case Abort:
+		return m.SendAbort()
+	}
+	return &InvalidCodeError{Code: t.Transaction.Type.Code()}
+}
+
+// Receive applies the event of the message received, i.e., ReceivedBegin, ReceivedContinue,
+// ReceivedEnd or ReceivedAbort. Unidirectional is allowed in any state, as it is outside of the transactions.
+func (m *TransactionStateMachine) Receive(t *TCAP) error {
+	if t == nil || t.Transaction == nil {
+		return ErrNoTransactionPortion
+	}
+	switch t.Transaction.Type.Code() {
+	case Unidirectional:
+		return nil
+	case Begin:
+		return m.ReceivedBegin()
+	case Continue:
+		return m.ReceivedContinue()
+	case End:
+		return m.ReceivedEnd()
+	case Abort:
+		return m.ReceivedAbort()
+	}
+	return &InvalidCodeError{Code: t.Transaction.Type.Code()}
+}
@@ -0,0 +1,56 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestTransactionStateMachine(t *testing.T) {
+	m := tcap.NewTransactionStateMachine()
+	if err := m.Send(tcap.NewBeginMessage(tcap.WithOTID(1))); err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "after Begin", m.State(), tcap.StateInitiationSent)
+
+	var te *tcap.TransitionError
+	if err := m.SendContinue(); !errors.As(err, &te) {
+		t.Fatalf("SendContinue: got %v, want TransitionError", err)
+	}
+	verify.Values(t, "illegal", []any{te.State, m.State()}, []any{tcap.StateInitiationSent, tcap.StateInitiationSent})
+
+	if err := m.Receive(tcap.NewContinueMessage(tcap.WithOTID(2), tcap.WithDTID(1))); err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "after Continue", m.State(), tcap.StateActive)
+	if err := m.ReceivedAbort(); err != nil {
+		t.Fatal(err)
+	}
+	verify.Values(t, "after Abort", m.State(), tcap.StateIdle)
+
+	m = tcap.NewTransactionStateMachine()
+	for _, event := range []func() error{m.ReceivedBegin, m.SendContinue, m.ReceivedContinue, m.SendEnd} {
+		if err := event(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := m.ReceivedEnd(); !errors.As(err, &te) {
+		t.Errorf("ReceivedEnd in idle: got %v, want TransitionError", err)
+	}
+	if err := m.Terminate(); err == nil {
+		t.Error("Terminate in idle: got no error")
+	}
+
+	if err := m.Send(&tcap.TCAP{}); !errors.Is(err, tcap.ErrNoTransactionPortion) {
+		t.Errorf("Send without Transaction: got %v, want %v", err, tcap.ErrNoTransactionPortion)
+	}
+	if err := m.Receive(nil); !errors.Is(err, tcap.ErrNoTransactionPortion) {
+		t.Errorf("Receive nil: got %v, want %v", err, tcap.ErrNoTransactionPortion)
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+// InvokeComponent is the decoded Invoke.
+type InvokeComponent struct {
+	InvokeID int
+
+	// LinkedID is the Linked ID, or nil if absent.
+	LinkedID *int
+
+	// OpCode is the local Operation Code, or zero if it is global, which is in GlobalOpCode.
+	OpCode       int64
+	GlobalOpCode OID
+
+	// Parameter is the contents of the Parameter, like the one given to NewInvoke, or nil if absent.
+	Parameter []byte
+
+	Component *Component
+}
+
+// ReturnResultComponent is the decoded ReturnResultLast or ReturnResultNotLast.
+type ReturnResultComponent struct {
+	InvokeID int
+	Last     bool
+
+	// OpCode and GlobalOpCode are the same as InvokeComponent, and both are zero if the result is absent.
+	OpCode       int64
+	GlobalOpCode OID
+
+	// Parameter is the contents of the Parameter, like the one given to NewReturnResult, or nil if absent.
+	Parameter []byte
+
+	Component *Component
+}
+
+// ReturnErrorComponent is the decoded ReturnError.
+type ReturnErrorComponent struct {
+	InvokeID int
+	Code     ErrorCode
+
+	// Parameter is the contents of the Parameter, like the one given to NewReturnError, or nil if absent.
+	Parameter []byte
+
+	Component *Component
+}
+
+// RejectComponent is the decoded Reject.
+type RejectComponent struct {
+	// InvokeID is nil if the Invoke ID is not derivable, i.e., NULL.
+	InvokeID *int
+	Problem  Problem
+
+	Component *Component
+}
+
+// Invokes returns the Invokes in the Component Portion, in order.
+func (t *TCAP) Invokes() ([]*InvokeComponent, error) {
+	var invokes []*InvokeComponent
+	for c := range t.AllComponents() {
+		if c.Type.Code() != Invoke {
+			continue
+		}
+		id, err := c.invokeID()
+		if err != nil {
+			return nil, err
+		}
+		inv := &InvokeComponent{InvokeID: id, Parameter: c.parameter(), Component: c}
+		if lk, ok := c.LinkedInvokeID(); ok {
+			inv.LinkedID = &lk
+		}
+		if inv.OpCode, inv.GlobalOpCode, err = decodeOpCode(c.OperationCode); err != nil {
+			return nil, err
+		}
+		invokes = append(invokes, inv)
+	}
+	return invokes, nil
+}
+
+// ReturnResults returns the ReturnResultLast and ReturnResultNotLast in the Component Portion, in order.
+func (t *TCAP) ReturnResults() ([]*ReturnResultComponent, error) {
+	var results []*ReturnResultComponent
+	for c := range t.AllComponents() {
+		code := c.Type.Code()
+		if code != ReturnResultLast && code != ReturnResultNotLast {
+			continue
+		}
+		id, err := c.invokeID()
+		if err != nil {
+			return nil, err
+		}
+		res := &ReturnResultComponent{InvokeID: id, Last: code == ReturnResultLast, Parameter: c.parameter(), Component: c}
+		if c.OperationCode != nil {
+			if res.OpCode, res.GlobalOpCode, err = decodeOpCode(c.OperationCode); err != nil {
+				return nil, err
+			}
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// ReturnErrors returns the ReturnErrors in the Component Portion, in order.
+func (t *TCAP) ReturnErrors() ([]*ReturnErrorComponent, error) {
+	var errs []*ReturnErrorComponent
+	for c := range t.AllComponents() {
+		if c.Type.Code() != ReturnError {
+			continue
+		}
+		id, err := c.invokeID()
+		if err != nil {
+			return nil, err
+		}
+		code, err := c.ErrCode()
+		if err != nil {
+			return nil, err
+		}
+		errs = append(errs, &ReturnErrorComponent{InvokeID: id, Code: code, Parameter: c.parameter(), Component: c})
+	}
+	return errs, nil
+}
+
+// Rejects returns the Rejects in the Component Portion, in order.
+func (t *TCAP) Rejects() ([]*RejectComponent, error) {
+	var rejects []*RejectComponent
+	for c := range t.AllComponents() {
+		if c.Type.Code() != Reject {
+			continue
+		}
+		p, err := c.Problem()
+		if err != nil {
+			return nil, err
+		}
+		rej := &RejectComponent{Problem: p, Component: c}
+		if c.IsInvokeIDDerivable() {
+			id, err := c.invokeID()
+			if err != nil {
+				return nil, err
+			}
+			rej.InvokeID = &id
+		}
+		rejects = append(rejects, rej)
+	}
+	return rejects, nil
+}
+
+// invokeID returns the Invoke ID decoded as an INTEGER.
+func (c *Component) invokeID() (int, error) {
+	if c.InvokeID == nil {
+		return 0, ErrEmptyValue
+	}
+	v, err := c.InvokeID.Int64()
+	if err != nil {
+		return 0, err
+	}
+	return int(v), nil
+}
+
+// parameter returns the contents of the Parameter, or nil if absent.
+func (c *Component) parameter() []byte {
+	if c.Parameter == nil {
+		return nil
+	}
+	return c.Parameter.Value
+}
+
+// operationCode is the CHOICE of the Operation Code, local (INTEGER) or global (OBJECT IDENTIFIER).
+var operationCode = NewChoice[OperationCode]().
+	Register(NewUniversalPrimitiveTag(2), func(i *IE) (OperationCode, error) {
+		v, err := i.Int64()
+		return LocalOperationCode(v), err
+	}).
+	Register(NewUniversalPrimitiveTag(6), func(i *IE) (OperationCode, error) {
+		o, err := i.OID()
+		return GlobalOperationCode(o), err
+	})
+
+// decodeOpCode decodes the Operation Code as local (INTEGER) or global (OBJECT IDENTIFIER).
+func decodeOpCode(i *IE) (int64, OID, error) {
+	if i == nil {
+		return 0, nil, ErrEmptyValue
+	}
+	c, err := operationCode.Decode(i)
+	return c.Local, c.Global, err
+}
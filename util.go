@@ -1,6 +1,14 @@
 package tcap
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// ErrLengthTooLong is returned when the length field of an element exceeds four octets.
+var ErrLengthTooLong = errors.New("tcap: length field too long")
 
 // UnmarshalAsn1ElementLength returns the actual length and the number of bytes
 // occupied by the length field itself (including the header byte).
@@ -22,6 +30,10 @@ func UnmarshalAsn1ElementLength(b []byte) (int, int, error) {
 		return -1, 0, fmt.Errorf("indefinite length not supported")
 	}
 
+	if numOctets > 4 {
+		return -1, 0, ErrLengthTooLong
+	}
+
 	if len(b) < 2+numOctets {
 		return -1, 0, fmt.Errorf("buffer too short for long-form length")
 	}
@@ -60,3 +72,36 @@ func MarshalAsn1ElementLength(length int) []byte {
 
 	return append([]byte{header}, valBytes...)
 }
+
+// headerLen returns the number of bytes occupied by the Tag and the Length field encoding
+// the given length.
+func headerLen(tag Tag, length int) int {
+	return tag.MarshalLen() + len(MarshalAsn1ElementLength(length))
+}
+
+// marshaler is implemented by the types that put their byte sequence in a byte array of MarshalLen.
+type marshaler interface {
+	MarshalLen() int
+	MarshalTo(b []byte) error
+}
+
+// appendBinary appends the byte sequence generated from m to b.
+func appendBinary(b []byte, m marshaler) ([]byte, error) {
+	l, n := len(b), m.MarshalLen()
+	b = slices.Grow(b, n)[:l+n]
+	clear(b[l:])
+	if err := m.MarshalTo(b[l:]); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// writeTo writes the byte sequence generated from m to w.
+func writeTo(w io.Writer, m marshaler) (int64, error) {
+	b, err := appendBinary(nil, m)
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(b)
+	return int64(n), err
+}
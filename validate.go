@@ -0,0 +1,163 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap
+
+import "fmt"
+
+// Violation is a structural rule of Q.773 that a TCAP message violates.
+type Violation struct {
+	// Field is the element violating the rule, e.g., "DestTransactionID" or "Component[1]".
+	Field string
+
+	// Reason describes the rule violated.
+	Reason string
+}
+
+// String returns Violation in human readable format.
+func (v Violation) String() string {
+	return v.Field + ": " + v.Reason
+}
+
+// violations collects the Violations found.
+type violations []Violation
+
+func (v *violations) add(field, format string, args ...any) {
+	*v = append(*v, Violation{Field: field, Reason: fmt.Sprintf(format, args...)})
+}
+
+// Validate checks the TCAP against the structural rules of Q.773, i.e., the mandatory and forbidden
+// Transaction IDs and portions for each message type, the DialoguePDU allowed in it, and the
+// types of Components. It returns a ValidationError with all the violations found, or nil.
+func (t *TCAP) Validate() error {
+	var v violations
+	tx := t.Transaction
+	if tx == nil {
+		v.add("Transaction", "missing")
+		return &ValidationError{Violations: v}
+	}
+
+	otid, dtid := tx.OrigTransactionID != nil, tx.DestTransactionID != nil
+	var pdus []int
+	switch tx.Type.Code() {
+	case Unidirectional:
+		v.forbid("OrigTransactionID", otid)
+		v.forbid("DestTransactionID", dtid)
+		if t.Components == nil || len(t.Components.Component) == 0 {
+			v.add("Components", "mandatory in Unidirectional")
+		}
+		pdus = []int{AUDT}
+	case Begin:
+		v.require("OrigTransactionID", otid)
+		v.forbid("DestTransactionID", dtid)
+		pdus = []int{AARQ}
+	case Continue:
+		v.require("OrigTransactionID", otid)
+		v.require("DestTransactionID", dtid)
+		pdus = []int{AARE}
+	case End:
+		v.forbid("OrigTransactionID", otid)
+		v.require("DestTransactionID", dtid)
+		pdus = []int{AARE}
+	case Abort:
+		v.forbid("OrigTransactionID", otid)
+		v.require("DestTransactionID", dtid)
+		if tx.PAbortCause != nil && t.Dialogue != nil {
+			v.add("PAbortCause", "not allowed with Dialogue Portion")
+		}
+		if t.Components != nil {
+			v.add("Components", "not allowed in Abort")
+		}
+		pdus = []int{AARE, ABRT}
+	default:
+		v.add("Transaction", "unknown message type %#x", uint32(tx.Type))
+	}
+
+	for _, field := range []struct {
+		name string
+		ie   *IE
+	}{{"OrigTransactionID", tx.OrigTransactionID}, {"DestTransactionID", tx.DestTransactionID}} {
+		if field.ie != nil && (len(field.ie.Value) < 1 || len(field.ie.Value) > 4) {
+			v.add(field.name, "length %d not in 1..4", len(field.ie.Value))
+		}
+	}
+
+	if d := t.Dialogue; d != nil && pdus != nil {
+		v.validateDialogue(d, tx.Type.Code() == Unidirectional, pdus)
+	}
+	if c := t.Components; c != nil {
+		v.validateComponents(c)
+	}
+
+	if len(v) == 0 {
+		return nil
+	}
+	return &ValidationError{Violations: v}
+}
+
+func (v *violations) require(field string, present bool) {
+	if !present {
+		v.add(field, "mandatory")
+	}
+}
+
+func (v *violations) forbid(field string, present bool) {
+	if present {
+		v.add(field, "not allowed")
+	}
+}
+
+// validateDialogue checks the Dialogue Portion has the DialoguePDU of the codes given.
+func (v *violations) validateDialogue(d *Dialogue, uni bool, codes []int) {
+	if d.IsUnidialogue() != uni {
+		v.add("Dialogue", "dialogue-as-id and unidialogue-as-id mismatch with message type")
+	}
+	if d.DialoguePDU == nil {
+		v.add("DialoguePDU", "missing")
+		return
+	}
+	code := d.DialoguePDU.Type.Code()
+	if code == ABRT {
+		if _, err := d.DialoguePDU.Source(); err != nil {
+			v.add("DialoguePDU.AbortSource", "mandatory dialogue-service-user or dialogue-service-provider")
+		}
+	}
+	for _, c := range codes {
+		if code == c {
+			return
+		}
+	}
+	if code == ABRT {
+		v.add("DialoguePDU", "ABRT only allowed in Abort")
+		return
+	}
+	v.add("DialoguePDU", "code %d not allowed", code)
+}
+
+// validateComponents checks the types and the Invoke IDs of the Components.
+func (v *violations) validateComponents(c *Components) {
+	if len(c.Component) == 0 {
+		v.add("Components", "empty Component Portion")
+	}
+	for n, comp := range c.Component {
+		field := fmt.Sprintf("Component[%d]", n)
+		switch comp.Type.Code() {
+		case Invoke:
+			v.require(field+".OperationCode", comp.OperationCode != nil)
+		case ReturnResultLast, ReturnResultNotLast:
+		case ReturnError:
+			v.require(field+".ErrorCode", comp.ErrorCode != nil)
+		case Reject:
+			v.require(field+".ProblemCode", comp.ProblemCode != nil)
+			// Invoke ID of Reject can be NULL.
+			continue
+		default:
+			v.add(field, "unknown component type %#x", uint32(comp.Type))
+			continue
+		}
+		if comp.InvokeID == nil || comp.InvokeID.Tag != NewUniversalPrimitiveTag(2) {
+			v.add(field+".InvokeID", "mandatory INTEGER")
+		}
+	}
+}
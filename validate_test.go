@@ -0,0 +1,54 @@
+// Copyright go-tcap authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package tcap_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/en-vee/go-tcap"
+	"github.com/pascaldekloe/goe/verify"
+)
+
+func TestValidate(t *testing.T) {
+	valid := []*tcap.TCAP{
+		tcap.NewBeginInvokeWithDialogue(0x11111111, tcap.DialogueAsID, tcap.NetworkUnstructuredSsContext, 2, 0, 59, nil),
+		tcap.NewContinueDialogue(0x22222222, 0x11111111, tcap.NetworkUnstructuredSsContext, 2),
+		tcap.NewEndReturnResult(0x11111111, 0, 59, true, nil),
+		tcap.NewUserAbort(0x11111111),
+		tcap.NewUnidirectionalInvoke(tcap.NetworkUnstructuredSsContext, 2, 0, 60, nil),
+	}
+	for _, m := range valid {
+		b, err := m.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed, err := tcap.Parse(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := parsed.Validate(); err != nil {
+			t.Errorf("%s: %v", parsed.Transaction.MessageTypeString(), err)
+		}
+	}
+
+	// Continue without DTID, with AARQ and a Component of unknown type.
+	m := tcap.NewContinueMessage(
+		tcap.WithOTID(0x22222222),
+		tcap.WithDialoguePDU(tcap.NewAARQ(1, tcap.NetworkUnstructuredSsContext, 2)),
+		tcap.WithComponents(tcap.NewInvoke(0, -1, 59, true, nil), &tcap.Component{Type: 0xa9}),
+	)
+	m.Transaction.DestTransactionID = nil
+	err := m.Validate()
+	var ve *tcap.ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("got %v, want ValidationError", err)
+	}
+	var fields []string
+	for _, v := range ve.Violations {
+		fields = append(fields, v.Field)
+	}
+	verify.Values(t, "fields", fields, []string{"DestTransactionID", "DialoguePDU", "Component[1]"})
+}